@@ -0,0 +1,218 @@
+package minty
+
+import "io"
+
+// Page assets
+//
+// Components often need a stylesheet, a script or a meta tag on the page.
+// Instead of asking callers to add them to <head> by hand, a component
+// registers them with b.Require while it is being built:
+//
+//	func DatePicker(b *mi.Builder, name string) mi.Node {
+//	    return b.Div(
+//	        b.Require(mi.StylesheetAsset("/static/datepicker.css"),
+//	            mi.ScriptAsset("/static/datepicker.js")),
+//	        b.Input(mi.Type("date"), mi.Name(name)),
+//	    )
+//	}
+//
+// Document places a slot at the end of <head> and at the end of <body>.
+// Each registered asset is written once, in registration order, into the
+// slot for its position, however many components asked for it. Without a
+// Document (an HTMX fragment, say) the first b.Require of an asset renders it
+// in place and later uses of the same asset render nothing.
+//
+// Only builders created for a render (Render, RenderWithContext,
+// RenderStream, NewBuilder) keep a registry. With the global B, which is
+// shared between goroutines, b.Require always renders the asset in place.
+
+// AssetPosition is where in the document an asset is emitted.
+type AssetPosition int
+
+const (
+	// AssetHead emits the asset at the end of <head>.
+	AssetHead AssetPosition = iota
+	// AssetBodyEnd emits the asset just before </body>.
+	AssetBodyEnd
+)
+
+// Asset is a page-level dependency identified by Key. Two assets with the
+// same key are the same asset; the first one registered wins.
+type Asset struct {
+	Key      string
+	Position AssetPosition
+	Content  H
+}
+
+// InHead returns a copy of the asset emitted in <head>.
+func (a Asset) InHead() Asset {
+	a.Position = AssetHead
+	return a
+}
+
+// InBody returns a copy of the asset emitted at the end of <body>.
+func (a Asset) InBody() Asset {
+	a.Position = AssetBodyEnd
+	return a
+}
+
+// StylesheetAsset links an external stylesheet in <head>.
+func StylesheetAsset(href string, attrs ...Attribute) Asset {
+	return Asset{
+		Key:      "link:" + href,
+		Position: AssetHead,
+		Content: func(b *Builder) Node {
+			return b.Link(append([]Attribute{Rel("stylesheet"), Href(href)}, attrs...)...)
+		},
+	}
+}
+
+// ScriptAsset loads an external script at the end of <body>.
+func ScriptAsset(src string, attrs ...interface{}) Asset {
+	return Asset{
+		Key:      "script:" + src,
+		Position: AssetBodyEnd,
+		Content: func(b *Builder) Node {
+			return b.Script(append([]interface{}{Src(src)}, attrs...)...)
+		},
+	}
+}
+
+// InlineScriptAsset embeds a script, identified by key, at the end of <body>.
+// The code is written verbatim and must not contain "</script".
+func InlineScriptAsset(key, js string) Asset {
+	return Asset{
+		Key:      "inline-script:" + key,
+		Position: AssetBodyEnd,
+		Content: func(b *Builder) Node {
+			return b.Script(Raw(js))
+		},
+	}
+}
+
+// InlineStyleAsset embeds a stylesheet, identified by key, in <head>.
+// The CSS is written verbatim and must not contain "</style".
+func InlineStyleAsset(key, css string) Asset {
+	return Asset{
+		Key:      "inline-style:" + key,
+		Position: AssetHead,
+		Content: func(b *Builder) Node {
+			return b.Style(Raw(css))
+		},
+	}
+}
+
+// MetaAsset adds a named <meta> tag to <head>.
+func MetaAsset(name, content string) Asset {
+	return Asset{
+		Key:      "meta:" + name,
+		Position: AssetHead,
+		Content: func(b *Builder) Node {
+			return b.Meta(Name(name), Content(content))
+		},
+	}
+}
+
+// HeadAsset registers arbitrary content for <head> under key.
+func HeadAsset(key string, content H) Asset {
+	return Asset{Key: key, Position: AssetHead, Content: content}
+}
+
+// BodyAsset registers arbitrary content for the end of <body> under key.
+func BodyAsset(key string, content H) Asset {
+	return Asset{Key: key, Position: AssetBodyEnd, Content: content}
+}
+
+// assetRegistry collects the assets registered while building one page.
+type assetRegistry struct {
+	assets  []Asset
+	seen    map[string]bool
+	slotted bool
+}
+
+// add registers an asset and reports whether it was new.
+func (r *assetRegistry) add(a Asset) bool {
+	if r.seen == nil {
+		r.seen = make(map[string]bool)
+	}
+	if r.seen[a.Key] {
+		return false
+	}
+	r.seen[a.Key] = true
+	r.assets = append(r.assets, a)
+	return true
+}
+
+// Require registers assets needed by the component being built. The returned
+// node renders nothing when the page has asset slots (see Document) and
+// otherwise renders the assets in place, once per page.
+func (b *Builder) Require(assets ...Asset) Node {
+	if b.assets == nil {
+		nodes := make([]Node, len(assets))
+		for i, a := range assets {
+			nodes[i] = a.Content(b)
+		}
+		return NewFragment(nodes...)
+	}
+	var fresh []Asset
+	for _, a := range assets {
+		if b.assets.add(a) {
+			fresh = append(fresh, a)
+		}
+	}
+	return &assetRequire{b: b, assets: fresh}
+}
+
+// AssetSlot returns the node that emits every asset registered for pos.
+// Document places both slots automatically; use AssetSlot when building
+// <html> by hand. Assets registered after the slot is built are still
+// included, because the slot is resolved when it is rendered.
+func (b *Builder) AssetSlot(pos AssetPosition) Node {
+	if b.assets == nil {
+		return NewFragment()
+	}
+	b.assets.slotted = true
+	return &assetSlot{b: b, pos: pos}
+}
+
+// assetRequire renders assets in place when the page has no slots.
+type assetRequire struct {
+	b      *Builder
+	assets []Asset
+}
+
+func (u *assetRequire) Render(w io.Writer) error {
+	if c := compilerFor(w); c != nil {
+		return c.live(func(b *Builder) Node { return b.Require(u.assets...) })
+	}
+	if u.b.assets.slotted {
+		return nil
+	}
+	for _, a := range u.assets {
+		if err := a.Content(u.b).Render(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assetSlot renders the registered assets for one position.
+type assetSlot struct {
+	b   *Builder
+	pos AssetPosition
+}
+
+func (s *assetSlot) Render(w io.Writer) error {
+	if c := compilerFor(w); c != nil {
+		return c.live(func(b *Builder) Node { return b.AssetSlot(s.pos) })
+	}
+	for _, a := range s.b.assets.assets {
+		if a.Position != s.pos {
+			continue
+		}
+		if err := a.Content(s.b).Render(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
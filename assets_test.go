@@ -0,0 +1,85 @@
+package minty
+
+import (
+	"strings"
+	"testing"
+)
+
+func widget(b *Builder) Node {
+	return b.Div(Class("widget"),
+		b.Require(
+			StylesheetAsset("/static/widget.css"),
+			ScriptAsset("/static/widget.js"),
+		),
+		"w",
+	)
+}
+
+func TestRequireEmitsAssetsOnceInDocument(t *testing.T) {
+	page := func(b *Builder) Node {
+		return Document("Assets", []Node{b.Meta(Name("author"), Content("me"))},
+			b.Body(widget(b), widget(b), widget(b)),
+		)(b)
+	}
+	html := RenderToString(page)
+
+	if n := strings.Count(html, "widget.css"); n != 1 {
+		t.Errorf("stylesheet emitted %d times, want 1:\n%s", n, html)
+	}
+	if n := strings.Count(html, "widget.js"); n != 1 {
+		t.Errorf("script emitted %d times, want 1:\n%s", n, html)
+	}
+
+	css := strings.Index(html, `<link rel="stylesheet" href="/static/widget.css" />`)
+	author := strings.Index(html, `name="author"`)
+	headEnd := strings.Index(html, "</head>")
+	if css < author || css > headEnd {
+		t.Errorf("stylesheet should follow the head nodes inside <head>:\n%s", html)
+	}
+	if !strings.Contains(html, `<script src="/static/widget.js"></script></body>`) {
+		t.Errorf("script should be emitted just before </body>:\n%s", html)
+	}
+}
+
+func TestRequireRendersInPlaceWithoutDocument(t *testing.T) {
+	fragment := func(b *Builder) Node {
+		return b.Section(widget(b), widget(b))
+	}
+	html := RenderToString(fragment)
+
+	want := `<section><div class="widget"><link rel="stylesheet" href="/static/widget.css" />` +
+		`<script src="/static/widget.js"></script>w</div><div class="widget">w</div></section>`
+	if html != want {
+		t.Errorf("unexpected fragment output:\ngot:  %s\nwant: %s", html, want)
+	}
+}
+
+func TestRequireWithGlobalBuilderRendersEveryTime(t *testing.T) {
+	var sb strings.Builder
+	if err := NewFragment(widget(B), widget(B)).Render(&sb); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(sb.String(), "widget.js"); n != 2 {
+		t.Errorf("expected inline assets for each use with B, got %d", n)
+	}
+	if slot := B.AssetSlot(AssetHead); RenderToString(func(*Builder) Node { return slot }) != "" {
+		t.Error("asset slot on the global builder should render nothing")
+	}
+}
+
+func TestAssetSlotIncludesLaterRegistrations(t *testing.T) {
+	page := func(b *Builder) Node {
+		return b.Html(
+			b.Head(b.AssetSlot(AssetHead)),
+			b.Body(
+				b.Require(MetaAsset("theme-color", "#fff"), InlineScriptAsset("boot", "boot()").InHead()),
+				b.AssetSlot(AssetBodyEnd),
+			),
+		)
+	}
+	html := RenderToString(page)
+	want := `<html><head><meta name="theme-color" content="#fff" /><script>boot()</script></head><body></body></html>`
+	if html != want {
+		t.Errorf("unexpected output:\ngot:  %s\nwant: %s", html, want)
+	}
+}
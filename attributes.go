@@ -516,8 +516,10 @@ func HtmxTrigger(trigger string) Attribute {
 // HtmxOn creates an hx-on:* attribute to specify event handlers. The hx-on*
 // attributes allow you to embed scripts inline to respond to events directly on
 // an element; similar to the onevent properties found in HTML, such as onClick.
-func HtmxOn(event, script string) Attribute {
-	return StringAttribute{Name: "hx-on:" + event, Value: script}
+// The script is TrustedJS: never build it from user input. Inline handlers
+// are dropped when rendering under CSPStrictHandlers.
+func HtmxOn(event string, script TrustedJS) Attribute {
+	return TrustedAttribute{Name: "hx-on:" + event, Value: string(script)}
 }
 
 // HTMX Indicators and Feedback
@@ -673,7 +675,7 @@ func HxSwap(strategy string) Attribute { return HtmxSwap(strategy) }
 func HxTrigger(trigger string) Attribute { return HtmxTrigger(trigger) }
 
 // HxOn is an alias for HtmxOn
-func HxOn(event, script string) Attribute { return HtmxOn(event, script) }
+func HxOn(event string, script TrustedJS) Attribute { return HtmxOn(event, script) }
 
 // HxIndicator is an alias for HtmxIndicator
 func HxIndicator(selector string) Attribute { return HtmxIndicator(selector) }
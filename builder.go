@@ -1,19 +1,24 @@
 package minty
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 )
 
 // Builder provides methods for creating HTML elements with the Minty pattern.
-type Builder struct{}
+// A builder may carry a render context; see NewBuilder.
+type Builder struct {
+	ctx    context.Context
+	assets *assetRegistry
+	ids    *idGenerator
+}
 
 // createElement creates an element with the given tag and processes mixed arguments.
 func (b *Builder) createElement(tag string, selfClosing bool, args ...interface{}) Node {
 	element := &Element{
 		Tag:         tag,
 		SelfClosing: selfClosing,
-		Attributes:  make(map[string]string),
 		Children:    []Node{},
 	}
 
@@ -39,23 +44,37 @@ func (b *Builder) createElement(tag string, selfClosing bool, args ...interface{
 		case Node:
 			if !selfClosing {
 				element.Children = append(element.Children, v)
+			} else {
+				element.dropped(v)
 			}
 		case string:
 			if !selfClosing {
 				element.Children = append(element.Children, &TextNode{Content: v})
+			} else {
+				element.dropped(v)
 			}
 		case int:
 			if !selfClosing {
 				element.Children = append(element.Children, &TextNode{Content: strconv.Itoa(v)})
+			} else {
+				element.dropped(v)
 			}
 		case fmt.Stringer:
 			if !selfClosing {
 				element.Children = append(element.Children, &TextNode{Content: v.String()})
+			} else {
+				element.dropped(v)
 			}
 		default:
-			if !selfClosing {
-				element.Children = append(element.Children, &TextNode{Content: fmt.Sprintf("%v", v)})
+			if selfClosing {
+				element.dropped(v)
+				continue
+			}
+			text := fmt.Sprintf("%v", v)
+			if !isScalar(v) {
+				element.stringified(v, text)
 			}
+			element.Children = append(element.Children, &TextNode{Content: text})
 		}
 	}
 
@@ -126,14 +145,14 @@ func (b *Builder) Link(attrs ...Attribute) Node {
 	return b.createElement("link", true, args...)
 }
 
-// Script creates a <script> element.
+// Script creates a <script> element, carrying the request's CSP nonce if any.
 func (b *Builder) Script(children ...interface{}) Node {
-	return b.createElement("script", false, children...)
+	return b.withNonce(b.createElement("script", false, children...))
 }
 
-// Style creates a <style> element.
+// Style creates a <style> element, carrying the request's CSP nonce if any.
 func (b *Builder) Style(children ...interface{}) Node {
-	return b.createElement("style", false, children...)
+	return b.withNonce(b.createElement("style", false, children...))
 }
 
 // Base creates a <base> element (self-closing).
@@ -773,5 +792,14 @@ func (b *Builder) SvgText(children ...interface{}) Node {
 	return b.createElement("text", false, children...)
 }
 
+// Custom and foreign elements
+
+// Element creates an element with an arbitrary tag name, for custom
+// elements and for packages such as mintysvg that build foreign content.
+// Arguments are handled as for the other builder methods.
+func (b *Builder) Element(tag string, args ...interface{}) Node {
+	return b.createElement(tag, false, args...)
+}
+
 // Global builder instance using standard Minty alias pattern
 var B = &Builder{}
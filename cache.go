@@ -0,0 +1,484 @@
+package minty
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Fragment caching
+//
+// Cached wraps an expensive template so its output is built once and
+// replayed as raw HTML until it expires or is invalidated:
+//
+//	func SalesPanel(svc *Service) mi.H {
+//	    return mi.Cached("dashboard:sales", 5*time.Minute, func(b *mi.Builder) mi.Node {
+//	        return salesTable(b, svc.RecentOrders(50))
+//	    }, mi.CacheTags("orders"))
+//	}
+//
+//	svc.CreateOrder(...)
+//	mi.InvalidateTags("orders")
+//
+// The key must identify everything the output depends on, including
+// per-request values such as the locale or the current user. When several
+// requests miss the same key at once, one builds the fragment and the
+// others wait for its result.
+//
+// Assets registered with b.Require inside the fragment are recorded with
+// it and registered again on every hit. Ids from b.UID are prefixed with
+// the key and reserved on the page at every hit, so a fragment using them
+// can appear only once per page; rendering it twice fails with an error.
+//
+// Inline scripts and styles are rendered without the request's CSP nonce,
+// since a nonce must never be reused across responses; register them as
+// assets instead, or use CSP hashes for static ones. The hashes of a
+// fragment's inline blocks are recorded with it and reported to the
+// request's CSPCollector on every hit, as are the handlers dropped in
+// strict mode. A fragment built for a strict collector is rebuilt for a
+// request without one, and vice versa.
+
+// CacheEntry is a rendered fragment.
+type CacheEntry struct {
+	HTML []byte
+	Tags []string
+
+	// Assets required by the fragment. They hold templates and cannot be
+	// serialised; caches that store entries out of process may drop them,
+	// in which case require the assets outside the cached block.
+	Assets []Asset
+
+	// IDs handed out by b.UID inside the fragment. They are reserved on
+	// the page at every use, and a page that would repeat them fails.
+	IDs []string
+
+	// CSP hash sources of the fragment's inline scripts and styles, and
+	// the inline handlers dropped from it if it was built for a strict
+	// CSPCollector.
+	ScriptHashes []string
+	StyleHashes  []string
+	Violations   []CSPViolation
+	StrictCSP    bool
+}
+
+// FragmentCache stores rendered fragments for Cached. Implementations must
+// be safe for concurrent use and comparable, as pointer types are.
+type FragmentCache interface {
+	// Get returns the entry stored under key, if present and not expired.
+	Get(key string) (*CacheEntry, bool)
+
+	// Set stores an entry for ttl; a ttl of zero or less never expires.
+	Set(key string, entry *CacheEntry, ttl time.Duration)
+
+	// Delete removes the entry stored under key.
+	Delete(key string)
+
+	// InvalidateTags removes every entry carrying any of the tags.
+	// Implementations should call RecordInvalidation first, so that
+	// fragments whose build overlaps the call are not stored afterwards.
+	InvalidateTags(tags ...string)
+}
+
+// DefaultCacheSize is the capacity of the default fragment cache.
+const DefaultCacheSize = 1024
+
+var (
+	fragmentCacheMu sync.RWMutex
+	fragmentCache   FragmentCache = NewLRUCache(DefaultCacheSize)
+)
+
+// SetFragmentCache replaces the cache used by Cached when no InCache
+// option is given.
+func SetFragmentCache(c FragmentCache) {
+	fragmentCacheMu.Lock()
+	defer fragmentCacheMu.Unlock()
+	fragmentCache = c
+}
+
+// DefaultFragmentCache returns the cache used by Cached by default.
+func DefaultFragmentCache() FragmentCache {
+	fragmentCacheMu.RLock()
+	defer fragmentCacheMu.RUnlock()
+	return fragmentCache
+}
+
+// InvalidateTags removes the entries carrying any of the tags from the
+// default fragment cache.
+func InvalidateTags(tags ...string) {
+	DefaultFragmentCache().InvalidateTags(tags...)
+}
+
+// RecordInvalidation notes that entries carrying any of the tags are being
+// removed. Cached does not store a fragment whose build began before the
+// call, since its output may predate the change behind the invalidation.
+func RecordInvalidation(tags ...string) {
+	tagGenerations.bump(tags)
+}
+
+// generations counts the invalidations of each tag.
+type generations struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+var tagGenerations = &generations{counts: make(map[string]uint64)}
+
+func (g *generations) bump(tags []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, tag := range tags {
+		g.counts[tag]++
+	}
+}
+
+// snapshot returns the current count of each tag.
+func (g *generations) snapshot(tags []string) []uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	counts := make([]uint64, len(tags))
+	for i, tag := range tags {
+		counts[i] = g.counts[tag]
+	}
+	return counts
+}
+
+// changed reports whether any tag was invalidated since snapshot.
+func (g *generations) changed(tags []string, snapshot []uint64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, tag := range tags {
+		if g.counts[tag] != snapshot[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// CacheOptions configures Cached.
+type CacheOptions struct {
+	Tags  []string
+	Cache FragmentCache // defaults to DefaultFragmentCache()
+}
+
+// CacheOption configures CacheOptions.
+type CacheOption func(*CacheOptions)
+
+// CacheTags labels the fragment so InvalidateTags can remove it.
+func CacheTags(tags ...string) CacheOption {
+	return func(o *CacheOptions) {
+		o.Tags = append(o.Tags, tags...)
+	}
+}
+
+// InCache stores the fragment in c instead of the default cache.
+func InCache(c FragmentCache) CacheOption {
+	return func(o *CacheOptions) {
+		o.Cache = c
+	}
+}
+
+// Cached returns a template that renders template once per key and ttl
+// and replays the stored HTML afterwards. If building or rendering the
+// fragment fails, nothing is stored and the error is returned when the
+// page is rendered.
+func Cached(key string, ttl time.Duration, template H, opts ...CacheOption) H {
+	o := CacheOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(b *Builder) Node {
+		cache := o.Cache
+		if cache == nil {
+			cache = DefaultFragmentCache()
+		}
+		collector, _ := cspCollectorKey.From(b.Context())
+		strict := collector != nil && collector.Strict
+		if entry, ok := cache.Get(key); ok && entry.StrictCSP == strict {
+			return entry.node(b, key)
+		}
+
+		entry, err := cacheFlights.do(b.Context(), flightKey{cache, key, strict}, func() (*CacheEntry, error) {
+			// Another caller may have finished while this one queued
+			if entry, ok := cache.Get(key); ok && entry.StrictCSP == strict {
+				return entry, nil
+			}
+			generation := tagGenerations.snapshot(o.Tags)
+			entry, err := renderFragment(b, key, template, strict)
+			if err != nil {
+				return nil, err
+			}
+			entry.Tags = o.Tags
+			// A build that overlaps an invalidation of its tags may be
+			// stale: serve it to the callers waiting for it, but do not
+			// store it. Checking again after Set covers an invalidation
+			// that removed entries just before this one was stored.
+			if tagGenerations.changed(o.Tags, generation) {
+				return entry, nil
+			}
+			cache.Set(key, entry, ttl)
+			if tagGenerations.changed(o.Tags, generation) {
+				cache.Delete(key)
+			}
+			return entry, nil
+		})
+		if err != nil {
+			return errorNode{err}
+		}
+		return entry.node(b, key)
+	}
+}
+
+// renderFragment builds and renders template for the cache, collecting
+// CSP hashes and, if strict is set, dropping inline handlers.
+func renderFragment(b *Builder, key string, template H, strict bool) (*CacheEntry, error) {
+	// A registry marked as slotted records assets without rendering them
+	// in place; they are replayed through b.Require on every use. Ids are
+	// prefixed with the key, since the page around a replayed fragment
+	// hands out its own, and reserved on the page when it is replayed.
+	fb := &Builder{
+		ctx:    CSPNonce.With(b.Context(), ""),
+		assets: &assetRegistry{slotted: true},
+		ids:    &idGenerator{prefix: idBase(key) + "-"},
+	}
+	node := template(fb)
+	if err := checkStrict(fb.Context(), node); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	collector := &CSPCollector{Strict: strict}
+	rw := acquireRenderWriter(&buf)
+	defer releaseRenderWriter(rw)
+	rw.csp = collector
+	if err := node.Render(rw); err != nil {
+		return nil, err
+	}
+	if err := rw.Writer.Flush(); err != nil {
+		return nil, err
+	}
+	return &CacheEntry{
+		HTML:         buf.Bytes(),
+		Assets:       fb.assets.assets,
+		IDs:          fb.ids.list(),
+		ScriptHashes: collector.ScriptHashes(),
+		StyleHashes:  collector.StyleHashes(),
+		Violations:   collector.Violations(),
+		StrictCSP:    strict,
+	}, nil
+}
+
+// node replays the entry for a page being built with b.
+func (e *CacheEntry) node(b *Builder, key string) Node {
+	if b.ids != nil {
+		if id := b.ids.claim(e.IDs); id != "" {
+			return errorNode{fmt.Errorf("minty: cached fragment %q repeats id %q on the page; use a separate key for each use", key, id)}
+		}
+	}
+	if collector, ok := cspCollectorKey.From(b.Context()); ok && collector != nil {
+		for _, source := range e.ScriptHashes {
+			collector.addSource("script", source)
+		}
+		for _, source := range e.StyleHashes {
+			collector.addSource("style", source)
+		}
+		for _, v := range e.Violations {
+			collector.addViolation(v)
+		}
+	}
+	html := Raw(string(e.HTML))
+	if len(e.Assets) == 0 {
+		return html
+	}
+	return NewFragment(b.Require(e.Assets...), html)
+}
+
+// errorNode fails the render with err.
+type errorNode struct {
+	err error
+}
+
+// Render returns the error without writing anything.
+func (n errorNode) Render(w io.Writer) error {
+	return n.err
+}
+
+// flightGroup runs one build per key at a time and shares its result with
+// the callers that arrive meanwhile.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[flightKey]*flightCall
+}
+
+// flightKey identifies a build: the same key in two caches is built twice,
+// once for each cache, and so is a key wanted with and without strict CSP.
+type flightKey struct {
+	cache  FragmentCache
+	key    string
+	strict bool
+}
+
+type flightCall struct {
+	done  chan struct{}
+	entry *CacheEntry
+	err   error
+}
+
+var cacheFlights = &flightGroup{}
+
+// do runs fn for key unless a run is in progress, in which case it waits
+// for that run's result or for ctx to end.
+func (g *flightGroup) do(ctx context.Context, key flightKey, fn func() (*CacheEntry, error)) (*CacheEntry, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[flightKey]*flightCall)
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.entry, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		// A panicking build fails the waiters instead of handing them a
+		// nil entry; the panic itself continues in this goroutine.
+		r := recover()
+		if r != nil {
+			call.entry, call.err = nil, fmt.Errorf("minty: building cached fragment %q panicked: %v", key.key, r)
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+		if r != nil {
+			panic(r)
+		}
+	}()
+	call.entry, call.err = fn()
+	return call.entry, call.err
+}
+
+// LRUCache is an in-memory FragmentCache that evicts the least recently
+// used entry once it holds its maximum number of entries.
+type LRUCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+	tags    map[string]map[string]struct{}
+	now     func() time.Time
+}
+
+type lruItem struct {
+	key     string
+	entry   *CacheEntry
+	expires time.Time // zero for no expiry
+}
+
+// NewLRUCache creates a cache holding at most maxEntries fragments.
+func NewLRUCache(maxEntries int) *LRUCache {
+	if maxEntries < 1 {
+		maxEntries = 1
+	}
+	return &LRUCache{
+		max:     maxEntries,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		tags:    make(map[string]map[string]struct{}),
+		now:     time.Now,
+	}
+}
+
+// Get implements FragmentCache.
+func (c *LRUCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	item := el.Value.(*lruItem)
+	if !item.expires.IsZero() && !c.now().Before(item.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return item.entry, true
+}
+
+// Set implements FragmentCache.
+func (c *LRUCache) Set(key string, entry *CacheEntry, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	item := &lruItem{key: key, entry: entry}
+	if ttl > 0 {
+		item.expires = c.now().Add(ttl)
+	}
+	c.entries[key] = c.order.PushFront(item)
+	for _, tag := range entry.Tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+	for c.order.Len() > c.max {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete implements FragmentCache.
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+}
+
+// InvalidateTags implements FragmentCache.
+func (c *LRUCache) InvalidateTags(tags ...string) {
+	RecordInvalidation(tags...)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			if el, ok := c.entries[key]; ok {
+				c.remove(el)
+			}
+		}
+	}
+}
+
+// Len returns the number of stored entries, including expired ones not
+// yet evicted.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove drops an entry and its tag index records. The caller holds mu.
+func (c *LRUCache) remove(el *list.Element) {
+	item := c.order.Remove(el).(*lruItem)
+	delete(c.entries, item.key)
+	for _, tag := range item.entry.Tags {
+		if keys := c.tags[tag]; keys != nil {
+			delete(keys, item.key)
+			if len(keys) == 0 {
+				delete(c.tags, tag)
+			}
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package minty
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCachedReplaysUntilExpiry(t *testing.T) {
+	cache := NewLRUCache(8)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	builds := 0
+	panel := Cached("panel", time.Minute, func(b *Builder) Node {
+		builds++
+		return b.Div(Class("panel"), "build ", builds)
+	}, InCache(cache))
+
+	first := RenderToString(panel)
+	second := RenderToString(panel)
+	if first != `<div class="panel">build 1</div>` || second != first || builds != 1 {
+		t.Errorf("expected one build replayed, got %q then %q (%d builds)", first, second, builds)
+	}
+
+	now = now.Add(time.Minute)
+	if got := RenderToString(panel); got != `<div class="panel">build 2</div>` {
+		t.Errorf("expired entry should be rebuilt, got %q", got)
+	}
+}
+
+func TestCachedInvalidatesByTag(t *testing.T) {
+	cache := NewLRUCache(8)
+	version := "v1"
+	orders := Cached("orders", 0, func(b *Builder) Node { return b.P(version) }, InCache(cache), CacheTags("orders"))
+	products := Cached("products", 0, func(b *Builder) Node { return b.P(version) }, InCache(cache), CacheTags("products"))
+
+	RenderToString(orders)
+	RenderToString(products)
+	version = "v2"
+	cache.InvalidateTags("orders")
+
+	if got := RenderToString(orders); got != "<p>v2</p>" {
+		t.Errorf("invalidated fragment = %q", got)
+	}
+	if got := RenderToString(products); got != "<p>v1</p>" {
+		t.Errorf("other tags should be kept, got %q", got)
+	}
+}
+
+func TestCachedDropsBuildsOverlappingInvalidation(t *testing.T) {
+	cache := NewLRUCache(8)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	version := "v1"
+	orders := Cached("orders", 0, func(b *Builder) Node {
+		v := version
+		close(started)
+		<-release
+		return b.P(v)
+	}, InCache(cache), CacheTags("orders"))
+
+	done := make(chan string)
+	go func() { done <- RenderToString(orders) }()
+	<-started
+	version = "v2"
+	cache.InvalidateTags("orders")
+	close(release)
+
+	if got := <-done; got != "<p>v1</p>" {
+		t.Errorf("build in progress = %q", got)
+	}
+	if _, ok := cache.Get("orders"); ok {
+		t.Error("a build that overlapped an invalidation must not be stored")
+	}
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", &CacheEntry{HTML: []byte("a"), Tags: []string{"t"}}, 0)
+	cache.Set("b", &CacheEntry{HTML: []byte("b")}, 0)
+	cache.Get("a")
+	cache.Set("c", &CacheEntry{HTML: []byte("c")}, 0)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("a was used recently and should be kept")
+	}
+	cache.InvalidateTags("t")
+	if cache.Len() != 1 || len(cache.tags) != 0 {
+		t.Errorf("tag invalidation left %d entries and %d tags", cache.Len(), len(cache.tags))
+	}
+}
+
+func TestCachedSingleFlight(t *testing.T) {
+	cache := NewLRUCache(8)
+	var builds atomic.Int32
+	release := make(chan struct{})
+	slow := Cached("slow", time.Minute, func(b *Builder) Node {
+		builds.Add(1)
+		<-release
+		return b.P("done")
+	}, InCache(cache))
+
+	var wg sync.WaitGroup
+	results := make([]string, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = RenderToString(slow)
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := builds.Load(); n != 1 {
+		t.Errorf("fragment built %d times, want 1", n)
+	}
+	for _, r := range results {
+		if r != "<p>done</p>" {
+			t.Fatalf("unexpected result %q", r)
+		}
+	}
+}
+
+func TestCachedSingleFlightPerCache(t *testing.T) {
+	first, second := NewLRUCache(8), NewLRUCache(8)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	slow := Cached("shared", 0, func(b *Builder) Node {
+		close(started)
+		<-release
+		return b.P("first")
+	}, InCache(first))
+	fast := Cached("shared", 0, func(b *Builder) Node { return b.P("second") }, InCache(second))
+
+	done := make(chan string)
+	go func() { done <- RenderToString(slow) }()
+	<-started
+	if got := RenderToString(fast); got != "<p>second</p>" {
+		t.Errorf("a build for another cache was shared: %q", got)
+	}
+	close(release)
+	if got := <-done; got != "<p>first</p>" {
+		t.Errorf("first cache got %q", got)
+	}
+	if _, ok := second.Get("shared"); !ok {
+		t.Error("second cache should store its own entry")
+	}
+}
+
+func TestCachedPanicFailsWaiters(t *testing.T) {
+	cache := NewLRUCache(8)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	broken := Cached("broken", 0, func(b *Builder) Node {
+		close(started)
+		<-release
+		panic("boom")
+	}, InCache(cache))
+
+	leader := make(chan interface{})
+	go func() {
+		defer func() { leader <- recover() }()
+		RenderToString(broken)
+	}()
+	<-started
+
+	waiter := make(chan error)
+	go func() {
+		_, err := RenderToStringE(broken)
+		waiter <- err
+	}()
+	// let the waiter join the flight before the build fails
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if r := <-leader; r != "boom" {
+		t.Errorf("the building caller should see the panic, got %v", r)
+	}
+	if err := <-waiter; err == nil || !strings.Contains(err.Error(), "panicked: boom") {
+		t.Errorf("waiter error = %v", err)
+	}
+	if _, ok := cache.Get("broken"); ok {
+		t.Error("a panicking build must not be cached")
+	}
+}
+
+func TestCachedReplaysAssetsAndFailsWithoutStoring(t *testing.T) {
+	cache := NewLRUCache(8)
+	page := func(b *Builder) Node {
+		return Document("Cached", nil, b.Body(Cached("widget", 0, widget, InCache(cache))(b)))(b)
+	}
+	RenderToString(page)
+	html := RenderToString(page)
+	if !strings.Contains(html, "widget.css") || !strings.Contains(html, "widget.js") {
+		t.Errorf("assets required inside a cached fragment should be replayed:\n%s", html)
+	}
+	if strings.Count(html, "widget.css") != 1 {
+		t.Errorf("asset emitted more than once:\n%s", html)
+	}
+
+	ctx := WithStrict(context.Background(), true)
+	broken := Cached("broken", 0, func(b *Builder) Node { return b.P(struct{}{}) }, InCache(cache))
+	var buf strings.Builder
+	err := RenderWithContext(ctx, func(b *Builder) Node { return b.Div(broken(b)) }, &buf)
+	var strictErr *StrictError
+	if !errors.As(err, &strictErr) {
+		t.Errorf("expected strict error from the fragment, got %v", err)
+	}
+	if _, ok := cache.Get("broken"); ok {
+		t.Error("failed fragments must not be cached")
+	}
+}
+
+func TestCachedDoesNotStoreNonce(t *testing.T) {
+	cache := NewLRUCache(8)
+	inline := Cached("inline", 0, func(b *Builder) Node {
+		return b.Script(Raw("init()"))
+	}, InCache(cache))
+
+	var buf strings.Builder
+	ctx := CSPNonce.With(context.Background(), "abc123")
+	if err := RenderWithContext(ctx, inline, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "abc123") {
+		t.Errorf("cached fragment captured the request nonce: %s", buf.String())
+	}
+}
+
+func TestCachedReportsCSPHashesAndViolations(t *testing.T) {
+	cache := NewLRUCache(8)
+	widget := func(b *Builder) Node {
+		return b.Div(b.Button(On("click", "save()"), "Save"), b.Script(Raw("init()")), b.Style(Raw("p{}")))
+	}
+	uncached := &CSPCollector{Strict: true}
+	RenderWithContext(WithCSPCollector(context.Background(), uncached), widget, &strings.Builder{})
+
+	cached := Cached("csp", 0, widget, InCache(cache))
+	for i := 0; i < 2; i++ {
+		collector := &CSPCollector{Strict: true}
+		var buf strings.Builder
+		if err := RenderWithContext(WithCSPCollector(context.Background(), collector), cached, &buf); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(buf.String(), "onclick") {
+			t.Errorf("render %d kept the handler: %s", i, buf.String())
+		}
+		if got := collector.ScriptHashes(); len(got) != 1 || got[0] != uncached.ScriptHashes()[0] {
+			t.Errorf("render %d script hashes = %v, want %v", i, got, uncached.ScriptHashes())
+		}
+		if got := collector.StyleHashes(); len(got) != 1 || got[0] != uncached.StyleHashes()[0] {
+			t.Errorf("render %d style hashes = %v", i, got)
+		}
+		if got := collector.Violations(); len(got) != 1 || got[0].Attribute != "onclick" {
+			t.Errorf("render %d violations = %v", i, got)
+		}
+	}
+
+	// a request without strict mode gets the handler back
+	if got := RenderToString(cached); !strings.Contains(got, `onclick="save()"`) {
+		t.Errorf("non-strict render = %s", got)
+	}
+}
@@ -0,0 +1,206 @@
+package minty
+
+import (
+	"sort"
+	"strings"
+)
+
+// Attribute merging
+//
+// Applying class, style or a space-separated token attribute to an element
+// that already has it merges the values instead of replacing them, so a
+// wrapper can extend a theme component:
+//
+//	theme.Button("Save", "primary", mi.Class("w-full"))
+//	// <button class="btn btn-primary w-full" type="button">
+//
+// Class tokens are added once each, in order. Style declarations are
+// merged by property, the later value winning. Use ReplaceAttr to
+// overwrite instead.
+
+// tokenAttributes hold space-separated token lists.
+var tokenAttributes = map[string]bool{
+	"class":            true,
+	"rel":              true,
+	"rev":              true,
+	"aria-controls":    true,
+	"aria-describedby": true,
+	"aria-flowto":      true,
+	"aria-labelledby":  true,
+	"aria-owns":        true,
+	"blocking":         true,
+	"headers":          true,
+	"itemprop":         true,
+	"itemref":          true,
+	"part":             true,
+	"ping":             true,
+	"sandbox":          true,
+}
+
+// mergeAttribute combines an existing attribute value with a new one, and
+// reports whether the attribute merges at all.
+func mergeAttribute(name, existing, value string) (string, bool) {
+	switch {
+	case tokenAttributes[name]:
+		return mergeTokens(existing, value), true
+	case name == "style":
+		return mergeStyles(existing, value), true
+	case name == "hx-ext":
+		return mergeCommaList(existing, value), true
+	}
+	return "", false
+}
+
+// mergeCommaList appends the items of a comma-separated list missing from
+// existing, as for hx-ext="sse, json-enc".
+func mergeCommaList(existing, value string) string {
+	var items []string
+	for _, list := range []string{existing, value} {
+		for _, item := range strings.Split(list, ",") {
+			if item = strings.TrimSpace(item); item != "" && !containsToken(items, item) {
+				items = append(items, item)
+			}
+		}
+	}
+	return strings.Join(items, ", ")
+}
+
+// mergeTokens appends the tokens of value missing from existing.
+func mergeTokens(existing, value string) string {
+	tokens := strings.Fields(existing)
+	for _, token := range strings.Fields(value) {
+		if !containsToken(tokens, token) {
+			tokens = append(tokens, token)
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+func containsToken(tokens []string, token string) bool {
+	for _, t := range tokens {
+		if t == token {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeStyles merges two declaration lists by property. A redeclared
+// property keeps its position and takes the later value.
+func mergeStyles(existing, value string) string {
+	type declaration struct{ property, text string }
+	var decls []declaration
+	add := func(list string) {
+		for _, text := range strings.Split(list, ";") {
+			text = strings.TrimSpace(text)
+			if text == "" {
+				continue
+			}
+			property := text
+			if i := strings.IndexByte(text, ':'); i >= 0 {
+				property = strings.ToLower(strings.TrimSpace(text[:i]))
+			}
+			replaced := false
+			for i := range decls {
+				if decls[i].property == property {
+					decls[i].text = text
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				decls = append(decls, declaration{property, text})
+			}
+		}
+	}
+	add(existing)
+	add(value)
+
+	texts := make([]string, len(decls))
+	for i, d := range decls {
+		texts[i] = d.text
+	}
+	return strings.Join(texts, "; ")
+}
+
+// replaceAttribute sets an attribute without merging.
+type replaceAttribute struct {
+	Name  string
+	Value string
+}
+
+// Apply sets the attribute, discarding any earlier value.
+func (ra replaceAttribute) Apply(element *Element) {
+	element.Attributes.Set(ra.Name, ra.Value)
+}
+
+// ReplaceAttr creates an attribute that overwrites an earlier value of
+// the same name instead of merging with it.
+func ReplaceAttr(name, value string) Attribute {
+	return replaceAttribute{Name: name, Value: value}
+}
+
+// noAttribute applies nothing.
+type noAttribute struct{}
+
+// Apply does nothing.
+func (noAttribute) Apply(*Element) {}
+
+// ClassIf adds class when condition is true.
+func ClassIf(condition bool, class string) Attribute {
+	if !condition {
+		return noAttribute{}
+	}
+	return Class(class)
+}
+
+// Classes adds the classes whose value is true, in sorted order so the
+// output does not depend on map iteration:
+//
+//	mi.Classes(map[string]bool{"active": isActive, "disabled": !enabled})
+func Classes(classes map[string]bool) Attribute {
+	names := make([]string, 0, len(classes))
+	for name, on := range classes {
+		if on {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return noAttribute{}
+	}
+	sort.Strings(names)
+	return Class(strings.Join(names, " "))
+}
+
+// Decorate returns a template that applies attrs to the root element of
+// template, merging as usual. When the template returns a fragment, the
+// first element in it is decorated.
+//
+//	mi.Decorate(theme.Card("Orders", body), mi.Class("shadow-lg"), mi.ID("orders"))
+func Decorate(template H, attrs ...Attribute) H {
+	return func(b *Builder) Node {
+		node := template(b)
+		if root := rootElement(node); root != nil {
+			for _, attr := range attrs {
+				attr.Apply(root)
+			}
+		}
+		return node
+	}
+}
+
+// rootElement returns node if it is an element, or the first element in
+// it if it is a fragment.
+func rootElement(node Node) *Element {
+	switch n := node.(type) {
+	case *Element:
+		return n
+	case *Fragment:
+		for _, child := range n.Children {
+			if el := rootElement(child); el != nil {
+				return el
+			}
+		}
+	}
+	return nil
+}
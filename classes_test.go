@@ -0,0 +1,96 @@
+package minty
+
+import "testing"
+
+func TestAttributesMerge(t *testing.T) {
+	got := RenderToString(func(b *Builder) Node {
+		return b.A(Class("btn btn-primary"), Rel("noopener"), Style("color: red; margin: 0;"), Href("/x"),
+			Class("w-full btn"), Rel("noreferrer"), Style("COLOR: blue"), Href("/y"),
+			Attr("aria-describedby", "hint"), Attr("aria-describedby", "error"),
+		)
+	})
+	want := `<a class="btn btn-primary w-full" rel="noopener noreferrer" style="COLOR: blue; margin: 0" href="/y" aria-describedby="hint error"></a>`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	got = RenderToString(func(b *Builder) Node {
+		return b.Div(Class("card"), ReplaceAttr("class", "panel"))
+	})
+	if got != `<div class="panel"></div>` {
+		t.Errorf("ReplaceAttr should overwrite, got %s", got)
+	}
+
+	got = RenderToString(func(b *Builder) Node {
+		return b.Div(HtmxExt("sse"), HtmxExt("json-enc, sse"))
+	})
+	if got != `<div hx-ext="sse, json-enc"></div>` {
+		t.Errorf("hx-ext should merge as a list, got %s", got)
+	}
+}
+
+func TestConditionalClasses(t *testing.T) {
+	got := RenderToString(func(b *Builder) Node {
+		return b.Li(Class("item"),
+			ClassIf(true, "active"), ClassIf(false, "hidden"),
+			Classes(map[string]bool{"selected": true, "disabled": false, "first": true}),
+		)
+	})
+	if got != `<li class="item active first selected"></li>` {
+		t.Errorf("got %s", got)
+	}
+	if got := RenderToString(func(b *Builder) Node { return b.Li(ClassIf(false, "x"), Classes(nil)) }); got != "<li></li>" {
+		t.Errorf("no classes should leave no attribute, got %s", got)
+	}
+}
+
+func TestTailwindMerge(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want string
+	}{
+		{[]string{"px-4 py-2 bg-blue-600", "px-2"}, "py-2 bg-blue-600 px-2"},
+		{[]string{"px-2 pt-1", "p-4"}, "p-4"},
+		{[]string{"p-4", "px-2"}, "p-4 px-2"},
+		{[]string{"text-sm text-gray-700", "text-lg"}, "text-gray-700 text-lg"},
+		{[]string{"text-sm text-gray-700", "text-red-500/50"}, "text-sm text-red-500/50"},
+		{[]string{"bg-blue-500 hover:bg-blue-700", "bg-red-500"}, "hover:bg-blue-700 bg-red-500"},
+		{[]string{"flex flex-row", "hidden md:flex-col"}, "flex-row hidden md:flex-col"},
+		{[]string{"font-bold font-sans", "font-medium"}, "font-sans font-medium"},
+		{[]string{"border border-gray-200", "border-2 border-t-4"}, "border-gray-200 border-2 border-t-4"},
+		{[]string{"w-10 h-10", "size-12"}, "size-12"},
+		{[]string{"-mt-2 rounded-tl-lg", "mt-4 rounded-lg"}, "mt-4 rounded-lg"},
+		{[]string{"card text-[14px]", "card text-[#333]"}, "text-[14px] card text-[#333]"},
+		{[]string{"text-base leading-7", "text-sm/6"}, "text-sm/6"},
+		{[]string{"text-sm/6", "leading-tight text-lg"}, "leading-tight text-lg"},
+		{[]string{"outline outline-blue-500", "outline-2 outline-dashed"}, "outline-blue-500 outline-2 outline-dashed"},
+		{[]string{"outline-offset-2 outline-red-500", "outline-offset-4"}, "outline-red-500 outline-offset-4"},
+	}
+	for _, tt := range tests {
+		if got := TailwindMerge(tt.in...); got != tt.want {
+			t.Errorf("TailwindMerge(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	got := RenderToString(func(b *Builder) Node {
+		return b.Button(Class("px-4 py-2 rounded"), TailwindClass("px-2 w-full"))
+	})
+	if got != `<button class="py-2 rounded px-2 w-full"></button>` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestDecorate(t *testing.T) {
+	card := func(b *Builder) Node {
+		return NewFragment(b.Text("\n"), b.Div(Class("card"), b.P("body")))
+	}
+	got := RenderToString(Decorate(card, Class("shadow"), ID("orders"), DataAttr("x", "1")))
+	if got != "\n"+`<div class="card shadow" id="orders" data-x="1"><p>body</p></div>` {
+		t.Errorf("got %s", got)
+	}
+
+	text := func(b *Builder) Node { return b.Text("plain") }
+	if got := RenderToString(Decorate(text, Class("x"))); got != "plain" {
+		t.Errorf("templates without elements should be unchanged, got %s", got)
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+
+	mi "github.com/ha1tch/minty"
+)
+
+// Options controls the generated code.
+type Options struct {
+	// Func names the generated template function.
+	Func string
+
+	// Package, if set, makes the output a complete file with a package
+	// clause and the minty import.
+	Package string
+
+	// Source names the input in the generated doc comment.
+	Source string
+}
+
+// Convert parses src and returns gofmt-formatted minty code for it, along
+// with warnings about markup that had no dedicated builder method.
+func Convert(src string, opts Options) ([]byte, []string, error) {
+	root, err := mi.ParseHTML(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.Func == "" {
+		opts.Func = "Mockup"
+	}
+
+	g := &generator{}
+	items := g.children(root.Children, false)
+	if g.err != nil {
+		return nil, nil, g.err
+	}
+
+	var sb strings.Builder
+	if opts.Package != "" {
+		fmt.Fprintf(&sb, "package %s\n\nimport mi \"github.com/ha1tch/minty\"\n\n", opts.Package)
+	} else {
+		// A package clause lets go/format parse the snippet; it is
+		// stripped again below.
+		sb.WriteString("package p\n\n")
+	}
+	if opts.Source != "" {
+		fmt.Fprintf(&sb, "// %s was generated by html2minty from %s.\n", opts.Func, opts.Source)
+	}
+	fmt.Fprintf(&sb, "func %s() mi.H {\nreturn func(b *mi.Builder) mi.Node {\nreturn ", opts.Func)
+
+	nodes := 0
+	for _, it := range items {
+		if it.code != "" {
+			nodes++
+		}
+	}
+	switch {
+	case nodes == 0:
+		sb.WriteString("mi.NewFragment()")
+	case nodes == 1 && len(items) == 1:
+		sb.WriteString(items[0].rootCode())
+	default:
+		sb.WriteString("mi.NewFragment(\n")
+		for _, it := range items {
+			it.writeLine(&sb, true)
+		}
+		sb.WriteString(")")
+	}
+	sb.WriteString("\n}\n}\n")
+
+	out, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("html2minty: generated code does not parse: %v", err)
+	}
+	if opts.Package == "" {
+		out = []byte(strings.TrimPrefix(string(out), "package p\n\n"))
+	}
+	return out, g.warnings, nil
+}
+
+// item is one argument of a generated call: an expression, or a comment
+// line carried over from the markup.
+type item struct {
+	code    string
+	text    bool // code is a string literal
+	inline  bool // code stays on the parent's line
+	comment string
+}
+
+// rootCode returns the item as a Node expression.
+func (it item) rootCode() string {
+	if it.text {
+		return "mi.Txt(" + it.code + ")"
+	}
+	return it.code
+}
+
+func (it item) writeLine(sb *strings.Builder, root bool) {
+	if it.comment != "" {
+		for _, line := range strings.Split(it.comment, "\n") {
+			sb.WriteString("// " + strings.TrimSpace(line) + "\n")
+		}
+	}
+	if it.code != "" {
+		if root {
+			sb.WriteString(it.rootCode() + ",\n")
+		} else {
+			sb.WriteString(it.code + ",\n")
+		}
+	}
+}
+
+type generator struct {
+	warnings []string
+	err      error
+}
+
+// children converts a child list, collapsing insignificant whitespace
+// unless preserve is set (inside pre and textarea).
+func (g *generator) children(nodes []mi.Node, preserve bool) []item {
+	var items []item
+	for i, n := range nodes {
+		switch n := n.(type) {
+		case *mi.Element:
+			items = append(items, g.element(n, preserve))
+		case *mi.TextNode:
+			text := n.Content
+			if !preserve {
+				text = collapseSpace(text, i == 0, i == len(nodes)-1)
+			}
+			if text != "" {
+				items = append(items, item{code: quote(text), text: true, inline: true})
+			}
+		case *mi.RawNode:
+			if c := strings.TrimSpace(n.Content); strings.HasPrefix(c, "<!--") {
+				c = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(c, "<!--"), "-->"))
+				if c != "" {
+					items = append(items, item{comment: c})
+				}
+			} else if c != "" {
+				items = append(items, item{code: "mi.Raw(" + quote(n.Content) + ")"})
+			}
+		}
+	}
+	return items
+}
+
+// collapseSpace folds runs of whitespace into single spaces. Leading and
+// trailing runs are dropped when they contain a newline, which is layout
+// in the source rather than a space between words, and at the edges of
+// the parent element.
+func collapseSpace(text string, first, last bool) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		if first || last || strings.ContainsAny(text, "\n\r") {
+			return ""
+		}
+		return " "
+	}
+	lead := text[:len(text)-len(strings.TrimLeft(text, " \t\n\r\f"))]
+	trail := text[len(strings.TrimRight(text, " \t\n\r\f")):]
+	out := strings.Join(strings.Fields(trimmed), " ")
+	if lead != "" && !first && !strings.ContainsAny(lead, "\n\r") {
+		out = " " + out
+	}
+	if trail != "" && !last && !strings.ContainsAny(trail, "\n\r") {
+		out += " "
+	}
+	return out
+}
+
+func (g *generator) element(el *mi.Element, preserve bool) item {
+	tm, ok := tagMethods[el.Tag]
+	if !ok {
+		return g.generic(el, "no builder method for <"+el.Tag+">", preserve)
+	}
+
+	switch tm.kind {
+	case argsNone:
+		if len(el.Attributes) > 0 || len(el.Children) > 0 {
+			return g.generic(el, "b."+tm.method+" takes no attributes", preserve)
+		}
+		return item{code: "b." + tm.method + "()"}
+	case argsTitle:
+		if len(el.Attributes) > 0 {
+			return g.generic(el, "b.Title takes no attributes", preserve)
+		}
+		return item{code: "b.Title(" + quote(mi.TextContent(el)) + ")"}
+	}
+
+	args := g.attributes(el)
+	if tm.kind == argsAttrs {
+		return item{code: "b." + tm.method + "(" + strings.Join(args, ", ") + ")"}
+	}
+
+	var kids []item
+	if el.Tag == "script" || el.Tag == "style" {
+		for _, child := range el.Children {
+			if raw, ok := child.(*mi.RawNode); ok && strings.TrimSpace(raw.Content) != "" {
+				kids = append(kids, item{code: "mi.Raw(" + quote(raw.Content) + ")", inline: true})
+			}
+		}
+	} else if el.Tag == "pre" || el.Tag == "textarea" {
+		// A newline straight after the start tag is not content
+		children := el.Children
+		if len(children) > 0 {
+			if t, ok := children[0].(*mi.TextNode); ok && strings.HasPrefix(t.Content, "\n") {
+				children = append([]mi.Node{&mi.TextNode{Content: t.Content[1:]}}, children[1:]...)
+			}
+		}
+		kids = g.children(children, true)
+	} else {
+		kids = g.children(el.Children, preserve)
+	}
+	return call("b."+tm.method, args, kids)
+}
+
+// attributes returns the helper calls for the element's attributes.
+func (g *generator) attributes(el *mi.Element) []string {
+	var args []string
+	for _, a := range el.Attributes {
+		if isEventHandler(a.Name) {
+			g.warnings = append(g.warnings, a.Name+" on <"+el.Tag+"> emitted as mi.TrustedJS, check the handler is safe")
+		}
+		args = append(args, attrExpr(a.Name, a.Value))
+	}
+	return args
+}
+
+// call returns the builder call fn(args..., kids...).
+func call(fn string, args []string, kids []item) item {
+	// Attributes, text and script bodies stay on one line; element children
+	// and comments go one per line.
+	inline := true
+	for _, k := range kids {
+		if !k.inline {
+			inline = false
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString(fn + "(")
+	sb.WriteString(strings.Join(args, ", "))
+	if inline {
+		for i, k := range kids {
+			if i > 0 || len(args) > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(k.code)
+		}
+		sb.WriteString(")")
+		return item{code: sb.String()}
+	}
+	if len(args) > 0 {
+		sb.WriteString(",")
+	}
+	sb.WriteString("\n")
+	for _, k := range kids {
+		k.writeLine(&sb, false)
+	}
+	sb.WriteString(")")
+	return item{code: sb.String()}
+}
+
+// generic falls back to the b.Element builder for elements without a
+// dedicated method. Empty elements such as <path /> or <br class="x"> are
+// emitted as mi.Raw instead, as b.Element would give them an end tag.
+func (g *generator) generic(el *mi.Element, reason string, preserve bool) item {
+	comment := "html2minty: " + reason
+	if !el.SelfClosing {
+		g.warnings = append(g.warnings, reason+", emitted as b.Element")
+		args := append([]string{quote(el.Tag)}, g.attributes(el)...)
+		it := call("b.Element", args, g.children(el.Children, preserve))
+		it.comment = comment
+		return it
+	}
+
+	g.warnings = append(g.warnings, reason+", emitted as mi.Raw")
+	// Trusted attributes keep the markup as written instead of sanitised
+	empty := &mi.Element{Tag: el.Tag, SelfClosing: true}
+	for _, a := range el.Attributes {
+		if isEventHandler(a.Name) {
+			g.warnings = append(g.warnings, a.Name+" on <"+el.Tag+"> emitted verbatim, check the handler is safe")
+		}
+		mi.TrustedAttr(a.Name, a.Value).Apply(empty)
+	}
+	var sb strings.Builder
+	if err := empty.Render(&sb); err != nil {
+		if g.err == nil {
+			g.err = err
+		}
+		return item{}
+	}
+	return item{comment: comment, code: "mi.Raw(" + quote(sb.String()) + ")"}
+}
+
+// attrExpr returns the helper call for an attribute, falling back to
+// mi.Attr when no helper fits the name or the value.
+func attrExpr(name, value string) string {
+	if h, ok := attrHelpers[name]; ok {
+		call := "mi." + h.fn + "("
+		switch h.kind {
+		case valueString:
+			return call + quote(value) + ")"
+		case valueInt:
+			if n, err := strconv.Atoi(value); err == nil && strconv.Itoa(n) == value {
+				return call + value + ")"
+			}
+		case valueFloat:
+			if f, err := strconv.ParseFloat(value, 64); err == nil && fmt.Sprintf("%g", f) == value {
+				return call + value + ")"
+			}
+		case valueBool:
+			if value == "true" || value == "false" {
+				return call + value + ")"
+			}
+		case valueYesNo:
+			if value == "yes" || value == "no" {
+				return call + strconv.FormatBool(value == "yes") + ")"
+			}
+		case valuePresence:
+			return call + ")"
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(name, "data-"):
+		return "mi.Data(" + quote(strings.TrimPrefix(name, "data-")) + ", " + quote(value) + ")"
+	case strings.HasPrefix(name, "hx-on:"):
+		return "mi.HxOn(" + quote(strings.TrimPrefix(name, "hx-on:")) + ", mi.TrustedJS(" + quote(value) + "))"
+	case isEventHandler(name):
+		// mi.Attr renders plain strings in on* attributes inert
+		return "mi.On(" + quote(strings.TrimPrefix(name, "on")) + ", mi.TrustedJS(" + quote(value) + "))"
+	}
+	return "mi.Attr(" + quote(name) + ", " + quote(value) + ")"
+}
+
+// isEventHandler reports whether name is an on* event handler attribute.
+func isEventHandler(name string) bool {
+	return len(name) > 2 && strings.HasPrefix(name, "on")
+}
+
+// quote returns a Go string literal, preferring a raw string for
+// multi-line content.
+func quote(s string) string {
+	if strings.Contains(s, "\n") && !strings.ContainsAny(s, "`\r") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+)
+
+func TestConvertMapsTagsAndAttributes(t *testing.T) {
+	src := `<div class="card" id="signup">
+  <h2>Create an <em>account</em></h2>
+  <form hx-post="/signup" hx-target="#result" data-step="1">
+    <input type="email" name="email" required maxlength="120">
+    <button type="submit" aria-hidden="false">Sign up</button>
+  </form>
+</div>`
+	code := convert(t, src, Options{})
+
+	for _, want := range []string{
+		`func Mockup() mi.H {`,
+		`return func(b *mi.Builder) mi.Node {`,
+		`return b.Div(mi.Class("card"), mi.ID("signup"),`,
+		`b.H2(`,
+		`"Create an ",`,
+		`b.Em("account"),`,
+		`b.Form(mi.HxPost("/signup"), mi.HxTarget("#result"), mi.Data("step", "1"),`,
+		`b.Input(mi.Type("email"), mi.Name("email"), mi.Required(), mi.MaxLength(120)),`,
+		`b.Button(mi.Type("submit"), mi.AriaHidden(false), "Sign up"),`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("missing %q in:\n%s", want, code)
+		}
+	}
+}
+
+func TestConvertFallbacks(t *testing.T) {
+	src := `<p x-data="{open: false}" tabindex="soon" hx-on:click="go()">` +
+		`<my-widget>hi</my-widget></p>`
+	code, warnings, err := Convert(src, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`mi.Attr("x-data", "{open: false}")`,
+		`mi.Attr("tabindex", "soon")`,
+		`mi.HxOn("click", mi.TrustedJS("go()"))`,
+		`// html2minty: no builder method for <my-widget>`,
+		`b.Element("my-widget", "hi"),`,
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("missing %q in:\n%s", want, code)
+		}
+	}
+	if len(warnings) != 1 {
+		t.Errorf("warnings = %q, want one", warnings)
+	}
+}
+
+func TestConvertFallbacksKeepMarkup(t *testing.T) {
+	src := `<svg><foo href="javascript:go()"></foo><bar onclick="go()" /></svg>`
+	code := convert(t, src, Options{})
+	for _, want := range []string{
+		`b.Element("foo", mi.Href("javascript:go()")),`,
+		`mi.Raw("<bar onclick=\"go()\" />"),`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("missing %q in:\n%s", want, code)
+		}
+	}
+
+	if _, _, err := Convert("<br a`b=1>", Options{}); err == nil {
+		t.Error("an attribute that cannot be rendered should fail the conversion")
+	}
+}
+
+func TestConvertEventHandlers(t *testing.T) {
+	code, warnings, err := Convert(`<button onclick="save()">Save</button>`, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `b.Button(mi.On("click", mi.TrustedJS("save()")), "Save")`; !strings.Contains(string(code), want) {
+		t.Errorf("missing %q in:\n%s", want, code)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "onclick") {
+		t.Errorf("warnings = %q, want one about onclick", warnings)
+	}
+}
+
+func TestConvertSVG(t *testing.T) {
+	code := convert(t, `<svg viewBox="0 0 10 10" preserveAspectRatio="none"><path d="M0 0"></path></svg>`, Options{})
+	if want := `b.Svg(mi.ViewBox("0 0 10 10"), mi.Attr("preserveAspectRatio", "none"),`; !strings.Contains(code, want) {
+		t.Errorf("missing %q in:\n%s", want, code)
+	}
+}
+
+func TestConvertWhitespace(t *testing.T) {
+	src := "<p>\n  Hello   <b>big</b> world\n</p>\n<pre>\n  keep  this\n</pre>"
+	code := convert(t, src, Options{})
+
+	for _, want := range []string{
+		`"Hello ",`,
+		`" world",`,
+		"b.Pre(`  keep  this\n`)",
+		`return mi.NewFragment(`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("missing %q in:\n%s", want, code)
+		}
+	}
+}
+
+func TestConvertScriptsAndComments(t *testing.T) {
+	src := "<!-- Header -->\n<script>if (a < b) go()</script>"
+	code := convert(t, src, Options{})
+
+	if !strings.Contains(code, "// Header\n") {
+		t.Errorf("comment not carried over:\n%s", code)
+	}
+	if !strings.Contains(code, `b.Script(mi.Raw("if (a < b) go()"))`) {
+		t.Errorf("script body not kept raw:\n%s", code)
+	}
+}
+
+func TestConvertPackage(t *testing.T) {
+	code := convert(t, `<br>`, Options{Package: "ui", Func: "Spacer", Source: "spacer.html"})
+	want := `package ui
+
+import mi "github.com/ha1tch/minty"
+
+// Spacer was generated by html2minty from spacer.html.
+func Spacer() mi.H {
+	return func(b *mi.Builder) mi.Node {
+		return b.Br()
+	}
+}
+`
+	if code != want {
+		t.Errorf("got:\n%s\nwant:\n%s", code, want)
+	}
+}
+
+// convert runs Convert and checks the output is gofmt-clean.
+func convert(t *testing.T, src string, opts Options) string {
+	t.Helper()
+	code, _, err := Convert(src, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := code
+	if opts.Package == "" {
+		file = append([]byte("package p\n\n"), code...)
+	}
+	formatted, err := format.Source(file)
+	if err != nil {
+		t.Fatalf("output does not parse: %v\n%s", err, code)
+	}
+	if string(formatted) != string(file) {
+		t.Errorf("output is not gofmt-clean:\n%s", code)
+	}
+	return string(code)
+}
@@ -0,0 +1,78 @@
+// html2minty converts an HTML file or fragment into minty Go code.
+//
+// Known tags become Builder methods, known attributes become the helpers
+// in attributes.go (hx-get becomes mi.HxGet, data-* becomes mi.Data) and
+// anything else falls back to mi.Attr. Elements without a builder method,
+// such as custom elements, use the generic b.Element with a comment; empty
+// ones are kept verbatim as mi.Raw.
+// The output is gofmt-formatted.
+//
+// Usage:
+//
+//	html2minty [flags] [file.html]
+//
+// With no file, the markup is read from standard input.
+//
+// Flags:
+//
+//	-func name  name of the generated function (default: Mockup)
+//	-pkg name   emit a complete file in package name
+//	-o file     write to file instead of standard output
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	funcName := flag.String("func", "Mockup", "name of the generated function")
+	pkg := flag.String("pkg", "", "emit a complete file in this package")
+	output := flag.String("o", "", "write to this file instead of standard output")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: html2minty [flags] [file.html]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(*funcName, *pkg, *output, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "html2minty:", err)
+		os.Exit(1)
+	}
+}
+
+func run(funcName, pkg, output string, args []string) error {
+	opts := Options{Func: funcName, Package: pkg}
+
+	var src []byte
+	var err error
+	switch len(args) {
+	case 0:
+		src, err = io.ReadAll(os.Stdin)
+	case 1:
+		src, err = os.ReadFile(args[0])
+		opts.Source = args[0]
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		return err
+	}
+
+	code, warnings, err := Convert(string(src), opts)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		fmt.Fprintln(os.Stderr, "html2minty: warning:", w)
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return os.WriteFile(output, code, 0o644)
+}
@@ -0,0 +1,178 @@
+package main
+
+// Tag and attribute tables mirror builder.go and attributes.go. Keep them
+// in sync when helpers are added there.
+
+// argKind describes the parameters a builder method accepts.
+type argKind int
+
+const (
+	argsMixed argKind = iota // ...interface{}: attributes and children
+	argsAttrs                // ...Attribute only (void elements)
+	argsNone                 // no arguments (Br, Wbr)
+	argsTitle                // a single text string (Title)
+)
+
+type tagMethod struct {
+	method string
+	kind   argKind
+}
+
+// tagMethods maps tag names to Builder methods.
+var tagMethods = map[string]tagMethod{
+	// Document structure
+	"html": {"Html", argsMixed}, "head": {"Head", argsMixed}, "body": {"Body", argsMixed},
+	"title": {"Title", argsTitle}, "meta": {"Meta", argsAttrs}, "link": {"Link", argsAttrs},
+	"script": {"Script", argsMixed}, "style": {"Style", argsMixed}, "base": {"Base", argsAttrs},
+	"noscript": {"Noscript", argsMixed},
+
+	// Sectioning
+	"main": {"Main", argsMixed}, "header": {"Header", argsMixed}, "footer": {"Footer", argsMixed},
+	"nav": {"Nav", argsMixed}, "section": {"Section", argsMixed}, "article": {"Article", argsMixed},
+	"aside": {"Aside", argsMixed}, "hgroup": {"Hgroup", argsMixed},
+	"h1": {"H1", argsMixed}, "h2": {"H2", argsMixed}, "h3": {"H3", argsMixed},
+	"h4": {"H4", argsMixed}, "h5": {"H5", argsMixed}, "h6": {"H6", argsMixed},
+
+	// Text content
+	"p": {"P", argsMixed}, "div": {"Div", argsMixed}, "span": {"Span", argsMixed},
+	"pre": {"Pre", argsMixed}, "blockquote": {"Blockquote", argsMixed}, "address": {"Address", argsMixed},
+	"hr": {"Hr", argsAttrs}, "br": {"Br", argsNone}, "wbr": {"Wbr", argsNone},
+
+	// Inline semantics
+	"strong": {"Strong", argsMixed}, "em": {"Em", argsMixed}, "b": {"B", argsMixed},
+	"i": {"I", argsMixed}, "u": {"U", argsMixed}, "s": {"S", argsMixed},
+	"small": {"Small", argsMixed}, "mark": {"Mark", argsMixed}, "del": {"Del", argsMixed},
+	"ins": {"Ins", argsMixed}, "sub": {"Sub", argsMixed}, "sup": {"Sup", argsMixed},
+	"code": {"Code", argsMixed}, "kbd": {"Kbd", argsMixed}, "samp": {"Samp", argsMixed},
+	"var": {"Var", argsMixed}, "cite": {"Cite", argsMixed}, "abbr": {"Abbr", argsMixed},
+	"dfn": {"Dfn", argsMixed}, "q": {"Q", argsMixed}, "time": {"Time", argsMixed},
+	"data": {"Data", argsMixed}, "bdi": {"Bdi", argsMixed}, "bdo": {"Bdo", argsMixed},
+	"ruby": {"Ruby", argsMixed}, "rt": {"Rt", argsMixed}, "rp": {"Rp", argsMixed},
+	"a": {"A", argsMixed},
+
+	// Lists and tables
+	"ul": {"Ul", argsMixed}, "ol": {"Ol", argsMixed}, "li": {"Li", argsMixed},
+	"dl": {"Dl", argsMixed}, "dt": {"Dt", argsMixed}, "dd": {"Dd", argsMixed},
+	"table": {"Table", argsMixed}, "thead": {"Thead", argsMixed}, "tbody": {"Tbody", argsMixed},
+	"tfoot": {"Tfoot", argsMixed}, "tr": {"Tr", argsMixed}, "th": {"Th", argsMixed},
+	"td": {"Td", argsMixed}, "caption": {"Caption", argsMixed}, "colgroup": {"Colgroup", argsMixed},
+	"col": {"Col", argsAttrs},
+
+	// Forms
+	"form": {"Form", argsMixed}, "input": {"Input", argsAttrs}, "button": {"Button", argsMixed},
+	"label": {"Label", argsMixed}, "textarea": {"Textarea", argsMixed}, "select": {"Select", argsMixed},
+	"option": {"Option", argsMixed}, "optgroup": {"Optgroup", argsMixed}, "fieldset": {"Fieldset", argsMixed},
+	"legend": {"Legend", argsMixed}, "datalist": {"Datalist", argsMixed}, "output": {"Output", argsMixed},
+	"progress": {"Progress", argsMixed}, "meter": {"Meter", argsMixed},
+
+	// Media and embedded content
+	"img": {"Img", argsAttrs}, "video": {"Video", argsMixed}, "audio": {"Audio", argsMixed},
+	"source": {"Source", argsAttrs}, "track": {"Track", argsAttrs}, "picture": {"Picture", argsMixed},
+	"canvas": {"Canvas", argsMixed}, "map": {"Map", argsMixed}, "area": {"Area", argsAttrs},
+	"embed": {"Embed", argsAttrs}, "object": {"Object", argsMixed}, "param": {"Param", argsAttrs},
+	"iframe": {"Iframe", argsMixed},
+
+	// SVG and MathML
+	"svg": {"Svg", argsMixed}, "polygon": {"Polygon", argsMixed}, "path": {"Path", argsMixed},
+	"circle": {"Circle", argsMixed}, "rect": {"Rect", argsMixed}, "line": {"Line", argsMixed},
+	"g": {"G", argsMixed}, "polyline": {"Polyline", argsMixed}, "defs": {"Defs", argsMixed},
+	"use": {"Use", argsMixed}, "text": {"SvgText", argsMixed}, "math": {"Math", argsMixed},
+
+	// Interactive and components
+	"details": {"Details", argsMixed}, "summary": {"Summary", argsMixed}, "dialog": {"Dialog", argsMixed},
+	"menu": {"Menu", argsMixed}, "template": {"Template", argsMixed}, "slot": {"Slot", argsMixed},
+}
+
+// valueKind describes the parameter an attribute helper takes.
+type valueKind int
+
+const (
+	valueString   valueKind = iota // Href(string)
+	valueInt                       // Rows(int)
+	valueFloat                     // High(float64)
+	valueBool                      // AriaHidden(bool), from "true"/"false"
+	valueYesNo                     // Translate(bool), from "yes"/"no"
+	valuePresence                  // Required(), regardless of value
+)
+
+type attrHelper struct {
+	fn   string
+	kind valueKind
+}
+
+// attrHelpers maps attribute names to helpers in attributes.go. The htmx
+// entries prefer the short Hx* aliases where they exist. hx-boost is left
+// to mi.Attr because HtmxBoost renders a bare boolean attribute, while
+// htmx only boosts for the value "true".
+var attrHelpers = map[string]attrHelper{
+	// Universal
+	"class": {"Class", valueString}, "id": {"ID", valueString}, "style": {"Style", valueString},
+	"title": {"Title", valueString}, "dir": {"Dir", valueString}, "tabindex": {"TabIndex", valueInt},
+	"accesskey": {"AccessKey", valueString}, "contenteditable": {"ContentEditable", valueBool},
+	"hidden": {"Hidden", valuePresence}, "spellcheck": {"Spellcheck", valueBool},
+	"translate": {"Translate", valueYesNo}, "lang": {"Lang", valueString}, "role": {"Role", valueString},
+
+	// Links
+	"href": {"Href", valueString}, "target": {"Target", valueString}, "rel": {"Rel", valueString},
+	"hreflang": {"Hreflang", valueString}, "referrerpolicy": {"Referrerpolicy", valueString},
+	"download": {"Download", valueString},
+
+	// Meta
+	"name": {"Name", valueString}, "content": {"Content", valueString},
+	"charset": {"Charset", valueString}, "http-equiv": {"HttpEquiv", valueString},
+
+	// Forms
+	"action": {"Action", valueString}, "method": {"Method", valueString}, "type": {"Type", valueString},
+	"value": {"Value", valueString}, "for": {"For", valueString}, "placeholder": {"Placeholder", valueString},
+	"accept": {"Accept", valueString}, "autocomplete": {"Autocomplete", valueString},
+	"enctype": {"Enctype", valueString}, "novalidate": {"Novalidate", valuePresence},
+	"required": {"Required", valuePresence}, "disabled": {"Disabled", valuePresence},
+	"checked": {"Checked", valuePresence}, "multiple": {"Multiple", valuePresence},
+	"readonly": {"Readonly", valuePresence}, "autofocus": {"Autofocus", valuePresence},
+	"selected": {"Selected", valuePresence}, "rows": {"Rows", valueInt}, "cols": {"Cols", valueInt},
+	"maxlength": {"MaxLength", valueInt}, "minlength": {"MinLength", valueInt}, "size": {"Size", valueInt},
+	"min": {"Min", valueString}, "max": {"Max", valueString}, "step": {"Step", valueString},
+	"pattern": {"Pattern", valueString}, "form": {"Form", valueString},
+	"formaction": {"Formaction", valueString}, "formenctype": {"Formenctype", valueString},
+	"formmethod": {"Formmethod", valueString}, "formnovalidate": {"Formnovalidate", valuePresence},
+	"formtarget": {"Formtarget", valueString}, "list": {"List", valueString},
+
+	// Media
+	"src": {"Src", valueString}, "alt": {"Alt", valueString}, "width": {"Width", valueString},
+	"height": {"Height", valueString}, "crossorigin": {"Crossorigin", valueString},
+	"poster": {"Poster", valueString}, "preload": {"Preload", valueString},
+	"srcset": {"Srcset", valueString}, "sizes": {"Sizes", valueString}, "media": {"Media", valueString},
+	"autoplay": {"Autoplay", valuePresence}, "controls": {"Controls", valuePresence},
+	"loop": {"Loop", valuePresence}, "muted": {"Muted", valuePresence},
+	"open": {"Open", valuePresence}, "defer": {"Defer", valuePresence}, "async": {"Async", valuePresence},
+
+	// Tables and text semantics
+	"colspan": {"Colspan", valueInt}, "rowspan": {"Rowspan", valueInt},
+	"headers": {"Headers", valueString}, "scope": {"Scope", valueString},
+	"abbr": {"AbbrAttr", valueString}, "datetime": {"Datetime", valueString},
+	"cite": {"CiteAttr", valueString}, "high": {"High", valueFloat}, "low": {"Low", valueFloat},
+	"optimum": {"Optimum", valueFloat},
+
+	// htmx
+	"hx-get": {"HxGet", valueString}, "hx-post": {"HxPost", valueString},
+	"hx-put": {"HxPut", valueString}, "hx-delete": {"HxDelete", valueString},
+	"hx-patch": {"HxPatch", valueString}, "hx-target": {"HxTarget", valueString},
+	"hx-swap": {"HxSwap", valueString}, "hx-swap-oob": {"HtmxSwapOOB", valueString},
+	"hx-trigger": {"HxTrigger", valueString}, "hx-indicator": {"HxIndicator", valueString},
+	"hx-confirm": {"HxConfirm", valueString}, "hx-prompt": {"HtmxPrompt", valueString},
+	"hx-headers": {"HtmxHeaders", valueString}, "hx-vals": {"HxVals", valueString},
+	"hx-include": {"HxInclude", valueString}, "hx-push-url": {"HtmxPushURL", valueString},
+	"hx-replace-url": {"HtmxReplaceURL", valueString}, "hx-sync": {"HtmxSync", valueString},
+	"hx-ext": {"HtmxExt", valueString}, "hx-preserve": {"HtmxPreserve", valuePresence},
+
+	// ARIA
+	"aria-label": {"AriaLabel", valueString}, "aria-labelledby": {"AriaLabelledby", valueString},
+	"aria-describedby": {"AriaDescribedby", valueString}, "aria-hidden": {"AriaHidden", valueBool},
+	"aria-expanded": {"AriaExpanded", valueBool}, "aria-selected": {"AriaSelected", valueBool},
+	"aria-checked": {"AriaChecked", valueBool}, "aria-disabled": {"AriaDisabled", valueBool},
+
+	// SVG
+	"viewBox": {"ViewBox", valueString}, "fill": {"Fill", valueString}, "stroke": {"Stroke", valueString},
+	"stroke-width": {"StrokeWidth", valueString}, "points": {"Points", valueString},
+	"d": {"D", valueString}, "cx": {"Cx", valueString}, "cy": {"Cy", valueString}, "r": {"R", valueString},
+}
@@ -0,0 +1,442 @@
+package minty
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Compiled templates
+//
+// Most of a page skeleton (head, navigation, sidebar, footer) is the same
+// on every request, yet a template rebuilds it as an Element tree each
+// time. Compile evaluates a template once, renders everything static to
+// bytes and keeps holes for the parts that change:
+//
+//	var (
+//	    pageTitle = mi.NewHole[string]("title")
+//	    pageBody  = mi.NewHole[mi.H]("body")
+//	)
+//
+//	var page = mi.MustCompile(func(b *mi.Builder) mi.Node {
+//	    return b.Html(
+//	        b.Head(b.Title(pageTitle.String()), siteStyles(b)),
+//	        b.Body(siteNav(b), b.Main(pageBody), siteFooter(b)),
+//	    )
+//	})
+//
+//	mi.RenderHandler(page.With(pageTitle.Fill("Orders"), pageBody.Fill(ordersTable)))
+//
+// A Hole placed as a child is a content hole and may be filled with text,
+// a Node or an H. Hole.String returns a placeholder for APIs that take a
+// string, such as b.Title or a plain attribute value; the filled value is
+// escaped when it is written. Placeholders are rejected in URL, script and
+// style attributes and inside <script> and <style>, where escaping alone is
+// not enough.
+//
+// Rendering a compiled template mostly copies bytes. Asset slots and
+// b.Require calls in the static parts and FlushPoint stay live, so assets
+// required by hole content still reach <head>. Inline <script> and <style>
+// elements stay live too, so they carry the request's CSP nonce and are
+// hashed by a CSPCollector. Anything else in the static parts is frozen at
+// compile time: it must not depend on the builder's context (request,
+// locale, CSRF token), and inline on* handlers are not dropped in strict
+// CSP mode. FlushAfter cannot see tags inside static bytes; use FlushPoint
+// in compiled templates.
+
+// holeIDs numbers holes so placeholders are unique in the process.
+var holeIDs atomic.Int64
+
+// Hole is a named dynamic part of a compiled template whose value has
+// type T.
+type Hole[T any] struct {
+	name string
+	id   int64
+}
+
+// NewHole creates a hole. The name is used in error messages only.
+func NewHole[T any](name string) *Hole[T] {
+	return &Hole[T]{name: name, id: holeIDs.Add(1)}
+}
+
+// Name returns the hole's name.
+func (h *Hole[T]) Name() string {
+	return h.name
+}
+
+// Fill returns the value of the hole for one render.
+func (h *Hole[T]) Fill(value T) HoleValue {
+	return HoleValue{id: h.id, value: value}
+}
+
+// String returns the hole's placeholder for use where a string is
+// expected. It is replaced by the escaped value when rendered.
+func (h *Hole[T]) String() string {
+	return placeholderPrefix + strconv.FormatInt(h.id, 10) + placeholderSuffix
+}
+
+// Render records a content hole while compiling. Rendered outside Compile
+// it fails.
+func (h *Hole[T]) Render(w io.Writer) error {
+	c := compilerFor(w)
+	if c == nil {
+		return fmt.Errorf("minty: hole %q rendered outside a compiled template", h.name)
+	}
+	return c.hole(h.id)
+}
+
+// HoleValue fills one hole; see Hole.Fill.
+type HoleValue struct {
+	id    int64
+	value interface{}
+}
+
+const (
+	placeholderPrefix = "\x00minty-hole:"
+	placeholderSuffix = "\x00"
+)
+
+// segment is one part of a compiled template: static bytes followed by
+// at most one dynamic part.
+type segment struct {
+	static  []byte
+	hole    int64               // content hole id, or 0
+	text    int64               // placeholder hole id, or 0
+	dynamic func(*Builder) Node // live asset slot, require or flush point
+}
+
+// Compiled is a template pre-rendered to bytes with holes for its dynamic
+// parts. It is safe for concurrent use.
+type Compiled struct {
+	segments []segment
+	ids      []string // issued by UID in the static parts
+}
+
+// compiler records the dynamic parts of a template being compiled.
+type compiler struct {
+	rw       *renderWriter
+	buf      *bytes.Buffer
+	segments []segment
+	start    int
+}
+
+// compilerFor returns the compiler behind w, if w is rendering for Compile.
+func compilerFor(w io.Writer) *compiler {
+	if rw, ok := w.(*renderWriter); ok {
+		return rw.compile
+	}
+	return nil
+}
+
+// cut ends the current static run and returns its bytes.
+func (c *compiler) cut() ([]byte, error) {
+	if err := c.rw.Writer.Flush(); err != nil {
+		return nil, err
+	}
+	static := append([]byte(nil), c.buf.Bytes()[c.start:]...)
+	c.start = c.buf.Len()
+	return static, nil
+}
+
+func (c *compiler) hole(id int64) error {
+	static, err := c.cut()
+	c.segments = append(c.segments, segment{static: static, hole: id})
+	return err
+}
+
+func (c *compiler) live(fn func(*Builder) Node) error {
+	static, err := c.cut()
+	c.segments = append(c.segments, segment{static: static, dynamic: fn})
+	return err
+}
+
+// inline records an inline script or style as a live segment, rendered
+// per request with the builder's CSP nonce.
+func (c *compiler) inline(e *Element) error {
+	for _, attr := range e.Attributes {
+		if err := c.checkAttribute(e.Tag, attr); err != nil {
+			return err
+		}
+	}
+	if err := c.checkRawText(e); err != nil {
+		return err
+	}
+	return c.live(func(b *Builder) Node {
+		el := *e
+		el.Attributes = append(AttributeList(nil), e.Attributes...)
+		return b.withNonce(&el)
+	})
+}
+
+// checkAttribute rejects placeholders where escaping does not make the
+// value safe.
+func (c *compiler) checkAttribute(tag string, attr AttributePair) error {
+	if attr.trusted || !strings.Contains(attr.Value, placeholderPrefix) {
+		return nil
+	}
+	if classifyAttribute(tag, attr.Name) != contextPlain {
+		return fmt.Errorf("minty: hole placeholder in %s attribute of <%s>; only plain attributes can hold placeholders", attr.Name, tag)
+	}
+	return nil
+}
+
+// checkRawText rejects placeholders inside <script> and <style>.
+func (c *compiler) checkRawText(e *Element) error {
+	if e.Tag != "script" && e.Tag != "style" {
+		return nil
+	}
+	for _, child := range e.Children {
+		var content string
+		switch n := child.(type) {
+		case *TextNode:
+			content = n.Content
+		case *RawNode:
+			content = n.Content
+		}
+		if strings.Contains(content, placeholderPrefix) {
+			return fmt.Errorf("minty: hole placeholder inside <%s>", e.Tag)
+		}
+	}
+	return nil
+}
+
+// Compile evaluates template once and pre-renders its static parts. The
+// template is built with a builder carrying no request context.
+func Compile(template H) (*Compiled, error) {
+	b := NewBuilder(context.Background())
+	node := template(b)
+	if err := checkStrict(b.Context(), node); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	rw := acquireRenderWriter(&buf)
+	defer releaseRenderWriter(rw)
+	c := &compiler{rw: rw, buf: &buf}
+	rw.compile = c
+	if err := node.Render(rw); err != nil {
+		return nil, err
+	}
+	static, err := c.cut()
+	if err != nil {
+		return nil, err
+	}
+	c.segments = append(c.segments, segment{static: static})
+
+	// Split static runs at string placeholders
+	compiled := &Compiled{ids: b.ids.list()}
+	for _, seg := range c.segments {
+		parts, err := splitPlaceholders(seg.static)
+		if err != nil {
+			return nil, err
+		}
+		compiled.segments = append(compiled.segments, parts[:len(parts)-1]...)
+		last := parts[len(parts)-1]
+		seg.static = last.static
+		compiled.segments = append(compiled.segments, seg)
+	}
+	return compiled, nil
+}
+
+// MustCompile is like Compile but panics on error. Use it for templates
+// compiled at package initialisation.
+func MustCompile(template H) *Compiled {
+	c, err := Compile(template)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// splitPlaceholders cuts static bytes at each placeholder. The last
+// segment holds the bytes after the final placeholder.
+func splitPlaceholders(static []byte) ([]segment, error) {
+	var out []segment
+	prefix := []byte(placeholderPrefix)
+	for {
+		i := bytes.Index(static, prefix)
+		if i < 0 {
+			return append(out, segment{static: static}), nil
+		}
+		rest := static[i+len(prefix):]
+		end := bytes.IndexByte(rest, placeholderSuffix[0])
+		if end < 0 {
+			return nil, errors.New("minty: truncated hole placeholder")
+		}
+		id, err := strconv.ParseInt(string(rest[:end]), 10, 64)
+		if err != nil {
+			return nil, errors.New("minty: malformed hole placeholder")
+		}
+		out = append(out, segment{static: static[:i], text: id})
+		static = rest[end+1:]
+	}
+}
+
+// With returns a template rendering the compiled output with the holes
+// filled in. Unfilled holes render nothing.
+func (c *Compiled) With(values ...HoleValue) H {
+	return func(b *Builder) Node {
+		if b.ids != nil {
+			b.ids.reserve(c.ids)
+		}
+		n := &compiledNode{c: c, values: values, nodes: make([]Node, len(c.segments))}
+		for i, seg := range c.segments {
+			switch {
+			case seg.hole != 0:
+				n.nodes[i] = holeNode(b, n.value(seg.hole))
+			case seg.dynamic != nil:
+				n.nodes[i] = seg.dynamic(b)
+			}
+		}
+		return n
+	}
+}
+
+// Render renders the compiled template with the holes filled in.
+func (c *Compiled) Render(w io.Writer, values ...HoleValue) error {
+	return Render(c.With(values...), w)
+}
+
+// compiledNode is one render of a compiled template.
+type compiledNode struct {
+	c      *Compiled
+	values []HoleValue
+	nodes  []Node // built content per segment, nil for static-only
+}
+
+func (n *compiledNode) value(id int64) interface{} {
+	for _, v := range n.values {
+		if v.id == id {
+			return v.value
+		}
+	}
+	return nil
+}
+
+// Render writes the static bytes and the dynamic parts in order.
+func (n *compiledNode) Render(w io.Writer) error {
+	sw, ok := bufferedWriter(w)
+	if !ok {
+		return renderBuffered(n, w)
+	}
+	for i, seg := range n.c.segments {
+		if _, err := sw.Write(seg.static); err != nil {
+			return err
+		}
+		if seg.text != 0 {
+			if err := writeEscaped(sw, holeText(n.value(seg.text))); err != nil {
+				return err
+			}
+		}
+		if node := n.nodes[i]; node != nil {
+			if err := node.Render(sw); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// holeNode converts a hole value to content.
+func holeNode(b *Builder, v interface{}) Node {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case Node:
+		return x
+	case H:
+		if x == nil {
+			return nil
+		}
+		return x(b)
+	case func(*Builder) Node:
+		if x == nil {
+			return nil
+		}
+		return x(b)
+	}
+	return &TextNode{Content: holeText(v)}
+}
+
+// holeText converts a hole value to text for a placeholder.
+func holeText(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case int:
+		return strconv.Itoa(x)
+	case fmt.Stringer:
+		return x.String()
+	}
+	return fmt.Sprint(v)
+}
+
+// Compiled layouts
+
+var (
+	layoutTitle   = NewHole[string]("title")
+	layoutContent = NewHole[H]("content")
+)
+
+// CompileDocument compiles a document wrapper such as Layout or a theme's
+// BootstrapDocument once for a set of options and returns a function that
+// only fills in the title and content:
+//
+//	var page = mi.CompileDocument(bootstrap.BootstrapDocument, mi.DocumentLang("en-GB"))
+//
+//	mi.RenderHandler(page("Orders", ordersTable))
+//
+// Properties that default to the title, such as og:title, are filled in
+// with it as well, so the output matches calling the wrapper directly.
+func CompileDocument(wrapper func(string, H, ...DocumentOption) H, opts ...DocumentOption) func(title string, content H) H {
+	c := MustCompile(wrapper(layoutTitle.String(), func(b *Builder) Node { return layoutContent }, opts...))
+	return func(title string, content H) H {
+		return c.With(layoutTitle.Fill(title), layoutContent.Fill(content))
+	}
+}
+
+// CompileFullLayout is CompileDocument for FullLayout. Sections passed as
+// nil are left out, as with FullLayout.
+func CompileFullLayout(opts ...DocumentOption) func(title string, nav, main, aside, footer H) H {
+	holes := [4]*Hole[H]{NewHole[H]("nav"), NewHole[H]("main"), NewHole[H]("aside"), NewHole[H]("footer")}
+	var (
+		mu       sync.Mutex
+		variants [8]*Compiled // by which of the optional sections are present
+	)
+	compiled := func(nav, aside, footer bool) *Compiled {
+		key := 0
+		for i, present := range []bool{nav, aside, footer} {
+			if present {
+				key |= 1 << i
+			}
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if variants[key] == nil {
+			section := func(present bool, hole *Hole[H]) H {
+				if !present {
+					return nil
+				}
+				return func(*Builder) Node { return hole }
+			}
+			variants[key] = MustCompile(FullLayout(layoutTitle.String(),
+				section(nav, holes[0]), section(true, holes[1]), section(aside, holes[2]), section(footer, holes[3]),
+				opts...))
+		}
+		return variants[key]
+	}
+	return func(title string, nav, main, aside, footer H) H {
+		return compiled(nav != nil, aside != nil, footer != nil).With(
+			layoutTitle.Fill(title),
+			holes[0].Fill(nav), holes[1].Fill(main), holes[2].Fill(aside), holes[3].Fill(footer),
+		)
+	}
+}
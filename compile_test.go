@@ -0,0 +1,191 @@
+package minty
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCompiledMatchesDirectRender(t *testing.T) {
+	title := NewHole[string]("title")
+	user := NewHole[string]("user")
+	body := NewHole[H]("body")
+
+	skeleton := func(b *Builder, title, user string, body Node) Node {
+		return b.Html(
+			b.Head(b.Title(title)),
+			b.Body(
+				b.Nav(Class("site-nav"), b.A(Href("/"), "Home"), b.Span(DataAttr("user", user), user)),
+				b.Main(body),
+				b.Footer("© Minty"),
+			),
+		)
+	}
+	compiled, err := Compile(func(b *Builder) Node {
+		return skeleton(b, title.String(), user.String(), body)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := func(b *Builder) Node { return b.P("Orders & <returns>") }
+	got, err := RenderToStringE(compiled.With(title.Fill(`Q&A "2025"`), user.Fill("<ada>"), body.Fill(content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RenderToString(func(b *Builder) Node {
+		return skeleton(b, `Q&A "2025"`, "<ada>", content(b))
+	})
+	if got != want {
+		t.Errorf("compiled output differs\n got: %s\nwant: %s", got, want)
+	}
+
+	// Unfilled holes render nothing
+	empty := RenderToString(compiled.With())
+	if !strings.Contains(empty, "<title></title>") || !strings.Contains(empty, "<main></main>") {
+		t.Errorf("unfilled holes should be empty: %s", empty)
+	}
+}
+
+func TestCompiledKeepsAssetsLive(t *testing.T) {
+	body := NewHole[H]("body")
+	page := MustCompile(Document("Shop", nil, B.Body(body)))
+
+	html := RenderToString(page.With(body.Fill(widget)))
+	css := strings.Index(html, "widget.css")
+	if css < 0 || css > strings.Index(html, "</head>") {
+		t.Errorf("asset required by hole content should be in <head>:\n%s", html)
+	}
+	if !strings.Contains(html, `<script src="/static/widget.js"></script></body>`) {
+		t.Errorf("body asset missing:\n%s", html)
+	}
+	if again := RenderToString(page.With()); strings.Contains(again, "widget") {
+		t.Errorf("assets leaked between renders:\n%s", again)
+	}
+}
+
+func TestCompileRejectsUnsafePlaceholders(t *testing.T) {
+	link := NewHole[string]("link")
+	tests := map[string]H{
+		"url attribute": func(b *Builder) Node { return b.A(Href(link.String()), "x") },
+		"event handler": func(b *Builder) Node { return b.Button(Attr("onclick", link.String())) },
+		"script":        func(b *Builder) Node { return b.Script(Raw("var x = '" + link.String() + "'")) },
+	}
+	for name, template := range tests {
+		if _, err := Compile(template); err == nil || !strings.Contains(err.Error(), "placeholder") {
+			t.Errorf("%s: expected placeholder error, got %v", name, err)
+		}
+	}
+
+	if _, err := Compile(func(b *Builder) Node { return b.Div(Title(link.String())) }); err != nil {
+		t.Errorf("plain attributes may hold placeholders: %v", err)
+	}
+	if _, err := RenderToStringE(func(b *Builder) Node { return b.Div(link) }); err == nil {
+		t.Error("a hole rendered outside Compile should fail")
+	}
+}
+
+func TestCompileDocumentAndFullLayout(t *testing.T) {
+	page := CompileDocument(Layout, DocumentLang("en-GB"))
+	content := func(b *Builder) Node { return b.H1("Hi") }
+	got := RenderToString(page("Welcome <home>", content))
+	want := RenderToString(Layout("Welcome <home>", content, DocumentLang("en-GB")))
+	if got != want {
+		t.Errorf("compiled Layout differs\n got: %s\nwant: %s", got, want)
+	}
+
+	full := CompileFullLayout()
+	nav := func(b *Builder) Node { return b.A(Href("/"), "Home") }
+	footer := func(b *Builder) Node { return b.Footer("fin") }
+	for _, sections := range [][3]H{{nav, nil, footer}, {nil, nil, nil}, {nav, content, footer}} {
+		got := RenderToString(full("Page", sections[0], content, sections[1], sections[2]))
+		want := RenderToString(FullLayout("Page", sections[0], content, sections[1], sections[2]))
+		if got != want {
+			t.Errorf("compiled FullLayout differs\n got: %s\nwant: %s", got, want)
+		}
+	}
+}
+
+func TestCompiledInlineBlocksGetNonceAndHash(t *testing.T) {
+	themed := func(title string, content H, opts ...DocumentOption) H {
+		return func(b *Builder) Node {
+			return Document(title, []Node{b.Style(Raw("body{margin:0}"))}, b.Body(content(b), b.Script(Raw("init()"))), opts...)(b)
+		}
+	}
+	page := CompileDocument(themed)
+	content := func(b *Builder) Node { return b.P("Hi") }
+
+	collector := &CSPCollector{}
+	ctx := WithCSPCollector(CSPNonce.With(context.Background(), "n0nce"), collector)
+	var got strings.Builder
+	if err := RenderWithContext(ctx, page("Home", content), &got); err != nil {
+		t.Fatal(err)
+	}
+	var want strings.Builder
+	RenderWithContext(CSPNonce.With(context.Background(), "n0nce"), themed("Home", content), &want)
+	if got.String() != want.String() {
+		t.Errorf("compiled output differs\n got: %s\nwant: %s", got.String(), want.String())
+	}
+	if !strings.Contains(got.String(), `<style nonce="n0nce">`) || !strings.Contains(got.String(), `<script nonce="n0nce">`) {
+		t.Errorf("inline blocks should carry the request nonce: %s", got.String())
+	}
+	if len(collector.ScriptHashes()) != 1 || len(collector.StyleHashes()) != 1 {
+		t.Errorf("inline blocks should be hashed: %v %v", collector.ScriptHashes(), collector.StyleHashes())
+	}
+	if again := RenderToString(page("Home", content)); strings.Contains(again, "nonce") {
+		t.Errorf("nonce leaked between renders: %s", again)
+	}
+}
+
+func TestCompiledStreamsAtFlushPoints(t *testing.T) {
+	body := NewHole[H]("body")
+	page := MustCompile(func(b *Builder) Node {
+		return b.Html(b.Head(b.Title("t")), FlushPoint(), b.Body(body))
+	})
+	w := &flushRecorder{}
+	if err := RenderStream(page.With(body.Fill(func(b *Builder) Node { return b.P("late") })), w, FlushAfter()); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.flushes) < 2 || !strings.HasSuffix(w.flushes[0], "</head>") {
+		t.Errorf("expected a flush after </head>, got %q", w.flushes)
+	}
+}
+
+func BenchmarkLayout(b *testing.B) {
+	content := func(b *Builder) Node { return b.P("Welcome back") }
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Render(bootstrapLikeDocument("Dashboard", content), io.Discard)
+	}
+}
+
+func BenchmarkCompiledLayout(b *testing.B) {
+	content := func(b *Builder) Node { return b.P("Welcome back") }
+	page := CompileDocument(bootstrapLikeDocument)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Render(page("Dashboard", content), io.Discard)
+	}
+}
+
+// bootstrapLikeDocument mirrors the theme document wrappers: a Document
+// with CDN links, a navigation bar, a sidebar and a footer around content.
+func bootstrapLikeDocument(title string, content H, opts ...DocumentOption) H {
+	return func(b *Builder) Node {
+		links := make([]interface{}, 0, 20)
+		for i := 0; i < 20; i++ {
+			links = append(links, b.Li(Class("nav-item"), b.A(Class("nav-link"), Href("/section/"+string(rune('a'+i))), "Section ", i)))
+		}
+		return Document(title, []Node{
+			b.Link(Rel("stylesheet"), Href("https://cdn.example.com/bootstrap.min.css")),
+		}, b.Body(
+			b.Nav(Class("navbar navbar-expand-lg"), b.Div(Class("container-fluid"), b.A(Class("navbar-brand"), Href("/"), "Minty"))),
+			b.Div(Class("container-fluid"), b.Div(Class("row"),
+				b.Aside(Class("col-3 sidebar"), b.Ul(append([]interface{}{Class("nav flex-column")}, links...)...)),
+				b.Main(Class("col-9"), content(b)),
+			)),
+			b.Footer(Class("footer"), b.P("© Minty"), b.P("All rights reserved")),
+		), opts...)(b)
+	}
+}
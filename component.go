@@ -0,0 +1,364 @@
+package minty
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Components
+//
+// A TypedComponent bundles a template with typed props, defaults, validation
+// and named slots, so callers name what they pass instead of lining up
+// positional arguments:
+//
+//	type AlertProps struct {
+//	    Kind        string
+//	    Dismissible bool
+//	}
+//
+//	var Alert = mi.NewComponent("alert", func(b *mi.Builder, p AlertProps, s mi.Slots) mi.Node {
+//	    return b.Div(mi.Class("alert alert-"+p.Kind),
+//	        s.Render(b, mi.SlotHeader),
+//	        s.Children(b),
+//	    )
+//	}, mi.WithDefaults(AlertProps{Kind: "info"}))
+//
+//	Alert.H(AlertProps{Dismissible: true},
+//	    mi.Slot(mi.SlotHeader, heading),
+//	    body, "Plain text works too",
+//	)
+//
+// Inside another template, Alert.Build(b, props, ...) returns the node
+// directly. Invalid props fail the render with an error naming the
+// component.
+//
+// WithDefaults fills every field left at its zero value, so a caller
+// cannot pass an explicit false, 0 or "" for a field whose default is not
+// zero. Props types that need that implement PropsDefaulter and fill
+// their own defaults, typically with pointer fields:
+//
+//	type AlertProps struct {
+//	    Kind        string
+//	    Dismissible *bool // nil means true
+//	}
+//
+//	func (p AlertProps) Defaults() AlertProps {
+//	    if p.Dismissible == nil {
+//	        dismissible := true
+//	        p.Dismissible = &dismissible
+//	    }
+//	    return p
+//	}
+
+// Common slot names.
+const (
+	SlotHeader  = "header"
+	SlotFooter  = "footer"
+	SlotActions = "actions"
+)
+
+// SlotContent fills a named slot of a component.
+type SlotContent struct {
+	Name    string
+	Content H
+}
+
+// Slot fills the named slot with content. A nil content leaves the slot
+// empty.
+func Slot(name string, content H) SlotContent {
+	return SlotContent{Name: name, Content: content}
+}
+
+// Slots holds the content passed to a component: named slots and the
+// default children.
+type Slots struct {
+	named    map[string]H
+	children []interface{}
+}
+
+// Has reports whether the named slot was filled.
+func (s Slots) Has(name string) bool {
+	return s.named[name] != nil
+}
+
+// Get returns the template for the named slot, or nil.
+func (s Slots) Get(name string) H {
+	return s.named[name]
+}
+
+// Render builds the named slot, or returns an empty fragment if it was
+// not filled.
+func (s Slots) Render(b *Builder, name string) Node {
+	if content := s.named[name]; content != nil {
+		return content(b)
+	}
+	return NewFragment()
+}
+
+// HasChildren reports whether any default children were passed.
+func (s Slots) HasChildren() bool {
+	return len(s.children) > 0
+}
+
+// Children builds the default children as a fragment.
+func (s Slots) Children(b *Builder) Node {
+	nodes := make([]Node, 0, len(s.children))
+	for _, child := range s.children {
+		switch v := child.(type) {
+		case H:
+			nodes = append(nodes, v(b))
+		case func(*Builder) Node:
+			nodes = append(nodes, v(b))
+		case Node:
+			nodes = append(nodes, v)
+		case string:
+			nodes = append(nodes, &TextNode{Content: v})
+		}
+	}
+	return NewFragment(nodes...)
+}
+
+// TypedComponent is a template with typed props P. It is not named
+// Component, which is the older class-wrapper function.
+type TypedComponent[P any] struct {
+	name     string
+	render   func(*Builder, P, Slots) Node
+	defaults *P
+	validate func(P) error
+}
+
+// ComponentOption configures a TypedComponent.
+type ComponentOption[P any] func(*TypedComponent[P])
+
+// WithDefaults sets the props used for fields the caller leaves at their
+// zero value. For non-struct props, defaults replace a zero value. An
+// explicit zero value cannot be told apart from an unset field and is
+// replaced too; see PropsDefaulter.
+func WithDefaults[P any](defaults P) ComponentOption[P] {
+	return func(c *TypedComponent[P]) {
+		c.defaults = &defaults
+	}
+}
+
+// WithValidation checks props, after defaults are applied, before each
+// render.
+func WithValidation[P any](validate func(P) error) ComponentOption[P] {
+	return func(c *TypedComponent[P]) {
+		c.validate = validate
+	}
+}
+
+// NewComponent creates a component. The name identifies it in errors.
+func NewComponent[P any](name string, render func(b *Builder, props P, slots Slots) Node, opts ...ComponentOption[P]) *TypedComponent[P] {
+	c := &TypedComponent[P]{name: name, render: render}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Name returns the component name.
+func (c *TypedComponent[P]) Name() string {
+	return c.name
+}
+
+// H returns a template rendering the component with props. Content may
+// be SlotContent for named slots, or H, Node and string values for the
+// default children.
+func (c *TypedComponent[P]) H(props P, content ...interface{}) H {
+	return func(b *Builder) Node {
+		return c.Build(b, props, content...)
+	}
+}
+
+// Build renders the component with b, for use inside another template.
+func (c *TypedComponent[P]) Build(b *Builder, props P, content ...interface{}) Node {
+	props, err := c.Props(props)
+	if err != nil {
+		return errorNode{err}
+	}
+	slots, err := c.slots(content)
+	if err != nil {
+		return errorNode{err}
+	}
+	return c.render(b, props, slots)
+}
+
+// PropsDefaulter is implemented by props that fill their own defaults.
+// Defaults returns the props with unset fields filled in; it runs instead
+// of the WithDefaults option.
+type PropsDefaulter[P any] interface {
+	Defaults() P
+}
+
+// Props applies the defaults to props and validates the result.
+func (c *TypedComponent[P]) Props(props P) (P, error) {
+	if d, ok := any(props).(PropsDefaulter[P]); ok {
+		props = d.Defaults()
+	} else if c.defaults != nil {
+		props = withDefaults(props, *c.defaults)
+	}
+	if c.validate != nil {
+		if err := c.validate(props); err != nil {
+			return props, c.errorf("%w", err)
+		}
+	}
+	return props, nil
+}
+
+// slots sorts content into named slots and default children.
+func (c *TypedComponent[P]) slots(content []interface{}) (Slots, error) {
+	var s Slots
+	for _, item := range content {
+		switch v := item.(type) {
+		case nil:
+		case SlotContent:
+			if v.Content == nil {
+				continue
+			}
+			if s.named == nil {
+				s.named = make(map[string]H)
+			}
+			s.named[v.Name] = v.Content
+		case H:
+			if v != nil {
+				s.children = append(s.children, v)
+			}
+		case func(*Builder) Node:
+			if v != nil {
+				s.children = append(s.children, v)
+			}
+		case Node, string:
+			s.children = append(s.children, v)
+		default:
+			return s, c.errorf("unsupported content of type %T", item)
+		}
+	}
+	return s, nil
+}
+
+func (c *TypedComponent[P]) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("minty: component %s: "+format, append([]interface{}{c.name}, args...)...)
+}
+
+// withDefaults fills the zero fields of props from defaults.
+func withDefaults[P any](props, defaults P) P {
+	pv := reflect.ValueOf(&props).Elem()
+	if pv.Kind() != reflect.Struct {
+		if pv.IsZero() {
+			return defaults
+		}
+		return props
+	}
+	dv := reflect.ValueOf(defaults)
+	for i := 0; i < pv.NumField(); i++ {
+		if field := pv.Field(i); field.CanSet() && field.IsZero() {
+			field.Set(dv.Field(i))
+		}
+	}
+	return props
+}
+
+// Built-in components
+
+// CardProps configures Card.
+type CardProps struct {
+	Title string
+}
+
+// Card renders an article.card. The header holds the title and the header
+// slot; the actions and footer slots render in footers after the content.
+var Card = NewComponent("card", func(b *Builder, p CardProps, s Slots) Node {
+	return b.Article(Class("card"),
+		If(p.Title != "" || s.Has(SlotHeader), func(b *Builder) Node {
+			return b.Header(Class("card-header"),
+				If(p.Title != "", func(b *Builder) Node {
+					return b.H3(Class("card-title"), p.Title)
+				})(b),
+				s.Render(b, SlotHeader),
+			)
+		})(b),
+
+		b.Section(Class("card-content"),
+			s.Children(b),
+		),
+
+		If(s.Has(SlotActions), func(b *Builder) Node {
+			return b.Footer(Class("card-actions"),
+				s.Render(b, SlotActions),
+			)
+		})(b),
+
+		If(s.Has(SlotFooter), func(b *Builder) Node {
+			return b.Footer(Class("card-footer"),
+				s.Render(b, SlotFooter),
+			)
+		})(b),
+	)
+})
+
+// ModalProps configures Modal.
+type ModalProps struct {
+	ID         string // used to open and close the dialog
+	Title      string
+	CloseLabel string // defaults to "×"; an empty label is replaced too
+}
+
+// Modal renders a dialog.modal with a title bar, the content and an
+// optional footer for the actions slot. The header slot renders after the
+// title.
+var Modal = NewComponent("modal", func(b *Builder, p ModalProps, s Slots) Node {
+	return b.Dialog(ID(p.ID), Class("modal"),
+		b.Header(Class("modal-header"),
+			b.H2(Class("modal-title"), p.Title),
+			s.Render(b, SlotHeader),
+			b.Button(Class("modal-close"), Type("button"), p.CloseLabel),
+		),
+
+		b.Section(Class("modal-body"),
+			s.Children(b),
+		),
+
+		If(s.Has(SlotActions), func(b *Builder) Node {
+			return b.Footer(Class("modal-footer"),
+				s.Render(b, SlotActions),
+			)
+		})(b),
+	)
+}, WithDefaults(ModalProps{CloseLabel: "×"}))
+
+// PageProps configures Page.
+type PageProps struct {
+	Title   string
+	Options []DocumentOption
+}
+
+// Slot names used by Page.
+const (
+	SlotNav   = "nav"
+	SlotAside = "aside"
+)
+
+// Page renders a complete HTML5 document. The nav slot is wrapped in a
+// <header>, the children in <main>, and the aside and footer slots follow
+// as they are.
+var Page = NewComponent("page", func(b *Builder, p PageProps, s Slots) Node {
+	o := newDocumentOptions(p.Options)
+	return b.Html(append(o.htmlAttributes(),
+		b.Head(append([]interface{}{
+			b.Meta(Charset("utf-8")),
+			b.Meta(Name("viewport"), Content("width=device-width, initial-scale=1")),
+			b.Title(p.Title),
+		}, o.headNodes(b, p.Title)...)...),
+		b.Body(
+			If(s.Has(SlotNav), func(b *Builder) Node {
+				return b.Header(s.Render(b, SlotNav))
+			})(b),
+
+			b.Main(s.Children(b)),
+
+			s.Render(b, SlotAside),
+			s.Render(b, SlotFooter),
+		),
+	)...)
+})
@@ -0,0 +1,103 @@
+package minty
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type badgeProps struct {
+	Label string
+	Kind  string
+	Count int
+}
+
+var badge = NewComponent("badge", func(b *Builder, p badgeProps, s Slots) Node {
+	return b.Span(Class("badge badge-"+p.Kind), p.Label, " ", p.Count, s.Children(b))
+}, WithDefaults(badgeProps{Kind: "info", Count: 1}), WithValidation(func(p badgeProps) error {
+	if p.Label == "" {
+		return errors.New("label is required")
+	}
+	return nil
+}))
+
+func TestComponentDefaultsAndValidation(t *testing.T) {
+	got := RenderToString(badge.H(badgeProps{Label: "New", Count: 3}))
+	if got != `<span class="badge badge-info">New 3</span>` {
+		t.Errorf("defaults should only fill zero fields, got %s", got)
+	}
+
+	_, err := RenderToStringE(badge.H(badgeProps{}))
+	if err == nil || err.Error() != "minty: component badge: label is required" {
+		t.Errorf("expected validation error, got %v", err)
+	}
+
+	if _, err := RenderToStringE(badge.H(badgeProps{Label: "x"}, 42)); err == nil || !strings.Contains(err.Error(), "unsupported content of type int") {
+		t.Errorf("expected content error, got %v", err)
+	}
+}
+
+type toggleProps struct {
+	Label string
+	On    *bool // nil means on
+}
+
+func (p toggleProps) Defaults() toggleProps {
+	if p.On == nil {
+		on := true
+		p.On = &on
+	}
+	return p
+}
+
+func TestComponentPropsDefaulter(t *testing.T) {
+	toggle := NewComponent("toggle", func(b *Builder, p toggleProps, s Slots) Node {
+		return b.Button(Attr("aria-pressed", fmt.Sprint(*p.On)), p.Label)
+	}, WithDefaults(toggleProps{Label: "ignored"}))
+
+	off := false
+	if got := RenderToString(toggle.H(toggleProps{Label: "Mute", On: &off})); got != `<button aria-pressed="false">Mute</button>` {
+		t.Errorf("explicit false should be kept, got %s", got)
+	}
+	if got := RenderToString(toggle.H(toggleProps{})); got != `<button aria-pressed="true"></button>` {
+		t.Errorf("Defaults should replace WithDefaults, got %s", got)
+	}
+}
+
+func TestComponentSlotsAndChildren(t *testing.T) {
+	heading := func(b *Builder) Node { return b.Em("Featured") }
+	got := RenderToString(func(b *Builder) Node {
+		return b.Div(Card.Build(b, CardProps{Title: "Plan"},
+			Slot(SlotHeader, heading),
+			Slot(SlotFooter, nil),
+			func(b *Builder) Node { return b.P("Body") },
+			b.Hr(),
+			"Text",
+			badge.H(badgeProps{Label: "Pro"}),
+		))
+	})
+	want := `<div><article class="card"><header class="card-header"><h3 class="card-title">Plan</h3><em>Featured</em></header>` +
+		`<section class="card-content"><p>Body</p><hr />Text<span class="badge badge-info">Pro 1</span></section></article></div>`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestLayoutsUseComponents(t *testing.T) {
+	content := func(b *Builder) Node { return b.P("Sure?") }
+	actions := func(b *Builder) Node { return b.Button("OK") }
+
+	got := RenderToString(ModalLayout("confirm", "Delete", content, actions))
+	want := `<dialog id="confirm" class="modal"><header class="modal-header"><h2 class="modal-title">Delete</h2>` +
+		`<button class="modal-close" type="button">×</button></header><section class="modal-body"><p>Sure?</p></section>` +
+		`<footer class="modal-footer"><button>OK</button></footer></dialog>`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	got = RenderToString(CardLayout("", content, nil))
+	if got != `<article class="card"><section class="card-content"><p>Sure?</p></section></article>` {
+		t.Errorf("card without title or actions = %s", got)
+	}
+}
@@ -0,0 +1,140 @@
+package minty
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Render context
+//
+// A Builder can carry a context.Context. Components receive the builder as
+// b, so anything stored in the context (the request, locale, CSRF token,
+// current user, deadline) is reachable without threading extra parameters
+// through every function:
+//
+//	func Greeting(b *mi.Builder) mi.Node {
+//	    return b.P("Hello, ", CurrentUser.Get(b).Name)
+//	}
+//
+// Render, RenderHandler, RenderHandlerFunc, HTMXHandler and HTMXHandlerFunc
+// build the context from the *http.Request. Rendering stops with the
+// context's error once it is cancelled or its deadline passes.
+//
+// The global B builder carries no context; helpers that evaluate templates
+// with B (Each, Filter, Range...) see context.Background().
+
+// NewBuilder creates a builder bound to ctx for building one page. Unlike
+// the global B it also collects the page's assets (see Require) and
+// scopes the ids handed out by UID.
+func NewBuilder(ctx context.Context) *Builder {
+	return &Builder{ctx: ctx, assets: &assetRegistry{}, ids: &idGenerator{}}
+}
+
+// Context returns the builder's context, or context.Background() if none.
+func (b *Builder) Context() context.Context {
+	if b == nil || b.ctx == nil {
+		return context.Background()
+	}
+	return b.ctx
+}
+
+// Request returns the HTTP request being rendered, or nil outside a handler.
+func (b *Builder) Request() *http.Request {
+	r, _ := requestKey.From(b.Context())
+	return r
+}
+
+// ContextKey is a typed key for request-scoped render values.
+//
+//	var CurrentUser = mi.NewContextKey[*User]("user")
+//
+//	ctx := CurrentUser.With(r.Context(), user)   // in middleware
+//	user := CurrentUser.Get(b)                   // in a component
+type ContextKey[T any] struct {
+	name string
+}
+
+// NewContextKey creates a key; the name is used only for debugging.
+func NewContextKey[T any](name string) *ContextKey[T] {
+	return &ContextKey[T]{name: name}
+}
+
+// String returns the key's name.
+func (k *ContextKey[T]) String() string {
+	return "minty context key " + k.name
+}
+
+// With returns a copy of ctx carrying value under the key.
+func (k *ContextKey[T]) With(ctx context.Context, value T) context.Context {
+	return context.WithValue(ctx, k, value)
+}
+
+// From returns the value stored in ctx and whether it was present.
+func (k *ContextKey[T]) From(ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(k).(T)
+	return v, ok
+}
+
+// Get returns the value visible to the builder, or the zero value.
+func (k *ContextKey[T]) Get(b *Builder) T {
+	v, _ := k.From(b.Context())
+	return v
+}
+
+// Common request-scoped values.
+var (
+	// Locale holds the request's locale, e.g. "en-GB".
+	Locale = NewContextKey[string]("locale")
+
+	// CSRFToken holds the anti-forgery token for forms rendered in the request.
+	CSRFToken = NewContextKey[string]("csrf-token")
+
+	requestKey = NewContextKey[*http.Request]("request")
+)
+
+// RequestContext returns r's context with r attached, so that components
+// can call b.Request().
+func RequestContext(r *http.Request) context.Context {
+	return requestKey.With(r.Context(), r)
+}
+
+// RenderWithContext renders a template with a builder bound to ctx and stops
+// with ctx.Err() if the context is cancelled before rendering completes.
+func RenderWithContext(ctx context.Context, template H, w io.Writer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	node := template(NewBuilder(ctx))
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := checkStrict(ctx, node); err != nil {
+		return err
+	}
+
+	rw := acquireRenderWriter(w)
+	defer releaseRenderWriter(rw)
+	rw.ctx = ctx
+	rw.csp, _ = cspCollectorKey.From(ctx)
+	if err := node.Render(rw); err != nil {
+		return err
+	}
+	return rw.Writer.Flush()
+}
+
+// contextCheckInterval is how many elements are rendered between checks of
+// the render context, keeping the cost of cancellation support negligible.
+const contextCheckInterval = 64
+
+// checkContext returns the context error every contextCheckInterval calls.
+func (rw *renderWriter) checkContext() error {
+	if rw.ctx == nil {
+		return nil
+	}
+	rw.elements++
+	if rw.elements%contextCheckInterval != 0 {
+		return nil
+	}
+	return rw.ctx.Err()
+}
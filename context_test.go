@@ -0,0 +1,91 @@
+package minty
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContextKeyRoundTrip(t *testing.T) {
+	user := NewContextKey[string]("user")
+	ctx := user.With(Locale.With(context.Background(), "en-GB"), "ada")
+
+	var got string
+	template := func(b *Builder) Node {
+		got = user.Get(b) + "/" + Locale.Get(b)
+		return b.P(user.Get(b))
+	}
+
+	var sb strings.Builder
+	if err := RenderWithContext(ctx, template, &sb); err != nil {
+		t.Fatalf("RenderWithContext returned error: %v", err)
+	}
+	if got != "ada/en-GB" {
+		t.Errorf("component saw %q, want %q", got, "ada/en-GB")
+	}
+	if sb.String() != "<p>ada</p>" {
+		t.Errorf("unexpected output: %s", sb.String())
+	}
+	if v := user.Get(B); v != "" {
+		t.Errorf("global builder should see zero value, got %q", v)
+	}
+}
+
+func TestRenderHandlerFuncExposesRequest(t *testing.T) {
+	handler := RenderHandlerFunc(func(r *http.Request) H {
+		return func(b *Builder) Node {
+			return b.P(b.Request().URL.Path)
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest("GET", "/orders/7", nil))
+
+	if rec.Body.String() != "<p>/orders/7</p>" {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestRenderWithContextStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rendered := 0
+	template := func(b *Builder) Node {
+		var items []Node
+		for i := 0; i < 1000; i++ {
+			items = append(items, &cancelAfter{n: &rendered, at: 100, cancel: cancel}, b.Li("item"))
+		}
+		return b.Ul(NewFragment(items...))
+	}
+
+	var sb strings.Builder
+	err := RenderWithContext(ctx, template, &sb)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if rendered >= 1000 {
+		t.Errorf("rendering continued after cancellation (%d nodes)", rendered)
+	}
+
+	if err := RenderWithContext(ctx, template, &sb); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected already-cancelled context to fail, got %v", err)
+	}
+}
+
+// cancelAfter cancels a context once it has been rendered at times.
+type cancelAfter struct {
+	n      *int
+	at     int
+	cancel context.CancelFunc
+}
+
+func (c *cancelAfter) Render(w io.Writer) error {
+	*c.n++
+	if *c.n == c.at {
+		c.cancel()
+	}
+	return nil
+}
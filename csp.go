@@ -0,0 +1,313 @@
+package minty
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Content Security Policy
+//
+// Two ways of allowing minty's inline <script> and <style> blocks under a
+// strict policy are supported:
+//
+//   - Nonces. CSPMiddleware generates a nonce per request and puts it in the
+//     request context. b.Script and b.Style add it to every element they
+//     create, including those emitted by DarkMode, mintydyn and page assets.
+//
+//   - Hashes. A CSPCollector attached to the render context records the
+//     sha256 hash of every inline block as it is rendered; CSPHashHandler
+//     buffers the page and sends a policy listing exactly those hashes.
+//
+// Inline event handlers (on* and hx-on attributes) cannot be allowed by a
+// nonce. In strict mode they are dropped from the output and reported as
+// violations so they can be moved into scripts.
+
+// CSPNonce holds the request's CSP nonce. Builders bound to a context that
+// carries a nonce add it to the script and style elements they create.
+var CSPNonce = NewContextKey[string]("csp-nonce")
+
+// NewNonce returns a random base64 nonce suitable for a CSP header.
+func NewNonce() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("minty: cannot generate CSP nonce: " + err.Error())
+	}
+	return base64.StdEncoding.EncodeToString(b[:])
+}
+
+// withNonce adds the builder's CSP nonce, if any, to a script or style.
+func (b *Builder) withNonce(node Node) Node {
+	if nonce := CSPNonce.Get(b); nonce != "" {
+		if el, ok := node.(*Element); ok && !el.HasAttribute("nonce") {
+			el.Attributes.setTrusted("nonce", nonce)
+		}
+	}
+	return node
+}
+
+// CSPPolicy is an immutable Content-Security-Policy header value.
+// Methods return modified copies, so a shared base policy can be extended
+// per request.
+type CSPPolicy struct {
+	directives []cspDirective
+}
+
+type cspDirective struct {
+	name    string
+	sources []string
+}
+
+// StrictCSP returns a policy that allows scripts, styles and other content
+// from the same origin only, plus whatever nonce or hashes are added.
+func StrictCSP() CSPPolicy {
+	return CSPPolicy{}.
+		With("default-src", "'self'").
+		With("script-src", "'self'").
+		With("style-src", "'self'").
+		With("object-src", "'none'").
+		With("base-uri", "'self'")
+}
+
+// With returns a copy of the policy with sources added to a directive.
+func (p CSPPolicy) With(directive string, sources ...string) CSPPolicy {
+	out := CSPPolicy{directives: make([]cspDirective, len(p.directives), len(p.directives)+1)}
+	for i, d := range p.directives {
+		out.directives[i] = cspDirective{name: d.name, sources: append([]string(nil), d.sources...)}
+	}
+	for i := range out.directives {
+		if out.directives[i].name == directive {
+			for _, s := range sources {
+				if !containsString(out.directives[i].sources, s) {
+					out.directives[i].sources = append(out.directives[i].sources, s)
+				}
+			}
+			return out
+		}
+	}
+	out.directives = append(out.directives, cspDirective{name: directive, sources: sources})
+	return out
+}
+
+// WithNonce returns a copy of the policy allowing elements with nonce.
+func (p CSPPolicy) WithNonce(nonce string) CSPPolicy {
+	source := "'nonce-" + nonce + "'"
+	return p.With("script-src", source).With("style-src", source)
+}
+
+// WithHashes returns a copy of the policy allowing the inline blocks
+// recorded by c.
+func (p CSPPolicy) WithHashes(c *CSPCollector) CSPPolicy {
+	p = p.With("script-src", c.ScriptHashes()...)
+	return p.With("style-src", c.StyleHashes()...)
+}
+
+// String returns the header value.
+func (p CSPPolicy) String() string {
+	var sb strings.Builder
+	for i, d := range p.directives {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(d.name)
+		for _, s := range d.sources {
+			sb.WriteByte(' ')
+			sb.WriteString(s)
+		}
+	}
+	return sb.String()
+}
+
+// CSPViolation is an inline event handler removed in strict mode.
+type CSPViolation struct {
+	Tag       string
+	Attribute string
+	Value     string
+}
+
+// CSPCollector records what a render needs from the policy: the hashes of
+// inline scripts and styles and, in strict mode, the inline event handlers
+// that were dropped. It is safe for concurrent use.
+type CSPCollector struct {
+	// Strict drops on* and hx-on attributes and records them as violations.
+	Strict bool
+
+	mu         sync.Mutex
+	scripts    []string
+	styles     []string
+	violations []CSPViolation
+}
+
+var cspCollectorKey = NewContextKey[*CSPCollector]("csp-collector")
+
+// WithCSPCollector returns a copy of ctx whose renders report to c.
+func WithCSPCollector(ctx context.Context, c *CSPCollector) context.Context {
+	return cspCollectorKey.With(ctx, c)
+}
+
+// ScriptHashes returns the 'sha256-...' sources of the inline scripts seen.
+func (c *CSPCollector) ScriptHashes() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.scripts...)
+}
+
+// StyleHashes returns the 'sha256-...' sources of the inline styles seen.
+func (c *CSPCollector) StyleHashes() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.styles...)
+}
+
+// Violations returns the inline handlers dropped in strict mode.
+func (c *CSPCollector) Violations() []CSPViolation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]CSPViolation(nil), c.violations...)
+}
+
+func (c *CSPCollector) addHash(tag string, content []byte) {
+	sum := sha256.Sum256(content)
+	c.addSource(tag, "'sha256-"+base64.StdEncoding.EncodeToString(sum[:])+"'")
+}
+
+// addSource records a hash source for a script or style.
+func (c *CSPCollector) addSource(tag, source string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if tag == "style" {
+		if !containsString(c.styles, source) {
+			c.styles = append(c.styles, source)
+		}
+		return
+	}
+	if !containsString(c.scripts, source) {
+		c.scripts = append(c.scripts, source)
+	}
+}
+
+func (c *CSPCollector) addViolation(v CSPViolation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.violations = append(c.violations, v)
+}
+
+// dropsAttribute reports whether strict mode removes the attribute.
+func (c *CSPCollector) dropsAttribute(tag string, attr AttributePair) bool {
+	if !c.Strict || classifyAttribute(tag, attr.Name) != contextJS {
+		return false
+	}
+	c.addViolation(CSPViolation{Tag: tag, Attribute: attr.Name, Value: attr.Value})
+	return true
+}
+
+// hashesContent reports whether the element is an inline block whose
+// content the collector must hash.
+func hashesContent(e *Element) bool {
+	switch e.Tag {
+	case "script":
+		return !e.HasAttribute("src")
+	case "style":
+		return true
+	}
+	return false
+}
+
+// renderHashed renders an inline block's children, recording their hash.
+func (c *CSPCollector) renderHashed(e *Element, w stringWriter) error {
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+	for _, child := range e.Children {
+		if err := child.Render(buf); err != nil {
+			return err
+		}
+	}
+	c.addHash(e.Tag, buf.Bytes())
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// CSPOption configures CSPMiddleware.
+type CSPOption func(*cspConfig)
+
+type cspConfig struct {
+	strict      bool
+	onViolation func(*http.Request, CSPViolation)
+}
+
+// CSPStrictHandlers drops inline event handlers from rendered pages and
+// passes each one to report, which may be nil.
+func CSPStrictHandlers(report func(*http.Request, CSPViolation)) CSPOption {
+	return func(c *cspConfig) {
+		c.strict = true
+		c.onViolation = report
+	}
+}
+
+// CSPMiddleware generates a nonce for every request, makes it available to
+// handlers that render with the request context, and sends policy with the
+// nonce added.
+//
+//	mux.Handle("/", mi.CSPMiddleware(mi.StrictCSP())(handler))
+func CSPMiddleware(policy CSPPolicy, opts ...CSPOption) func(http.Handler) http.Handler {
+	config := &cspConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nonce := NewNonce()
+			w.Header().Set("Content-Security-Policy", policy.WithNonce(nonce).String())
+			ctx := CSPNonce.With(r.Context(), nonce)
+
+			var collector *CSPCollector
+			if config.strict {
+				collector = &CSPCollector{Strict: true}
+				ctx = WithCSPCollector(ctx, collector)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+
+			if collector != nil && config.onViolation != nil {
+				for _, v := range collector.Violations() {
+					config.onViolation(r, v)
+				}
+			}
+		})
+	}
+}
+
+// CSPHashHandler renders the template returned by fn into a buffer,
+// recording the hash of each inline script and style, and sends it with
+// policy extended by those hashes.
+func CSPHashHandler(policy CSPPolicy, fn func(*http.Request) H) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		collector, _ := cspCollectorKey.From(r.Context())
+		if collector == nil {
+			collector = &CSPCollector{}
+		}
+		buf := acquireBuffer()
+		defer releaseBuffer(buf)
+
+		ctx := WithCSPCollector(RequestContext(r), collector)
+		if err := RenderWithContext(ctx, fn(r), buf); err != nil {
+			http.Error(w, "Template render error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Security-Policy", policy.WithHashes(collector).String())
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		buf.WriteTo(w)
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package minty
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBuilderAddsNonceToScriptsAndStyles(t *testing.T) {
+	ctx := CSPNonce.With(context.Background(), "abc123")
+	page := func(b *Builder) Node {
+		return b.Div(
+			b.Script(Raw("init()")),
+			b.Style(Raw("p{color:red}")),
+			b.Require(InlineScriptAsset("boot", "boot()")),
+			NewDarkMode().Script(b),
+		)
+	}
+
+	var sb strings.Builder
+	if err := RenderWithContext(ctx, page, &sb); err != nil {
+		t.Fatal(err)
+	}
+	html := sb.String()
+	if n := strings.Count(html, `nonce="abc123"`); n != 4 {
+		t.Errorf("expected 4 elements with the nonce, got %d:\n%s", n, html)
+	}
+	if strings.Contains(RenderToString(page), "nonce=") {
+		t.Error("no nonce expected without one in the context")
+	}
+}
+
+func TestCSPMiddlewareSendsMatchingNonce(t *testing.T) {
+	handler := CSPMiddleware(StrictCSP())(RenderHandlerFunc(func(r *http.Request) H {
+		return func(b *Builder) Node { return b.Script(Raw("go()")) }
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	nonce := strings.TrimSuffix(strings.TrimPrefix(rec.Body.String(), `<script nonce="`), `">go()</script>`)
+	if nonce == "" || nonce == rec.Body.String() {
+		t.Fatalf("body has no nonce: %s", rec.Body.String())
+	}
+	header := rec.Header().Get("Content-Security-Policy")
+	want := "script-src 'self' 'nonce-" + nonce + "'"
+	if !strings.Contains(header, want) {
+		t.Errorf("header %q does not contain %q", header, want)
+	}
+}
+
+func TestCSPHashHandlerListsInlineHashes(t *testing.T) {
+	handler := CSPHashHandler(StrictCSP(), func(r *http.Request) H {
+		return func(b *Builder) Node {
+			return b.Div(
+				b.Script(Raw("alert(1)")),
+				b.Script(Src("/app.js")),
+				b.Style(Raw("p{margin:0}")),
+			)
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest("GET", "/", nil))
+
+	hash := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+	}
+	header := rec.Header().Get("Content-Security-Policy")
+	if !strings.Contains(header, "script-src 'self' "+hash("alert(1)")+";") {
+		t.Errorf("missing script hash in %q", header)
+	}
+	if !strings.Contains(header, "style-src 'self' "+hash("p{margin:0}")+";") {
+		t.Errorf("missing style hash in %q", header)
+	}
+	if strings.Contains(header, hash("")) {
+		t.Errorf("external script should not be hashed: %q", header)
+	}
+	if !strings.Contains(rec.Body.String(), "<script>alert(1)</script>") {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestCSPStrictHandlersDropsInlineHandlers(t *testing.T) {
+	var reported []CSPViolation
+	report := func(r *http.Request, v CSPViolation) { reported = append(reported, v) }
+
+	handler := CSPMiddleware(StrictCSP(), CSPStrictHandlers(report))(RenderHandler(func(b *Builder) Node {
+		return b.Div(
+			b.Button(On("click", "save()"), Class("btn"), "Save"),
+			b.Div(HxOn("htmx:afterSwap", "done()")),
+			NewDarkMode().Toggle(b),
+		)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	body := rec.Body.String()
+	if strings.Contains(body, "onclick") || strings.Contains(body, "hx-on") {
+		t.Errorf("inline handlers should be dropped: %s", body)
+	}
+	if !strings.Contains(body, `<button class="btn">Save</button>`) {
+		t.Errorf("element should otherwise render: %s", body)
+	}
+	if len(reported) != 2 {
+		t.Fatalf("expected 2 violations, got %+v", reported)
+	}
+	if reported[0] != (CSPViolation{Tag: "button", Attribute: "onclick", Value: "save()"}) {
+		t.Errorf("unexpected violation: %+v", reported[0])
+	}
+	if reported[1].Attribute != "hx-on:htmx:afterSwap" {
+		t.Errorf("unexpected violation: %+v", reported[1])
+	}
+}
+
+func TestCSPPolicyIsImmutable(t *testing.T) {
+	base := StrictCSP()
+	withNonce := base.WithNonce("n1")
+	if strings.Contains(base.String(), "nonce") {
+		t.Errorf("base policy was modified: %s", base)
+	}
+	want := "default-src 'self'; script-src 'self' 'nonce-n1'; style-src 'self' 'nonce-n1'; object-src 'none'; base-uri 'self'"
+	if withNonce.String() != want {
+		t.Errorf("got  %s\nwant %s", withNonce, want)
+	}
+}
@@ -179,6 +179,8 @@ func DarkModeAttr(attrName, lightValue, darkValue string, opts ...DarkModeOption
 
 // Script generates the JavaScript for dark mode initialisation.
 // Place this in the <head> to prevent flash of wrong theme on page load.
+// The script is registered as a head asset, so within a Document it is
+// emitted once in <head> wherever Script is called.
 //
 // Example:
 //
@@ -191,7 +193,9 @@ func (dm *DarkMode) Script(b *Builder) Node {
 	if dm.config.Minify {
 		js = minifyDarkModeJS(js)
 	}
-	return b.Script(Raw(js))
+	return b.Require(HeadAsset("minty-darkmode", func(b *Builder) Node {
+		return b.Script(Raw(js))
+	}))
 }
 
 // ScriptRaw returns the raw JavaScript string without wrapping in a script tag.
@@ -216,7 +220,7 @@ func (dm *DarkMode) ScriptRaw() string {
 func (dm *DarkMode) Toggle(b *Builder, attrs ...interface{}) Node {
 	buttonAttrs := []interface{}{
 		Type("button"),
-		Attr("onclick", dm.toggleFunctionName()+"()"),
+		Data("dark-mode-toggle", ""),
 		Attr("aria-label", "Toggle dark mode"),
 	}
 	buttonAttrs = append(buttonAttrs, attrs...)
@@ -306,6 +310,14 @@ func (dm *DarkMode) generateScript() string {
 	sb.WriteString(fmt.Sprintf("    %s(window.__darkModeInit);\n", updateFn))
 	sb.WriteString("});\n")
 
+	// Toggle buttons are bound here rather than with onclick, so the page
+	// works under a Content-Security-Policy without 'unsafe-inline'
+	sb.WriteString("document.addEventListener('click', function(e) {\n")
+	sb.WriteString("    if (e.target.closest && e.target.closest('[data-dark-mode-toggle]')) {\n")
+	sb.WriteString(fmt.Sprintf("        %s();\n", toggleFn))
+	sb.WriteString("    }\n")
+	sb.WriteString("});\n")
+
 	return sb.String()
 }
 
@@ -0,0 +1,356 @@
+package minty
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Document options
+//
+// Document, the page layouts in layout.go and the theme *Document helpers
+// accept DocumentOption values describing the page as a whole:
+//
+//	mi.Document("Pricing", nil, body,
+//	    mi.DocumentLang("de"),
+//	    mi.DocumentDescription("Plans and prices"),
+//	    mi.DocumentCanonical("https://example.com/pricing"),
+//	    mi.DocumentOpenGraph(mi.OpenGraph{Image: "https://example.com/og.png"}),
+//	    mi.DocumentJSONLD(mi.LDOrganization{Name: "Example", URL: "https://example.com"}),
+//	)
+
+// DocumentOptions describes page-level metadata.
+type DocumentOptions struct {
+	Lang        string   // <html lang>
+	Dir         string   // <html dir>: "ltr", "rtl" or "auto"
+	Description string   // <meta name="description">
+	Keywords    []string // <meta name="keywords">
+	Canonical   string   // <link rel="canonical">
+	Robots      []string // <meta name="robots">, e.g. "noindex", "nofollow"
+	ThemeColor  string   // <meta name="theme-color">
+	Favicons    []Favicon
+	OpenGraph   *OpenGraph
+	Twitter     *TwitterCard
+	JSONLD      []interface{} // structured data, one <script> each
+}
+
+// DocumentOption configures DocumentOptions.
+type DocumentOption func(*DocumentOptions)
+
+// Favicon is one entry of a favicon set.
+type Favicon struct {
+	Href  string
+	Rel   string // defaults to "icon"
+	Type  string // e.g. "image/png", "image/svg+xml"
+	Sizes string // e.g. "32x32"
+}
+
+// OpenGraph holds og:* properties. Empty fields are omitted; Title and
+// Description default to the document's title and description.
+type OpenGraph struct {
+	Type        string // defaults to "website"
+	Title       string
+	Description string
+	URL         string // defaults to the canonical URL
+	Image       string
+	ImageAlt    string
+	SiteName    string
+	Locale      string
+}
+
+// TwitterCard holds twitter:* properties. Empty fields are omitted.
+type TwitterCard struct {
+	Card        string // "summary", "summary_large_image"...
+	Site        string
+	Creator     string
+	Title       string
+	Description string
+	Image       string
+	ImageAlt    string
+}
+
+// DocumentLang sets the document language, which defaults to "en" for
+// Document and every layout.
+func DocumentLang(lang string) DocumentOption {
+	return func(o *DocumentOptions) {
+		o.Lang = lang
+	}
+}
+
+// DocumentDir sets the document text direction.
+func DocumentDir(dir string) DocumentOption {
+	return func(o *DocumentOptions) {
+		o.Dir = dir
+	}
+}
+
+// DocumentDescription sets the meta description.
+func DocumentDescription(description string) DocumentOption {
+	return func(o *DocumentOptions) {
+		o.Description = description
+	}
+}
+
+// DocumentKeywords sets the meta keywords.
+func DocumentKeywords(keywords ...string) DocumentOption {
+	return func(o *DocumentOptions) {
+		o.Keywords = keywords
+	}
+}
+
+// DocumentCanonical sets the canonical URL of the page.
+func DocumentCanonical(url string) DocumentOption {
+	return func(o *DocumentOptions) {
+		o.Canonical = url
+	}
+}
+
+// DocumentRobots sets robots directives such as "noindex" or "nofollow".
+func DocumentRobots(directives ...string) DocumentOption {
+	return func(o *DocumentOptions) {
+		o.Robots = directives
+	}
+}
+
+// DocumentThemeColor sets the browser UI theme colour.
+func DocumentThemeColor(color string) DocumentOption {
+	return func(o *DocumentOptions) {
+		o.ThemeColor = color
+	}
+}
+
+// DocumentFavicons adds favicon links.
+func DocumentFavicons(icons ...Favicon) DocumentOption {
+	return func(o *DocumentOptions) {
+		o.Favicons = append(o.Favicons, icons...)
+	}
+}
+
+// DocumentOpenGraph sets Open Graph properties.
+func DocumentOpenGraph(og OpenGraph) DocumentOption {
+	return func(o *DocumentOptions) {
+		o.OpenGraph = &og
+	}
+}
+
+// DocumentTwitterCard sets Twitter card properties.
+func DocumentTwitterCard(card TwitterCard) DocumentOption {
+	return func(o *DocumentOptions) {
+		o.Twitter = &card
+	}
+}
+
+// DocumentJSONLD adds structured data, marshalled with encoding/json into
+// an application/ld+json script. Use the LD* types or any value that
+// marshals to a JSON-LD object.
+func DocumentJSONLD(data ...interface{}) DocumentOption {
+	return func(o *DocumentOptions) {
+		o.JSONLD = append(o.JSONLD, data...)
+	}
+}
+
+// newDocumentOptions applies opts over the defaults shared by Document and
+// the layouts: the language is "en" unless set.
+func newDocumentOptions(opts []DocumentOption) *DocumentOptions {
+	o := DocumentOptions{Lang: "en"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &o
+}
+
+// htmlAttributes returns the attributes for the <html> element.
+func (o *DocumentOptions) htmlAttributes() []interface{} {
+	var attrs []interface{}
+	if o.Lang != "" {
+		attrs = append(attrs, Lang(o.Lang))
+	}
+	if o.Dir != "" {
+		attrs = append(attrs, Dir(o.Dir))
+	}
+	return attrs
+}
+
+// headNodes returns the <head> elements described by the options.
+func (o *DocumentOptions) headNodes(b *Builder, title string) []interface{} {
+	var nodes []interface{}
+	meta := func(name, content string) {
+		if content != "" {
+			nodes = append(nodes, b.Meta(Name(name), Content(content)))
+		}
+	}
+	property := func(name, content string) {
+		if content != "" {
+			nodes = append(nodes, b.Meta(Attr("property", name), Content(content)))
+		}
+	}
+
+	meta("description", o.Description)
+	meta("keywords", strings.Join(o.Keywords, ", "))
+	meta("robots", strings.Join(o.Robots, ", "))
+	meta("theme-color", o.ThemeColor)
+	if o.Canonical != "" {
+		nodes = append(nodes, b.Link(Rel("canonical"), Href(o.Canonical)))
+	}
+
+	for _, icon := range o.Favicons {
+		rel := icon.Rel
+		if rel == "" {
+			rel = "icon"
+		}
+		attrs := []Attribute{Rel(rel), Href(icon.Href)}
+		if icon.Type != "" {
+			attrs = append(attrs, Type(icon.Type))
+		}
+		if icon.Sizes != "" {
+			attrs = append(attrs, Sizes(icon.Sizes))
+		}
+		nodes = append(nodes, b.Link(attrs...))
+	}
+
+	if og := o.OpenGraph; og != nil {
+		property("og:type", firstNonEmpty(og.Type, "website"))
+		property("og:title", firstNonEmpty(og.Title, title))
+		property("og:description", firstNonEmpty(og.Description, o.Description))
+		property("og:url", firstNonEmpty(og.URL, o.Canonical))
+		property("og:image", og.Image)
+		property("og:image:alt", og.ImageAlt)
+		property("og:site_name", og.SiteName)
+		property("og:locale", og.Locale)
+	}
+
+	if tc := o.Twitter; tc != nil {
+		meta("twitter:card", tc.Card)
+		meta("twitter:site", tc.Site)
+		meta("twitter:creator", tc.Creator)
+		meta("twitter:title", tc.Title)
+		meta("twitter:description", tc.Description)
+		meta("twitter:image", tc.Image)
+		meta("twitter:image:alt", tc.ImageAlt)
+	}
+
+	for _, data := range o.JSONLD {
+		if node := jsonLDScript(b, data); node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// jsonLDScript renders structured data as an application/ld+json script.
+// encoding/json escapes <, > and & (and U+2028/U+2029), so the payload
+// cannot close the script element. Values that fail to marshal are skipped.
+func jsonLDScript(b *Builder, data interface{}) Node {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	return b.Script(Type("application/ld+json"), Raw(string(payload)))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Structured data types for common schema.org entities. They marshal with
+// the @context and @type keys set.
+
+const schemaOrg = "https://schema.org"
+
+// LDOrganization is a schema.org Organization.
+type LDOrganization struct {
+	Name   string   `json:"name"`
+	URL    string   `json:"url,omitempty"`
+	Logo   string   `json:"logo,omitempty"`
+	SameAs []string `json:"sameAs,omitempty"`
+}
+
+// MarshalJSON adds the JSON-LD @context and @type.
+func (v LDOrganization) MarshalJSON() ([]byte, error) {
+	type plain LDOrganization
+	return marshalLD("Organization", plain(v))
+}
+
+// LDWebSite is a schema.org WebSite.
+type LDWebSite struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// MarshalJSON adds the JSON-LD @context and @type.
+func (v LDWebSite) MarshalJSON() ([]byte, error) {
+	type plain LDWebSite
+	return marshalLD("WebSite", plain(v))
+}
+
+// LDPerson is a schema.org Person.
+type LDPerson struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// MarshalJSON adds the JSON-LD @context and @type.
+func (v LDPerson) MarshalJSON() ([]byte, error) {
+	type plain LDPerson
+	return marshalLD("Person", plain(v))
+}
+
+// LDArticle is a schema.org Article.
+type LDArticle struct {
+	Headline      string          `json:"headline"`
+	Description   string          `json:"description,omitempty"`
+	Image         []string        `json:"image,omitempty"`
+	DatePublished string          `json:"datePublished,omitempty"`
+	DateModified  string          `json:"dateModified,omitempty"`
+	Author        []LDPerson      `json:"author,omitempty"`
+	Publisher     *LDOrganization `json:"publisher,omitempty"`
+}
+
+// MarshalJSON adds the JSON-LD @context and @type.
+func (v LDArticle) MarshalJSON() ([]byte, error) {
+	type plain LDArticle
+	return marshalLD("Article", plain(v))
+}
+
+// LDBreadcrumb is one entry of an LDBreadcrumbList.
+type LDBreadcrumb struct {
+	Name string
+	URL  string
+}
+
+// LDBreadcrumbList is a schema.org BreadcrumbList.
+type LDBreadcrumbList []LDBreadcrumb
+
+// MarshalJSON adds the JSON-LD @context and @type.
+func (v LDBreadcrumbList) MarshalJSON() ([]byte, error) {
+	type item struct {
+		Type     string `json:"@type"`
+		Position int    `json:"position"`
+		Name     string `json:"name"`
+		Item     string `json:"item,omitempty"`
+	}
+	items := make([]item, len(v))
+	for i, crumb := range v {
+		items[i] = item{Type: "ListItem", Position: i + 1, Name: crumb.Name, Item: crumb.URL}
+	}
+	return marshalLD("BreadcrumbList", struct {
+		Items []item `json:"itemListElement"`
+	}{items})
+}
+
+// marshalLD marshals v, a struct, with @context and @type keys first.
+func marshalLD(typ string, v interface{}) ([]byte, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	head := `{"@context":"` + schemaOrg + `","@type":"` + typ + `"`
+	if len(body) > 2 {
+		head += ","
+	}
+	return append([]byte(head), body[1:]...), nil
+}
@@ -0,0 +1,104 @@
+package minty
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocumentDefaultsToEnglish(t *testing.T) {
+	html := RenderToString(Document("Home", nil, B.Body("hi")))
+	if !strings.Contains(html, `<html lang="en">`) {
+		t.Errorf("expected lang=en by default: %s", html)
+	}
+}
+
+func TestDocumentOptions(t *testing.T) {
+	page := Document("Pricing", nil, B.Body("plans"),
+		DocumentLang("ar"),
+		DocumentDir("rtl"),
+		DocumentDescription("Plans & prices"),
+		DocumentCanonical("https://example.com/pricing"),
+		DocumentRobots("noindex", "nofollow"),
+		DocumentThemeColor("#0f172a"),
+		DocumentFavicons(
+			Favicon{Href: "/favicon.svg", Type: "image/svg+xml"},
+			Favicon{Href: "/apple-touch-icon.png", Rel: "apple-touch-icon", Sizes: "180x180"},
+		),
+		DocumentOpenGraph(OpenGraph{Image: "https://example.com/og.png"}),
+		DocumentTwitterCard(TwitterCard{Card: "summary_large_image", Site: "@example"}),
+	)
+	html := RenderToString(page)
+
+	for _, want := range []string{
+		`<html lang="ar" dir="rtl">`,
+		`<meta name="description" content="Plans &amp; prices" />`,
+		`<meta name="robots" content="noindex, nofollow" />`,
+		`<meta name="theme-color" content="#0f172a" />`,
+		`<link rel="canonical" href="https://example.com/pricing" />`,
+		`<link rel="icon" href="/favicon.svg" type="image/svg+xml" />`,
+		`<link rel="apple-touch-icon" href="/apple-touch-icon.png" sizes="180x180" />`,
+		`<meta property="og:type" content="website" />`,
+		`<meta property="og:title" content="Pricing" />`,
+		`<meta property="og:description" content="Plans &amp; prices" />`,
+		`<meta property="og:url" content="https://example.com/pricing" />`,
+		`<meta property="og:image" content="https://example.com/og.png" />`,
+		`<meta name="twitter:card" content="summary_large_image" />`,
+		`<meta name="twitter:site" content="@example" />`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %s in:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "og:site_name") {
+		t.Error("empty Open Graph fields should be omitted")
+	}
+}
+
+func TestDocumentJSONLDIsEscaped(t *testing.T) {
+	page := Document("Post", nil, B.Body(),
+		DocumentJSONLD(
+			LDArticle{
+				Headline: "</script><script>alert(1)</script>",
+				Author:   []LDPerson{{Name: "Ada"}},
+			},
+			LDBreadcrumbList{{Name: "Home", URL: "/"}, {Name: "Blog"}},
+		),
+	)
+	html := RenderToString(page)
+
+	if strings.Count(html, "<script") != 2 || strings.Contains(html, "alert(1)</script>") {
+		t.Fatalf("JSON-LD payload escaped its script element:\n%s", html)
+	}
+	want := `<script type="application/ld+json">{"@context":"https://schema.org","@type":"Article",` +
+		`"headline":"\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e",` +
+		`"author":[{"@context":"https://schema.org","@type":"Person","name":"Ada"}]}</script>`
+	if !strings.Contains(html, want) {
+		t.Errorf("unexpected article JSON-LD:\n%s", html)
+	}
+	crumbs := `{"@context":"https://schema.org","@type":"BreadcrumbList","itemListElement":[` +
+		`{"@type":"ListItem","position":1,"name":"Home","item":"/"},` +
+		`{"@type":"ListItem","position":2,"name":"Blog"}]}`
+	if !strings.Contains(html, crumbs) {
+		t.Errorf("unexpected breadcrumb JSON-LD:\n%s", html)
+	}
+}
+
+func TestLayoutsAcceptDocumentOptions(t *testing.T) {
+	content := func(b *Builder) Node { return b.P("x") }
+
+	html := RenderToString(LayoutWithMeta("T", "About us", []string{"a", "b"}, content, DocumentLang("fr")))
+	for _, want := range []string{
+		`<html lang="fr">`,
+		`<meta name="description" content="About us" />`,
+		`<meta name="keywords" content="a, b" />`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %s in:\n%s", want, html)
+		}
+	}
+
+	html = RenderToString(FullLayout("T", nil, content, nil, nil, DocumentDir("rtl")))
+	if !strings.Contains(html, `<html lang="en" dir="rtl">`) {
+		t.Errorf("FullLayout should keep lang=en and add dir: %s", html)
+	}
+}
@@ -617,8 +617,16 @@ type EcommerceService struct {
 	carts      []Cart
 	orders     []Order
 	customers  []Customer
+	onChange   []func(kind string)
 }
 
+// Kinds of data reported to OnChange listeners.
+const (
+	ChangeProducts  = "products"
+	ChangeOrders    = "orders"
+	ChangeCustomers = "customers"
+)
+
 // NewEcommerceService creates a new e-commerce service
 func NewEcommerceService() *EcommerceService {
 	return &EcommerceService{
@@ -630,6 +638,19 @@ func NewEcommerceService() *EcommerceService {
 	}
 }
 
+// OnChange registers fn to be called after the service changes data of the
+// given kind (ChangeProducts, ChangeOrders or ChangeCustomers), for example
+// to invalidate cached views of it.
+func (es *EcommerceService) OnChange(fn func(kind string)) {
+	es.onChange = append(es.onChange, fn)
+}
+
+func (es *EcommerceService) changed(kind string) {
+	for _, fn := range es.onChange {
+		fn(kind)
+	}
+}
+
 // Product Operations
 
 func (es *EcommerceService) CreateProduct(name, description, sku, category string, 
@@ -655,6 +676,7 @@ func (es *EcommerceService) CreateProduct(name, description, sku, category strin
 	}
 	
 	es.products = append(es.products, product)
+	es.changed(ChangeProducts)
 	return &product, nil
 }
 
@@ -706,7 +728,11 @@ func (es *EcommerceService) UpdateProductInventory(productID string, quantityCha
 		return err
 	}
 	
-	return UpdateInventory(product, quantityChange)
+	if err := UpdateInventory(product, quantityChange); err != nil {
+		return err
+	}
+	es.changed(ChangeProducts)
+	return nil
 }
 
 // Cart Operations
@@ -815,6 +841,7 @@ func (es *EcommerceService) CreateOrder(cartID string, customer Customer,
 	cart.UpdatedAt = time.Now()
 	
 	es.orders = append(es.orders, order)
+	es.changed(ChangeOrders)
 	return &order, nil
 }
 
@@ -869,7 +896,11 @@ func (es *EcommerceService) ShipOrder(orderID, trackingNumber string) error {
 		return err
 	}
 	
-	return ShipOrder(order, trackingNumber)
+	if err := ShipOrder(order, trackingNumber); err != nil {
+		return err
+	}
+	es.changed(ChangeOrders)
+	return nil
 }
 
 // Customer Operations
@@ -893,6 +924,7 @@ func (es *EcommerceService) CreateCustomer(name, email string) (*Customer, error
 	}
 	
 	es.customers = append(es.customers, customer)
+	es.changed(ChangeCustomers)
 	return &customer, nil
 }
 
@@ -420,8 +420,16 @@ type FinanceService struct {
 	transactions []Transaction
 	invoices     []Invoice
 	customers    []Customer
+	onChange     []func(kind string)
 }
 
+// Kinds of data reported to OnChange listeners.
+const (
+	ChangeAccounts     = "accounts"
+	ChangeTransactions = "transactions"
+	ChangeInvoices     = "invoices"
+)
+
 // NewFinanceService creates a new finance service
 func NewFinanceService() *FinanceService {
 	return &FinanceService{
@@ -432,6 +440,21 @@ func NewFinanceService() *FinanceService {
 	}
 }
 
+// OnChange registers fn to be called after the service changes data of the
+// given kind (ChangeAccounts, ChangeTransactions or ChangeInvoices), for
+// example to invalidate cached views of it.
+func (fs *FinanceService) OnChange(fn func(kind string)) {
+	fs.onChange = append(fs.onChange, fn)
+}
+
+func (fs *FinanceService) changed(kinds ...string) {
+	for _, kind := range kinds {
+		for _, fn := range fs.onChange {
+			fn(kind)
+		}
+	}
+}
+
 // Account Operations
 
 func (fs *FinanceService) CreateAccount(name, accountType string, initialBalance mt.Money, customerID string) (*Account, error) {
@@ -451,6 +474,7 @@ func (fs *FinanceService) CreateAccount(name, accountType string, initialBalance
 	}
 	
 	fs.accounts = append(fs.accounts, account)
+	fs.changed(ChangeAccounts)
 	return &account, nil
 }
 
@@ -481,6 +505,7 @@ func (fs *FinanceService) UpdateAccountBalance(accountID string, transactions []
 	
 	account.Balance = CalculateAccountBalance(transactions)
 	account.UpdatedAt = time.Now()
+	fs.changed(ChangeAccounts)
 	return nil
 }
 
@@ -533,6 +558,7 @@ func (fs *FinanceService) CreateTransaction(accountID string, amount mt.Money,
 	}
 	
 	fs.transactions = append(fs.transactions, transaction)
+	fs.changed(ChangeTransactions, ChangeAccounts)
 	return &transaction, nil
 }
 
@@ -594,6 +620,7 @@ func (fs *FinanceService) CreateInvoice(number string, customer Customer,
 	}
 	
 	fs.invoices = append(fs.invoices, invoice)
+	fs.changed(ChangeInvoices)
 	return &invoice, nil
 }
 
@@ -617,6 +644,7 @@ func (fs *FinanceService) PayInvoice(invoiceID string, paymentAmount mt.Money) e
 			if err := ProcessPayment(&fs.invoices[i], paymentAmount); err != nil {
 				return err
 			}
+			fs.changed(ChangeInvoices)
 			return nil
 		}
 	}
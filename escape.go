@@ -0,0 +1,404 @@
+package minty
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Contextual attribute escaping.
+//
+// Every attribute value is HTML-escaped when rendered, but that alone does
+// not make it safe: href="javascript:..." or onclick="..." built from user
+// input is still executable. Like html/template, Element.Render therefore
+// classifies each attribute by the context its value is interpreted in and
+// sanitises untrusted values for that context:
+//
+//   - URL attributes (href, src, action, hx-get, <object data>, ...) must
+//     use a scheme from the allowlist (http, https, mailto, tel by default)
+//     or be relative. Anything else is replaced with "about:invalid#ZmintyZ".
+//     The to, from, by and values of an SVG <animate> or <set> that targets
+//     a URL attribute are checked the same way, and ws-connect also accepts
+//     ws and wss.
+//   - Event handlers (on*) and htmx handlers (hx-on*) are JavaScript. Untrusted
+//     values are rendered as an inert JS string literal.
+//   - style values that contain expressions, script URLs, imports or escapes
+//     are replaced with "ZmintyZ".
+//   - srcdoc is a whole HTML document, so untrusted values are replaced with
+//     "ZmintyZ".
+//
+// htmx also reads its attributes with a data- prefix, so data-hx-get and
+// data-hx-on:click are classified like hx-get and hx-on:click.
+//
+// Attribute names are always validated; an invalid name makes Render fail
+// with an *AttributeError.
+//
+// Values the developer knows to be safe can opt out with TrustedURL, TrustedJS
+// and TrustedCSS via TrustedHref, TrustedSrc, On, HtmxOn and TrustedStyle, or
+// with TrustedAttr for any other attribute.
+
+// unsafeValue replaces attribute values that fail contextual checks.
+const unsafeValue = "ZmintyZ"
+
+// unsafeURL replaces URLs whose scheme is not allowed.
+const unsafeURL = "about:invalid#" + unsafeValue
+
+// TrustedURL is a URL the caller vouches for. It bypasses the scheme allowlist.
+type TrustedURL string
+
+// TrustedJS is JavaScript source the caller vouches for, such as an event
+// handler body written by the developer.
+type TrustedJS string
+
+// TrustedCSS is a CSS declaration list the caller vouches for.
+type TrustedCSS string
+
+// TrustedAttribute is an attribute whose value skips contextual sanitisation.
+// The value is still HTML-escaped and the name is still validated.
+type TrustedAttribute struct {
+	Name  string
+	Value string
+}
+
+// Apply adds the trusted attribute to an element.
+func (ta TrustedAttribute) Apply(element *Element) {
+	element.Attributes.setTrusted(ta.Name, ta.Value)
+}
+
+// TrustedAttr creates an attribute that bypasses contextual sanitisation.
+// Only use it for values that never contain user input.
+func TrustedAttr(name, value string) Attribute {
+	return TrustedAttribute{Name: name, Value: value}
+}
+
+// TrustedHref creates an href attribute that bypasses the scheme allowlist.
+func TrustedHref(url TrustedURL) Attribute {
+	return TrustedAttribute{Name: "href", Value: string(url)}
+}
+
+// TrustedSrc creates a src attribute that bypasses the scheme allowlist,
+// for example for data: image URLs generated on the server.
+func TrustedSrc(url TrustedURL) Attribute {
+	return TrustedAttribute{Name: "src", Value: string(url)}
+}
+
+// TrustedStyle creates a style attribute that bypasses CSS sanitisation.
+func TrustedStyle(css TrustedCSS) Attribute {
+	return TrustedAttribute{Name: "style", Value: string(css)}
+}
+
+// On creates an on* event handler attribute, e.g. On("click", "save()").
+func On(event string, script TrustedJS) Attribute {
+	return TrustedAttribute{Name: "on" + event, Value: string(script)}
+}
+
+// AttributeError reports an attribute that cannot be rendered safely.
+type AttributeError struct {
+	Tag  string
+	Name string
+}
+
+// Error implements the error interface.
+func (e *AttributeError) Error() string {
+	return "minty: invalid attribute name " + strconv.Quote(e.Name) + " on <" + e.Tag + ">"
+}
+
+// URL scheme allowlist
+
+var (
+	urlSchemesMu sync.RWMutex
+	urlSchemes   = map[string]bool{
+		"http":   true,
+		"https":  true,
+		"mailto": true,
+		"tel":    true,
+	}
+)
+
+// AllowURLScheme adds schemes to the allowlist used for URL attributes.
+// Call it during program initialisation, e.g. AllowURLScheme("sms", "ftp").
+func AllowURLScheme(schemes ...string) {
+	urlSchemesMu.Lock()
+	defer urlSchemesMu.Unlock()
+	for _, scheme := range schemes {
+		urlSchemes[strings.ToLower(scheme)] = true
+	}
+}
+
+func urlSchemeAllowed(scheme string) bool {
+	urlSchemesMu.RLock()
+	defer urlSchemesMu.RUnlock()
+	return urlSchemes[scheme]
+}
+
+// Attribute contexts
+
+type attrContext int
+
+const (
+	contextPlain attrContext = iota
+	contextURL
+	contextURLList
+	contextJS
+	contextCSS
+	contextHTMXValues
+	contextWebSocketURL
+	contextHTML
+)
+
+// urlAttributes lists attributes whose value is interpreted as a URL.
+var urlAttributes = map[string]bool{
+	"href":           true,
+	"src":            true,
+	"action":         true,
+	"formaction":     true,
+	"poster":         true,
+	"cite":           true,
+	"background":     true,
+	"longdesc":       true,
+	"usemap":         true,
+	"manifest":       true,
+	"icon":           true,
+	"codebase":       true,
+	"xlink:href":     true,
+	"ping":           true,
+	"hx-get":         true,
+	"hx-post":        true,
+	"hx-put":         true,
+	"hx-delete":      true,
+	"hx-patch":       true,
+	"hx-push-url":    true,
+	"hx-replace-url": true,
+	"sse-connect":    true,
+}
+
+// classifyAttribute returns the context the value of the named attribute
+// is interpreted in on an element with the given tag.
+func classifyAttribute(tag, name string) attrContext {
+	name = strings.ToLower(name)
+	// htmx reads every attribute with a data- prefix as well
+	if rest, ok := strings.CutPrefix(name, "data-"); ok && isHTMXAttribute(rest) {
+		name = rest
+	}
+	switch {
+	case urlAttributes[name], name == "data" && strings.EqualFold(tag, "object"):
+		return contextURL
+	case name == "ws-connect":
+		return contextWebSocketURL
+	case name == "srcdoc":
+		return contextHTML
+	case name == "srcset" || name == "imagesrcset":
+		return contextURLList
+	case name == "style":
+		return contextCSS
+	case name == "hx-vals" || name == "hx-headers":
+		return contextHTMXValues
+	case strings.HasPrefix(name, "on"), strings.HasPrefix(name, "hx-on"):
+		return contextJS
+	}
+	return contextPlain
+}
+
+// isHTMXAttribute reports whether name is an htmx or htmx extension
+// attribute.
+func isHTMXAttribute(name string) bool {
+	return strings.HasPrefix(name, "hx-") || name == "sse-connect" || name == "ws-connect"
+}
+
+// sanitizeAttributeValue makes an untrusted value safe for its context.
+func sanitizeAttributeValue(tag, name, value string) string {
+	switch classifyAttribute(tag, name) {
+	case contextURL:
+		return sanitizeURL(value)
+	case contextWebSocketURL:
+		if scheme, _ := urlScheme(value); scheme == "ws" || scheme == "wss" {
+			return value
+		}
+		return sanitizeURL(value)
+	case contextHTML:
+		return unsafeValue
+	case contextURLList:
+		return sanitizeSrcset(value)
+	case contextJS:
+		return jsStringLiteral(value)
+	case contextCSS:
+		return sanitizeCSS(value)
+	case contextHTMXValues:
+		// hx-vals and hx-headers evaluate their value when prefixed with js:
+		trimmed := strings.ToLower(strings.TrimSpace(value))
+		if strings.HasPrefix(trimmed, "js:") || strings.HasPrefix(trimmed, "javascript:") {
+			return unsafeValue
+		}
+	}
+	return value
+}
+
+// animatesURLAttribute reports whether e is an SVG <animate> or <set>
+// whose target is a URL attribute, so that its values become URLs.
+func animatesURLAttribute(e *Element) bool {
+	if e.Tag != "animate" && e.Tag != "set" {
+		return false
+	}
+	target, ok := e.Attributes.Get("attributeName")
+	return ok && classifyAttribute("", strings.TrimSpace(target)) == contextURL
+}
+
+// sanitizeAnimationValue checks the values an animation assigns to a URL
+// attribute, which would otherwise route around the scheme allowlist.
+func sanitizeAnimationValue(name, value string) string {
+	switch name {
+	case "to", "from", "by":
+		return sanitizeURL(value)
+	case "values":
+		values := strings.Split(value, ";")
+		for i, v := range values {
+			values[i] = sanitizeURL(strings.TrimSpace(v))
+		}
+		return strings.Join(values, ";")
+	}
+	return value
+}
+
+// sanitizeURL returns the URL unchanged if it is relative or uses an allowed
+// scheme, and a harmless placeholder otherwise.
+func sanitizeURL(value string) string {
+	if urlIsSafe(value) {
+		return value
+	}
+	return unsafeURL
+}
+
+func urlIsSafe(value string) bool {
+	scheme, ok := urlScheme(value)
+	return !ok || urlSchemeAllowed(scheme)
+}
+
+// urlScheme returns the lower-case scheme of a URL, or false if the URL is
+// relative.
+func urlScheme(value string) (string, bool) {
+	// Browsers ignore leading whitespace/control characters and strip tabs
+	// and newlines anywhere in the URL, so "java\tscript:" is a script URL.
+	cleaned := strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, strings.TrimLeft(value, "\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e\x0f\x10\x11\x12\x13\x14\x15\x16\x17\x18\x19\x1a\x1b\x1c\x1d\x1e\x1f "))
+
+	colon := strings.IndexByte(cleaned, ':')
+	if colon < 0 {
+		return "", false
+	}
+	// A colon after a path, query or fragment delimiter is not a scheme.
+	if i := strings.IndexAny(cleaned, "/?#"); i >= 0 && i < colon {
+		return "", false
+	}
+	return strings.ToLower(cleaned[:colon]), true
+}
+
+// sanitizeSrcset checks every candidate URL of a srcset list.
+func sanitizeSrcset(value string) string {
+	for _, candidate := range strings.Split(value, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) > 0 && !urlIsSafe(fields[0]) {
+			return unsafeURL
+		}
+	}
+	return value
+}
+
+// cssUnsafeTokens are substrings that let a style attribute run script or
+// hide such payloads behind comments and escapes.
+var cssUnsafeTokens = []string{
+	"expression(", "javascript:", "vbscript:", "-moz-binding",
+	"@import", "</", "<!--", "/*", "\\",
+}
+
+// sanitizeCSS returns the declarations unchanged if they are free of
+// script-capable constructs and every url() uses an allowed scheme.
+func sanitizeCSS(value string) string {
+	lower := strings.ToLower(value)
+	for _, token := range cssUnsafeTokens {
+		if strings.Contains(lower, token) {
+			return unsafeValue
+		}
+	}
+	for rest := lower; ; {
+		i := strings.Index(rest, "url(")
+		if i < 0 {
+			break
+		}
+		rest = rest[i+4:]
+		end := strings.IndexByte(rest, ')')
+		if end < 0 {
+			return unsafeValue
+		}
+		if !urlIsSafe(strings.Trim(strings.TrimSpace(rest[:end]), `'"`)) {
+			return unsafeValue
+		}
+		rest = rest[end:]
+	}
+	return value
+}
+
+// jsStringLiteral quotes an untrusted value as a JavaScript string so that,
+// used as a handler body, it evaluates to a harmless expression.
+func jsStringLiteral(value string) string {
+	var sb strings.Builder
+	sb.Grow(len(value) + 2)
+	sb.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '"':
+			sb.WriteString(`\"`)
+		case '\'':
+			sb.WriteString(`\'`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '<':
+			sb.WriteString(`\u003c`)
+		case '>':
+			sb.WriteString(`\u003e`)
+		case '&':
+			sb.WriteString(`\u0026`)
+		case '\u2028':
+			sb.WriteString(`\u2028`)
+		case '\u2029':
+			sb.WriteString(`\u2029`)
+		default:
+			if r < 0x20 {
+				sb.WriteString(`\u00`)
+				sb.WriteByte("0123456789abcdef"[r>>4])
+				sb.WriteByte("0123456789abcdef"[r&0xF])
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// validAttributeName reports whether name is a legal HTML attribute name:
+// non-empty and free of whitespace, quotes, '<', '>', '/', '=' and control
+// characters.
+func validAttributeName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r <= 0x20, r >= 0x7F && r <= 0x9F:
+			return false
+		case r == '"', r == '\'', r == '<', r == '>', r == '/', r == '=', r == '`':
+			return false
+		case r >= 0xFDD0 && r <= 0xFDEF, r&0xFFFE == 0xFFFE:
+			return false
+		}
+	}
+	return true
+}
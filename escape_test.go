@@ -0,0 +1,208 @@
+package minty
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestURLSchemeAllowlist(t *testing.T) {
+	tests := []struct {
+		url  string
+		safe bool
+	}{
+		{"/about", true},
+		{"about.html", true},
+		{"#section", true},
+		{"?q=a:b", true},
+		{"https://example.com", true},
+		{"HTTP://example.com", true},
+		{"mailto:a@example.com", true},
+		{"tel:+123", true},
+		{"javascript:alert(1)", false},
+		{"  JavaScript:alert(1)", false},
+		{"java\tscript:alert(1)", false},
+		{"\x01javascript:alert(1)", false},
+		{"data:text/html;base64,xxx", false},
+		{"vbscript:msgbox", false},
+	}
+
+	for _, tt := range tests {
+		html := RenderToString(func(b *Builder) Node {
+			return b.A(Href(tt.url), "x")
+		})
+		blocked := strings.Contains(html, unsafeURL)
+		if blocked == tt.safe {
+			t.Errorf("Href(%q): safe=%v, got %s", tt.url, tt.safe, html)
+		}
+	}
+}
+
+func TestURLContextCoversSrcAndHTMX(t *testing.T) {
+	html := RenderToString(func(b *Builder) Node {
+		return b.Div(
+			b.Img(Src("data:image/png;base64,AAAA"), Srcset("/a.png 1x, javascript:x 2x")),
+			b.Button(HxGet("javascript:alert(1)"), "Go"),
+			b.Div(Attr("sse-connect", "javascript:alert(1)")),
+		)
+	})
+	if strings.Count(html, unsafeURL) != 4 {
+		t.Errorf("Expected src, srcset, hx-get and sse-connect to be neutralised, got: %s", html)
+	}
+}
+
+func TestTrustedURLBypassesAllowlist(t *testing.T) {
+	html := RenderToString(func(b *Builder) Node {
+		return b.Img(TrustedSrc("data:image/png;base64,AAAA"), Alt("dot"))
+	})
+	if !strings.Contains(html, `src="data:image/png;base64,AAAA"`) {
+		t.Errorf("TrustedSrc should render unchanged, got: %s", html)
+	}
+}
+
+func TestEventHandlerContext(t *testing.T) {
+	html := RenderToString(func(b *Builder) Node {
+		return b.Div(
+			b.Button(Attr("onclick", "alert(document.cookie)"), "untrusted"),
+			b.Button(On("click", "save()"), "trusted"),
+			b.Button(HxOn("click", "this.remove()"), "htmx"),
+			b.Button(Attr("hx-on:click", "steal()"), "untrusted htmx"),
+		)
+	})
+
+	if !strings.Contains(html, `onclick="&#34;alert(document.cookie)&#34;"`) {
+		t.Errorf("Untrusted handler should be an inert string literal, got: %s", html)
+	}
+	if !strings.Contains(html, `onclick="save()"`) {
+		t.Errorf("On should render trusted handler, got: %s", html)
+	}
+	if !strings.Contains(html, `hx-on:click="this.remove()"`) {
+		t.Errorf("HxOn should render trusted handler, got: %s", html)
+	}
+	if !strings.Contains(html, `hx-on:click="&#34;steal()&#34;"`) {
+		t.Errorf("Untrusted hx-on should be an inert string literal, got: %s", html)
+	}
+}
+
+func TestStyleContext(t *testing.T) {
+	tests := []struct {
+		style string
+		safe  bool
+	}{
+		{"color: red; width: 50%", true},
+		{"background: url(/img/bg.png)", true},
+		{"background: url('https://cdn.example.com/a.png')", true},
+		{"width: expression(alert(1))", false},
+		{"background: url(javascript:alert(1))", false},
+		{"background: url( 'data:text/html,x')", false},
+		{"color: \\72 ed", false},
+		{"x: 1</style><script>", false},
+	}
+
+	for _, tt := range tests {
+		html := RenderToString(func(b *Builder) Node {
+			return b.Div(Style(tt.style))
+		})
+		blocked := strings.Contains(html, `style="`+unsafeValue+`"`)
+		if blocked == tt.safe {
+			t.Errorf("Style(%q): safe=%v, got %s", tt.style, tt.safe, html)
+		}
+	}
+
+	html := RenderToString(func(b *Builder) Node {
+		return b.Div(TrustedStyle(`content: "\201C"`))
+	})
+	if strings.Contains(html, unsafeValue) {
+		t.Errorf("TrustedStyle should bypass sanitisation, got: %s", html)
+	}
+}
+
+func TestHTMXValsRejectsScript(t *testing.T) {
+	html := RenderToString(func(b *Builder) Node {
+		return b.Div(HxVals(`js:{a: steal()}`), HtmxHeaders(`{"X-Token": "abc"}`))
+	})
+	if !strings.Contains(html, `hx-vals="`+unsafeValue+`"`) {
+		t.Errorf("js: hx-vals should be rejected, got: %s", html)
+	}
+	if !strings.Contains(html, `hx-headers="{&#34;X-Token&#34;: &#34;abc&#34;}"`) {
+		t.Errorf("JSON hx-headers should pass, got: %s", html)
+	}
+}
+
+func TestInvalidAttributeName(t *testing.T) {
+	for _, name := range []string{"", "a b", `x"onload="y`, "a>b", "a/b", "a=b"} {
+		var buf bytes.Buffer
+		err := Render(func(b *Builder) Node {
+			return b.Div(Attr(name, "v"))
+		}, &buf)
+
+		var attrErr *AttributeError
+		if !errors.As(err, &attrErr) {
+			t.Errorf("Attr(%q) should fail with AttributeError, got %v", name, err)
+		}
+	}
+
+	for _, name := range []string{"data-x", "aria-label", "hx-on:click", "xlink:href", "@click", "x-on:submit.prevent"} {
+		if !validAttributeName(name) {
+			t.Errorf("%q should be a valid attribute name", name)
+		}
+	}
+}
+
+// restoreURLSchemes puts back the global scheme allowlist when the test
+// ends. Tests that change it must not call t.Parallel.
+func restoreURLSchemes(t *testing.T) {
+	t.Helper()
+	urlSchemesMu.Lock()
+	saved := make(map[string]bool, len(urlSchemes))
+	for scheme, allowed := range urlSchemes {
+		saved[scheme] = allowed
+	}
+	urlSchemesMu.Unlock()
+	t.Cleanup(func() {
+		urlSchemesMu.Lock()
+		defer urlSchemesMu.Unlock()
+		urlSchemes = saved
+	})
+}
+
+func TestAllowURLScheme(t *testing.T) {
+	restoreURLSchemes(t)
+	AllowURLScheme("SMS")
+	html := RenderToString(func(b *Builder) Node {
+		return b.A(Href("sms:+123"), "text us")
+	})
+	if !strings.Contains(html, `href="sms:+123"`) {
+		t.Errorf("Registered scheme should be allowed, got: %s", html)
+	}
+}
+
+func TestClassificationCoversDocumentsAndHTMXForms(t *testing.T) {
+	html := RenderToString(func(b *Builder) Node {
+		return b.Div(
+			b.Iframe(Attr("srcdoc", "<script>steal()</script>")),
+			b.Iframe(TrustedAttr("srcdoc", "<p>ok</p>")),
+			b.Element("object", Attr("data", "javascript:alert(1)")),
+			b.Div(Data("hx-get", "javascript:alert(1)")),
+			b.Button(Data("hx-on:click", "steal()")),
+			b.Div(Data("hx-vals", "js:{a: steal()}")),
+			b.Div(Attr("ws-connect", "wss://example.com/chat"), Data("ws-connect", "javascript:alert(1)")),
+			b.Div(Data("href", "javascript:is-just-data")),
+		)
+	})
+	for _, want := range []string{
+		`<iframe srcdoc="` + unsafeValue + `">`,
+		`<iframe srcdoc="&lt;p&gt;ok&lt;/p&gt;">`,
+		`<object data="` + unsafeURL + `">`,
+		`data-hx-get="` + unsafeURL + `"`,
+		`data-hx-on:click="&#34;steal()&#34;"`,
+		`data-hx-vals="` + unsafeValue + `"`,
+		`ws-connect="wss://example.com/chat" data-ws-connect="` + unsafeURL + `"`,
+		`data-href="javascript:is-just-data"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %s in: %s", want, html)
+		}
+	}
+}
@@ -3,3 +3,7 @@ module github.com/ha1tch/insurance-quote
 go 1.22.2
 
 require github.com/ha1tch/minty v0.0.1
+
+// The example tracks the library in this repository, including packages
+// not yet in a tagged release.
+replace github.com/ha1tch/minty => ../..
@@ -672,7 +672,7 @@ func (h *Handler) Claims(w http.ResponseWriter, r *http.Request) {
 					mi.ID("view-toggle-btn"),
 					mi.Type("button"),
 					mi.Class("inline-flex items-center gap-2 px-3 py-2 text-sm font-medium text-gray-700 dark:text-gray-300 bg-white dark:bg-gray-700 border border-gray-300 dark:border-gray-600 rounded-lg hover:bg-gray-50 dark:hover:bg-gray-600"),
-					mi.Attr("onclick", "toggleClaimsView()"),
+					mi.On("click", "toggleClaimsView()"),
 					Icon("code-bracket", "w-4 h-4"), "JSON",
 				),
 			),
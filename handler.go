@@ -0,0 +1,299 @@
+package minty
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Handlers
+//
+// The handlers render into a buffer and write the page only once it is
+// complete, so a template that fails halfway produces a proper error
+// response instead of half a page followed by an error message. Handle
+// lets the handler function fail too, with a status code:
+//
+//	mux.Handle("/orders/{id}", mi.Handle(func(r *http.Request) (mi.H, error) {
+//	    order, err := store.Order(r.PathValue("id"))
+//	    if errors.Is(err, store.ErrNotFound) {
+//	        return nil, mi.NewHTTPError(http.StatusNotFound, "No such order")
+//	    }
+//	    if err != nil {
+//	        return nil, err // 500, logged, details not shown
+//	    }
+//	    return OrderPage(order), nil
+//	}))
+//
+// Errors become an error page, or for htmx requests that want a fragment,
+// an error fragment with HX-Reswap (and HX-Retarget if ErrorTarget is set).
+// htmx does not swap 4xx and 5xx responses by default; HTMXSwapErrors
+// renders the script that lets it swap these fragments. Responses carry
+// Vary: HX-Request, since they differ for htmx requests.
+
+// HTTPError is an error with an HTTP status and a message safe to show to
+// the user. Err, if set, is the underlying cause; it is logged for 5xx
+// statuses and never shown.
+type HTTPError struct {
+	Status  int
+	Message string
+	Err     error
+}
+
+// NewHTTPError creates an error with a status and a message for the user.
+// An empty message defaults to the status text.
+func NewHTTPError(status int, message string) *HTTPError {
+	return &HTTPError{Status: status, Message: message}
+}
+
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	s := "minty: " + strconv.Itoa(e.Status) + " " + e.Title()
+	if e.Message != "" && e.Message != e.Title() {
+		s += ": " + e.Message
+	}
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+// Unwrap returns the underlying cause.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// Title returns the status text, e.g. "Not Found".
+func (e *HTTPError) Title() string {
+	if text := http.StatusText(e.Status); text != "" {
+		return text
+	}
+	return "Error"
+}
+
+// Text returns the message, or the status text if there is none.
+func (e *HTTPError) Text() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return e.Title()
+}
+
+// httpError converts err to an *HTTPError. Other errors become a 500 whose
+// message does not reveal them.
+func httpError(err error) *HTTPError {
+	var he *HTTPError
+	if errors.As(err, &he) {
+		if he.Status < 400 || he.Status > 599 {
+			he = &HTTPError{Status: http.StatusInternalServerError, Message: he.Message, Err: he}
+		}
+		return he
+	}
+	return &HTTPError{Status: http.StatusInternalServerError, Err: err}
+}
+
+// HandlerOptions configures the error responses of the handlers.
+type HandlerOptions struct {
+	// ErrorPage renders errors for full page requests. It defaults to
+	// DefaultErrorPage.
+	ErrorPage func(*HTTPError) H
+
+	// ErrorFragment renders errors for htmx requests that want a fragment.
+	// It defaults to DefaultErrorFragment.
+	ErrorFragment func(*HTTPError) H
+
+	// ErrorTarget, if set, is sent as HX-Retarget with error fragments,
+	// e.g. "#errors". Otherwise the fragment goes to the request's target.
+	ErrorTarget string
+
+	// ErrorSwap is sent as HX-Reswap with error fragments. It defaults to
+	// innerHTML, so the target element itself is kept.
+	ErrorSwap string
+
+	// Log receives 5xx errors. It defaults to the standard logger.
+	Log func(r *http.Request, err error)
+}
+
+// HandlerOption configures HandlerOptions.
+type HandlerOption func(*HandlerOptions)
+
+// ErrorViews sets the templates used for error pages and fragments. A nil
+// function keeps the default.
+func ErrorViews(page, fragment func(*HTTPError) H) HandlerOption {
+	return func(o *HandlerOptions) {
+		if page != nil {
+			o.ErrorPage = page
+		}
+		if fragment != nil {
+			o.ErrorFragment = fragment
+		}
+	}
+}
+
+// ErrorTarget sends error fragments to the element matching selector with
+// the given swap strategy ("" for innerHTML).
+func ErrorTarget(selector, swap string) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.ErrorTarget = selector
+		o.ErrorSwap = swap
+	}
+}
+
+// LogErrors sets the function that receives 5xx errors.
+func LogErrors(fn func(r *http.Request, err error)) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Log = fn
+	}
+}
+
+func handlerOptions(opts []HandlerOption) *HandlerOptions {
+	o := &HandlerOptions{
+		ErrorPage:     DefaultErrorPage,
+		ErrorFragment: DefaultErrorFragment,
+		Log:           logError,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.ErrorSwap == "" {
+		o.ErrorSwap = HTMXSwapStrategies.InnerHTML
+	}
+	return o
+}
+
+func logError(r *http.Request, err error) {
+	log.Printf("minty: %s %s: %v", r.Method, r.URL.RequestURI(), err)
+}
+
+// DefaultErrorPage renders an error as a minimal document.
+func DefaultErrorPage(e *HTTPError) H {
+	return func(b *Builder) Node {
+		return Document(e.Title(), nil, b.Main(Class("error-page"),
+			b.H1(e.Title()),
+			If(e.Message != "" && e.Message != e.Title(), func(b *Builder) Node {
+				return b.P(e.Message)
+			})(b),
+		))(b)
+	}
+}
+
+// DefaultErrorFragment renders an error as an alert.
+func DefaultErrorFragment(e *HTTPError) H {
+	return func(b *Builder) Node {
+		return b.Div(Class("error"), Role("alert"), e.Text())
+	}
+}
+
+// Handle creates a handler from a function that returns a template or an
+// error. The template is rendered into a buffer and written only when it
+// is complete; errors from fn or from rendering become error responses.
+func Handle(fn func(*http.Request) (H, error), opts ...HandlerOption) http.HandlerFunc {
+	o := handlerOptions(opts)
+	return func(w http.ResponseWriter, r *http.Request) {
+		template, err := fn(r)
+		serve(w, r, template, err, o)
+	}
+}
+
+// HandleHTMX is Handle with separate functions for full pages and for
+// fragments, chosen as in HTMXHandlerFunc.
+func HandleHTMX(fullPage, fragment func(*http.Request) (H, error), opts ...HandlerOption) http.HandlerFunc {
+	o := handlerOptions(opts)
+	return func(w http.ResponseWriter, r *http.Request) {
+		fn := fullPage
+		if ParseHTMX(r).WantsFragment() {
+			fn = fragment
+		}
+		template, err := fn(r)
+		serve(w, r, template, err, o)
+	}
+}
+
+// serve renders template, or the error view for err, and writes it.
+func serve(w http.ResponseWriter, r *http.Request, template H, err error, o *HandlerOptions) {
+	addVary(w.Header(), "HX-Request")
+	if err == nil && template == nil {
+		err = errors.New("handler returned no template")
+	}
+	if err == nil {
+		buf := acquireBuffer()
+		defer releaseBuffer(buf)
+		if err = RenderWithContext(RequestContext(r), template, buf); err == nil {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			buf.WriteTo(w)
+			return
+		}
+	}
+	serveError(w, r, err, o)
+}
+
+// serveError writes the error view for err with its status.
+func serveError(w http.ResponseWriter, r *http.Request, err error, o *HandlerOptions) {
+	if r.Context().Err() != nil {
+		return // the client has gone
+	}
+	e := httpError(err)
+	if e.Status >= 500 && o.Log != nil {
+		o.Log(r, err)
+	}
+
+	hx := ParseHTMX(r)
+	view := o.ErrorPage
+	if hx.WantsFragment() {
+		view = o.ErrorFragment
+	}
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+	if err := RenderWithContext(RequestContext(r), view(e), buf); err != nil {
+		if o.Log != nil {
+			o.Log(r, fmt.Errorf("rendering error view: %w", err))
+		}
+		http.Error(w, e.Text(), e.Status)
+		return
+	}
+
+	if hx.Request {
+		if hx.WantsFragment() && o.ErrorTarget != "" {
+			w.Header().Set("HX-Retarget", o.ErrorTarget)
+		}
+		swap := o.ErrorSwap
+		if !hx.WantsFragment() {
+			swap = HTMXSwapStrategies.InnerHTML // boosted requests swap the body
+		}
+		w.Header().Set("HX-Reswap", swap)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(e.Status)
+	buf.WriteTo(w)
+}
+
+// addVary adds a field to the Vary header unless it is listed already.
+func addVary(h http.Header, field string) {
+	for _, value := range h.Values("Vary") {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v == "*" || strings.EqualFold(v, field) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", field)
+}
+
+// HTMXSwapErrors renders a script that lets htmx swap the error fragments
+// of these handlers, which it otherwise drops because of their 4xx or 5xx
+// status. Only error responses carrying HX-Reswap are swapped, so errors
+// from proxies and other servers still go to htmx:responseError.
+func HTMXSwapErrors() H {
+	return func(b *Builder) Node {
+		return b.Script(Raw(`document.addEventListener("htmx:beforeSwap", function(event) {
+  var xhr = event.detail.xhr;
+  if (xhr.status >= 400 && xhr.getResponseHeader("HX-Reswap")) {
+    event.detail.shouldSwap = true;
+    event.detail.isError = false;
+  }
+});`))
+	}
+}
@@ -0,0 +1,109 @@
+package minty
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleBuffersFailedRenders(t *testing.T) {
+	var logged []error
+	handler := Handle(func(r *http.Request) (H, error) {
+		return func(b *Builder) Node {
+			// badge requires a label, so rendering fails after the heading
+			return b.Main(b.H1("Orders"), badge.H(badgeProps{})(b))
+		}, nil
+	}, LogErrors(func(r *http.Request, err error) { logged = append(logged, err) }))
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest("GET", "/orders", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if strings.Contains(body, "Orders") || !strings.Contains(body, "<h1>Internal Server Error</h1>") {
+		t.Errorf("expected only the error page, got %s", body)
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0].Error(), "component badge") {
+		t.Errorf("logged %v", logged)
+	}
+	if rec.Header().Get("Vary") != "HX-Request" {
+		t.Errorf("Vary = %q", rec.Header().Get("Vary"))
+	}
+}
+
+func TestHandleMapsErrorsToStatusAndViews(t *testing.T) {
+	var logged []error
+	errNotFound := NewHTTPError(http.StatusNotFound, "No such order")
+	handler := HandleHTMX(
+		func(r *http.Request) (H, error) { return nil, errNotFound },
+		func(r *http.Request) (H, error) { return nil, errors.New("database is down") },
+		ErrorTarget("#errors", ""),
+		LogErrors(func(r *http.Request, err error) { logged = append(logged, err) }),
+	)
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest("GET", "/orders/7", nil))
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "<title>Not Found</title>") ||
+		!strings.Contains(rec.Body.String(), "<p>No such order</p>") {
+		t.Errorf("got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("HX-Reswap") != "" || len(logged) != 0 {
+		t.Errorf("4xx page should not be logged or carry htmx headers: %v %v", rec.Header(), logged)
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, htmxRequest(map[string]string{"HX-Request": "true", "HX-Target": "order"}))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d", rec.Code)
+	}
+	if rec.Body.String() != `<div class="error" role="alert">Internal Server Error</div>` {
+		t.Errorf("fragment = %s", rec.Body.String())
+	}
+	if rec.Header().Get("HX-Retarget") != "#errors" || rec.Header().Get("HX-Reswap") != "innerHTML" {
+		t.Errorf("headers = %v", rec.Header())
+	}
+	if len(logged) != 1 || logged[0].Error() != "database is down" {
+		t.Errorf("logged %v", logged)
+	}
+}
+
+func TestHandlersSetVary(t *testing.T) {
+	handler := HTMXHandler(
+		func(b *Builder) Node { return b.P("page") },
+		func(b *Builder) Node { return b.P("fragment") },
+	)
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Vary", "Accept-Encoding")
+	handler(rec, htmxRequest(map[string]string{"HX-Request": "true"}))
+	if rec.Body.String() != "<p>fragment</p>" || rec.Code != http.StatusOK {
+		t.Errorf("got %d %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Values("Vary"); len(got) != 2 || got[1] != "HX-Request" {
+		t.Errorf("Vary = %v", got)
+	}
+
+	addVary(rec.Header(), "hx-request")
+	if got := rec.Header().Values("Vary"); len(got) != 2 {
+		t.Errorf("Vary should not repeat fields: %v", got)
+	}
+
+	failing := HTMXHandler(
+		func(b *Builder) Node { return b.P("page") },
+		func(b *Builder) Node { return badge.Build(b, badgeProps{}) },
+		ErrorViews(nil, func(e *HTTPError) H {
+			return func(b *Builder) Node { return b.Strong(e.Text()) }
+		}),
+		ErrorTarget("#errors", "outerHTML"),
+		LogErrors(nil),
+	)
+	rec = httptest.NewRecorder()
+	failing(rec, htmxRequest(map[string]string{"HX-Request": "true"}))
+	if rec.Body.String() != "<strong>Internal Server Error</strong>" || rec.Header().Get("HX-Retarget") != "#errors" ||
+		rec.Header().Get("HX-Reswap") != "outerHTML" {
+		t.Errorf("handler options not applied: %v %s", rec.Header(), rec.Body.String())
+	}
+}
@@ -1,6 +1,7 @@
 package minty
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 )
@@ -37,19 +38,83 @@ func GetHTMXCurrentURL(r *http.Request) string {
 	return r.Header.Get("HX-Current-URL")
 }
 
+// HTMXRequest holds the request headers sent by htmx.
+type HTMXRequest struct {
+	Request        bool   // HX-Request: the request was made by htmx
+	Boosted        bool   // HX-Boosted: a link or form boosted with hx-boost
+	HistoryRestore bool   // HX-History-Restore-Request: a history cache miss
+	Target         string // HX-Target: id of the target element
+	Trigger        string // HX-Trigger: id of the triggering element
+	TriggerName    string // HX-Trigger-Name: name of the triggering element
+	CurrentURL     string // HX-Current-URL: URL of the browser
+	Prompt         string // HX-Prompt: the user's answer to hx-prompt
+}
+
+// ParseHTMX reads the htmx headers of a request. For requests not made by
+// htmx, Request is false and the other fields are empty.
+func ParseHTMX(r *http.Request) HTMXRequest {
+	if !IsHTMX(r) {
+		return HTMXRequest{}
+	}
+	return HTMXRequest{
+		Request:        true,
+		Boosted:        r.Header.Get("HX-Boosted") == "true",
+		HistoryRestore: r.Header.Get("HX-History-Restore-Request") == "true",
+		Target:         r.Header.Get("HX-Target"),
+		Trigger:        r.Header.Get("HX-Trigger"),
+		TriggerName:    r.Header.Get("HX-Trigger-Name"),
+		CurrentURL:     r.Header.Get("HX-Current-URL"),
+		Prompt:         r.Header.Get("HX-Prompt"),
+	}
+}
+
+// WantsFullPage reports whether the response should be a whole page with
+// its layout: a normal browser request, a boosted navigation, or a history
+// restore after a cache miss, which replaces the whole body.
+func (h HTMXRequest) WantsFullPage() bool {
+	return !h.Request || h.Boosted || h.HistoryRestore
+}
+
+// WantsFragment reports whether the response should be a partial for a
+// targeted swap.
+func (h HTMXRequest) WantsFragment() bool {
+	return !h.WantsFullPage()
+}
+
+// IsHistoryRestore reports whether htmx is restoring a page from history
+// because it was missing from its cache.
+func (h HTMXRequest) IsHistoryRestore() bool {
+	return h.HistoryRestore
+}
+
+// TargetIs reports whether the swap targets the element with the given
+// id. A leading "#" is ignored.
+func (h HTMXRequest) TargetIs(id string) bool {
+	return h.Target != "" && h.Target == strings.TrimPrefix(id, "#")
+}
+
+// TriggeredBy reports whether the request was triggered by the element
+// with the given id or name.
+func (h HTMXRequest) TriggeredBy(idOrName string) bool {
+	return idOrName != "" && (h.Trigger == idOrName || h.TriggerName == idOrName)
+}
+
 // HTMX Response Helpers
 
 // SetHTMXTrigger sets the HX-Trigger response header to trigger client-side events.
+// It replaces events added with Trigger; use Trigger to add to them.
 func SetHTMXTrigger(w http.ResponseWriter, events string) {
 	w.Header().Set("HX-Trigger", events)
 }
 
 // SetHTMXTriggerAfterSwap sets the HX-Trigger-After-Swap response header.
+// It replaces events added with TriggerAfterSwap.
 func SetHTMXTriggerAfterSwap(w http.ResponseWriter, events string) {
 	w.Header().Set("HX-Trigger-After-Swap", events)
 }
 
 // SetHTMXTriggerAfterSettle sets the HX-Trigger-After-Settle response header.
+// It replaces events added with TriggerAfterSettle.
 func SetHTMXTriggerAfterSettle(w http.ResponseWriter, events string) {
 	w.Header().Set("HX-Trigger-After-Settle", events)
 }
@@ -97,45 +162,49 @@ func RenderFragment(template H, w http.ResponseWriter) error {
 	return Render(template, w)
 }
 
-// RenderFragmentWithTrigger renders a fragment and sets trigger events.
+// RenderFragmentWithTrigger renders a fragment and adds trigger events to
+// the HX-Trigger header. triggerEvents is a comma-separated list of names
+// or a JSON object of events and details; events added earlier with
+// Trigger are kept.
 func RenderFragmentWithTrigger(template H, w http.ResponseWriter, triggerEvents string) error {
-	SetHTMXTrigger(w, triggerEvents)
+	events, err := parseTriggers(triggerEvents)
+	if err != nil {
+		return fmt.Errorf("minty: trigger events %q: %w", triggerEvents, err)
+	}
+	if err := mergeTriggers(w.Header(), HXTrigger, events); err != nil {
+		return err
+	}
 	return RenderFragment(template, w)
 }
 
 // HTMXHandler creates an HTTP handler that automatically handles HTMX vs full page requests.
-func HTMXHandler(fullPageTemplate H, fragmentTemplate H) http.HandlerFunc {
+// Boosted and history-restore requests get the full page; see
+// HTMXRequest.WantsFullPage. Like RenderHandler it renders into a buffer
+// first, and it sets Vary: HX-Request so caches keep the two apart.
+// Options such as ErrorViews and ErrorTarget configure the error responses.
+func HTMXHandler(fullPageTemplate H, fragmentTemplate H, opts ...HandlerOption) http.HandlerFunc {
+	o := handlerOptions(opts)
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		
-		if IsHTMX(r) {
-			if err := Render(fragmentTemplate, w); err != nil {
-				http.Error(w, "Fragment render error", http.StatusInternalServerError)
-			}
-		} else {
-			if err := Render(fullPageTemplate, w); err != nil {
-				http.Error(w, "Template render error", http.StatusInternalServerError)
-			}
+		template := fullPageTemplate
+		if ParseHTMX(r).WantsFragment() {
+			template = fragmentTemplate
 		}
+		serve(w, r, template, nil, o)
 	}
 }
 
 // HTMXHandlerFunc creates an HTTP handler from functions that return templates.
-func HTMXHandlerFunc(fullPageFn func(*http.Request) H, fragmentFn func(*http.Request) H) http.HandlerFunc {
+// Boosted and history-restore requests get the full page; fragmentFn can
+// call ParseHTMX to choose a partial by target or trigger. Use HandleHTMX
+// for functions that can fail.
+func HTMXHandlerFunc(fullPageFn func(*http.Request) H, fragmentFn func(*http.Request) H, opts ...HandlerOption) http.HandlerFunc {
+	o := handlerOptions(opts)
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		
-		if IsHTMX(r) {
-			template := fragmentFn(r)
-			if err := Render(template, w); err != nil {
-				http.Error(w, "Fragment render error", http.StatusInternalServerError)
-			}
-		} else {
-			template := fullPageFn(r)
-			if err := Render(template, w); err != nil {
-				http.Error(w, "Template render error", http.StatusInternalServerError)
-			}
+		fn := fullPageFn
+		if ParseHTMX(r).WantsFragment() {
+			fn = fragmentFn
 		}
+		serve(w, r, fn(r), nil, o)
 	}
 }
 
@@ -0,0 +1,128 @@
+package minty
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func htmxRequest(headers map[string]string) *http.Request {
+	r := httptest.NewRequest("GET", "/orders", nil)
+	for name, value := range headers {
+		r.Header.Set(name, value)
+	}
+	return r
+}
+
+func TestParseHTMX(t *testing.T) {
+	h := ParseHTMX(htmxRequest(map[string]string{
+		"HX-Request":      "true",
+		"HX-Target":       "order-list",
+		"HX-Trigger":      "search",
+		"HX-Trigger-Name": "q",
+		"HX-Current-URL":  "https://shop.example/orders",
+		"HX-Prompt":       "yes",
+	}))
+	want := HTMXRequest{
+		Request: true, Target: "order-list", Trigger: "search", TriggerName: "q",
+		CurrentURL: "https://shop.example/orders", Prompt: "yes",
+	}
+	if h != want {
+		t.Errorf("ParseHTMX = %+v, want %+v", h, want)
+	}
+	if h.WantsFullPage() || !h.TargetIs("#order-list") || !h.TargetIs("order-list") || h.TargetIs("orders") {
+		t.Errorf("targeted swap misclassified: %+v", h)
+	}
+	if !h.TriggeredBy("search") || !h.TriggeredBy("q") || h.TriggeredBy("") {
+		t.Errorf("TriggeredBy misreports %+v", h)
+	}
+
+	if h := ParseHTMX(htmxRequest(map[string]string{"HX-Target": "order-list"})); h.Request || h.Target != "" || !h.WantsFullPage() {
+		t.Errorf("headers without HX-Request should be ignored: %+v", h)
+	}
+	if h := ParseHTMX(htmxRequest(map[string]string{"HX-Request": "true", "HX-Boosted": "true"})); !h.WantsFullPage() {
+		t.Errorf("boosted requests want the full page: %+v", h)
+	}
+	h = ParseHTMX(htmxRequest(map[string]string{"HX-Request": "true", "HX-History-Restore-Request": "true"}))
+	if !h.IsHistoryRestore() || !h.WantsFullPage() {
+		t.Errorf("history restores want the full page: %+v", h)
+	}
+}
+
+func TestHTMXHandlerFuncServesFullPageForBoostAndHistory(t *testing.T) {
+	handler := HTMXHandlerFunc(
+		func(r *http.Request) H { return func(b *Builder) Node { return b.Body(b.P("page")) } },
+		func(r *http.Request) H { return func(b *Builder) Node { return b.P("fragment") } },
+	)
+	tests := []struct {
+		headers map[string]string
+		want    string
+	}{
+		{nil, "<body><p>page</p></body>"},
+		{map[string]string{"HX-Request": "true", "HX-Target": "main"}, "<p>fragment</p>"},
+		{map[string]string{"HX-Request": "true", "HX-Boosted": "true"}, "<body><p>page</p></body>"},
+		{map[string]string{"HX-Request": "true", "HX-History-Restore-Request": "true"}, "<body><p>page</p></body>"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler(rec, htmxRequest(tt.headers))
+		if rec.Body.String() != tt.want {
+			t.Errorf("headers %v: got %s, want %s", tt.headers, rec.Body.String(), tt.want)
+		}
+	}
+}
+
+func TestTriggerMergesEvents(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Trigger(rec, "refresh", nil)
+	Trigger(rec, "closeModal", nil)
+	if got := rec.Header().Get(HXTrigger); got != "refresh, closeModal" {
+		t.Errorf("HX-Trigger = %s", got)
+	}
+
+	Trigger(rec, "itemAdded", map[string]interface{}{"id": 7, "name": "Café <1>"})
+	Trigger(rec, "refresh", 2)
+	want := `{"refresh":2,"closeModal":null,"itemAdded":{"id":7,"name":"Caf\u00e9 \u003c1\u003e"}}`
+	if got := rec.Header().Get(HXTrigger); got != want {
+		t.Errorf("HX-Trigger = %s\nwant %s", got, want)
+	}
+
+	TriggerAfterSwap(rec, "swapped", nil)
+	TriggerAfterSettle(rec, "settled", true)
+	if rec.Header().Get(HXTriggerAfterSwap) != "swapped" || rec.Header().Get(HXTriggerAfterSettle) != `{"settled":true}` {
+		t.Errorf("phase headers: %v", rec.Header())
+	}
+
+	if err := Trigger(rec, "bad", func() {}); err == nil {
+		t.Error("unmarshalable detail should fail")
+	}
+	if err := Trigger(rec, " ", nil); err == nil {
+		t.Error("empty event name should fail")
+	}
+}
+
+func TestRenderFragmentWithTriggerKeepsEvents(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Trigger(rec, "itemAdded", map[string]int{"id": 7})
+	err := RenderFragmentWithTrigger(func(b *Builder) Node { return b.P("ok") }, rec, `{"notify":"Saved"}, ignored`)
+	if err == nil {
+		t.Fatal("malformed events should fail")
+	}
+	if err := RenderFragmentWithTrigger(func(b *Builder) Node { return b.P("ok") }, rec, `{"notify":"Saved"}`); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.Header().Get(HXTrigger); got != `{"itemAdded":{"id":7},"notify":"Saved"}` {
+		t.Errorf("HX-Trigger = %s", got)
+	}
+	if rec.Body.String() != "<p>ok</p>" {
+		t.Errorf("body = %s", rec.Body.String())
+	}
+}
+
+func TestHTMXEventListener(t *testing.T) {
+	got := RenderToString(HTMXEventListener("cartChanged", `render(detail.value)`))
+	want := "<script>document.addEventListener(\"cartChanged\", function(event) {\n  var detail = event.detail;\n  render(detail.value)\n});</script>"
+	if got != want {
+		t.Errorf("got %s", got)
+	}
+}
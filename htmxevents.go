@@ -0,0 +1,215 @@
+package minty
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// HTMX Response Events
+//
+// htmx fires the events named in the HX-Trigger response headers on the
+// element that made the request. Trigger and its variants add one event at
+// a time, so separate helpers in a handler can each announce what they did:
+//
+//	mi.Trigger(w, "itemAdded", map[string]interface{}{"id": item.ID})
+//	mi.Trigger(w, "cartChanged", cart.Count())
+//	// HX-Trigger: {"itemAdded":{"id":7},"cartChanged":3}
+//
+// Events without a detail are written as a plain list of names. Adding the
+// same event again replaces its detail. Details must be set before the
+// response is written.
+
+// HTMX trigger response headers, one per phase of the swap.
+const (
+	HXTrigger            = "HX-Trigger"
+	HXTriggerAfterSwap   = "HX-Trigger-After-Swap"
+	HXTriggerAfterSettle = "HX-Trigger-After-Settle"
+)
+
+// Trigger adds an event to the HX-Trigger header, fired as soon as the
+// response arrives. detail becomes event.detail on the client; values that
+// are not JSON objects arrive as event.detail.value. Pass nil for no detail.
+func Trigger(w http.ResponseWriter, event string, detail interface{}) error {
+	return addTrigger(w.Header(), HXTrigger, event, detail)
+}
+
+// TriggerAfterSwap adds an event fired after the new content is swapped in.
+func TriggerAfterSwap(w http.ResponseWriter, event string, detail interface{}) error {
+	return addTrigger(w.Header(), HXTriggerAfterSwap, event, detail)
+}
+
+// TriggerAfterSettle adds an event fired after the swapped content settles.
+func TriggerAfterSettle(w http.ResponseWriter, event string, detail interface{}) error {
+	return addTrigger(w.Header(), HXTriggerAfterSettle, event, detail)
+}
+
+// triggerEvent is one event in a trigger header. detail is nil for events
+// without one.
+type triggerEvent struct {
+	name   string
+	detail json.RawMessage
+}
+
+func addTrigger(h http.Header, header, event string, detail interface{}) error {
+	if strings.TrimSpace(event) == "" {
+		return fmt.Errorf("minty: %s: empty event name", header)
+	}
+	var raw json.RawMessage
+	if detail != nil {
+		var err error
+		if raw, err = json.Marshal(detail); err != nil {
+			return fmt.Errorf("minty: %s %q: %w", header, event, err)
+		}
+	}
+	return mergeTriggers(h, header, []triggerEvent{{event, raw}})
+}
+
+// mergeTriggers adds events to a trigger header, keeping the events it
+// already names in order.
+func mergeTriggers(h http.Header, header string, events []triggerEvent) error {
+	current, err := parseTriggers(h.Get(header))
+	if err != nil {
+		return fmt.Errorf("minty: existing %s header: %w", header, err)
+	}
+	for _, event := range events {
+		replaced := false
+		for i := range current {
+			if current[i].name == event.name {
+				current[i].detail = event.detail
+				replaced = true
+			}
+		}
+		if !replaced {
+			current = append(current, event)
+		}
+	}
+	if len(current) > 0 {
+		h.Set(header, formatTriggers(current))
+	}
+	return nil
+}
+
+// parseTriggers reads a trigger header written as a JSON object or as a
+// comma-separated list of event names.
+func parseTriggers(value string) ([]triggerEvent, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(value, "{") {
+		var events []triggerEvent
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				events = append(events, triggerEvent{name: name})
+			}
+		}
+		return events, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(value))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var events []triggerEvent
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var detail json.RawMessage
+		if err := dec.Decode(&detail); err != nil {
+			return nil, err
+		}
+		if string(detail) == "null" {
+			detail = nil
+		}
+		events = append(events, triggerEvent{key.(string), detail})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected content after the event object")
+	}
+	return events, nil
+}
+
+// formatTriggers writes events as a list of names when none has a detail
+// and as a JSON object otherwise. Non-ASCII characters are escaped so the
+// header survives clients that decode headers as Latin-1.
+func formatTriggers(events []triggerEvent) string {
+	plain := true
+	for _, event := range events {
+		if event.detail != nil {
+			plain = false
+		}
+	}
+	if plain {
+		names := make([]string, len(events))
+		for i, event := range events {
+			names[i] = event.name
+		}
+		return strings.Join(names, ", ")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, event := range events {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(event.name)
+		buf.Write(name)
+		buf.WriteByte(':')
+		if event.detail == nil {
+			buf.WriteString("null")
+		} else {
+			buf.Write(event.detail)
+		}
+	}
+	buf.WriteByte('}')
+	return asciiJSON(buf.String())
+}
+
+// asciiJSON escapes the non-ASCII characters of JSON text as \uXXXX.
+func asciiJSON(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r < utf8.RuneSelf:
+			sb.WriteRune(r)
+		case r > 0xFFFF:
+			r -= 0x10000
+			fmt.Fprintf(&sb, `\u%04x\u%04x`, 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+		default:
+			fmt.Fprintf(&sb, `\u%04x`, r)
+		}
+	}
+	return sb.String()
+}
+
+// HTMXEventListener renders a script that runs handler whenever the server
+// triggers event. The handler sees the event as event and its payload as
+// detail:
+//
+//	mi.HTMXEventListener("cartChanged", `document.getElementById("cart-count").textContent = detail.value`)
+//
+// The listener is attached to document, so it hears the event from any
+// element. Prefer it to hx-on for events with capitals in their name:
+// HTML lowercases attribute names, and hx-on:cartChanged would listen for
+// "cartchanged". The handler is TrustedJS: never build it from user input.
+func HTMXEventListener(event string, handler TrustedJS) H {
+	return func(b *Builder) Node {
+		name, _ := json.Marshal(event)
+		return b.Script(Raw("document.addEventListener(" + string(name) + ", function(event) {\n" +
+			"  var detail = event.detail;\n" +
+			"  " + string(handler) + "\n" +
+			"});"))
+	}
+}
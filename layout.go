@@ -6,103 +6,61 @@ import (
 
 // Layout creates a simple HTML page layout with title and content.
 // This is the basic layout pattern for Week 2.
-func Layout(title string, content H) H {
+func Layout(title string, content H, opts ...DocumentOption) H {
 	return func(b *Builder) Node {
-		return b.Html(
-			b.Head(
+		o := newDocumentOptions(opts)
+		return b.Html(append(o.htmlAttributes(),
+			b.Head(append([]interface{}{
 				b.Meta(Charset("utf-8")),
 				b.Meta(Name("viewport"), Content("width=device-width, initial-scale=1")),
 				b.Title(title),
-			),
+			}, o.headNodes(b, title)...)...),
 			b.Body(
 				content(b),
 			),
-		)
+		)...)
 	}
 }
 
 // LayoutWithCSS creates a layout with custom CSS styles.
-func LayoutWithCSS(title string, cssURL string, content H) H {
+func LayoutWithCSS(title string, cssURL string, content H, opts ...DocumentOption) H {
 	return func(b *Builder) Node {
-		return b.Html(
-			b.Head(
+		o := newDocumentOptions(opts)
+		return b.Html(append(o.htmlAttributes(),
+			b.Head(append([]interface{}{
 				b.Meta(Charset("utf-8")),
 				b.Meta(Name("viewport"), Content("width=device-width, initial-scale=1")),
 				b.Title(title),
 				b.Link(Rel("stylesheet"), Href(cssURL)),
-			),
+			}, o.headNodes(b, title)...)...),
 			b.Body(
 				content(b),
 			),
-		)
+		)...)
 	}
 }
 
 // LayoutWithMeta creates a layout with custom meta tags.
-func LayoutWithMeta(title, description string, keywords []string, content H) H {
-	return func(b *Builder) Node {
-		headChildren := []interface{}{
-			b.Meta(Charset("utf-8")),
-			b.Meta(Name("viewport"), Content("width=device-width, initial-scale=1")),
-			b.Title(title),
-		}
-		
-		if description != "" {
-			headChildren = append(headChildren, 
-				b.Meta(Name("description"), Content(description)))
-		}
-		
-		if len(keywords) > 0 {
-			keywordString := ""
-			for i, keyword := range keywords {
-				if i > 0 {
-					keywordString += ", "
-				}
-				keywordString += keyword
-			}
-			headChildren = append(headChildren,
-				b.Meta(Name("keywords"), Content(keywordString)))
-		}
-		
-		return b.Html(
-			b.Head(headChildren...),
-			b.Body(
-				content(b),
-			),
-		)
-	}
+func LayoutWithMeta(title, description string, keywords []string, content H, opts ...DocumentOption) H {
+	opts = append([]DocumentOption{
+		DocumentDescription(description),
+		DocumentKeywords(keywords...),
+	}, opts...)
+	return Layout(title, content, opts...)
 }
 
 // FullLayout creates a complete HTML5 layout with semantic structure.
-func FullLayout(title string, nav, main, aside, footer H) H {
-	return func(b *Builder) Node {
-		return b.Html(Lang("en"),
-			b.Head(
-				b.Meta(Charset("utf-8")),
-				b.Meta(Name("viewport"), Content("width=device-width, initial-scale=1")),
-				b.Title(title),
-			),
-			b.Body(
-				If(nav != nil, func(b *Builder) Node {
-					return b.Header(nav(b))
-				})(b),
-				
-				b.Main(main(b)),
-				
-				If(aside != nil, func(b *Builder) Node {
-					return aside(b)
-				})(b),
-				
-				If(footer != nil, func(b *Builder) Node {
-					return footer(b)
-				})(b),
-			),
-		)
-	}
+// It renders the Page component; nil sections are left out.
+func FullLayout(title string, nav, main, aside, footer H, opts ...DocumentOption) H {
+	return Page.H(PageProps{Title: title, Options: opts}, main,
+		Slot(SlotNav, nav),
+		Slot(SlotAside, aside),
+		Slot(SlotFooter, footer),
+	)
 }
 
 // ArticleLayout creates a layout optimized for article content.
-func ArticleLayout(title, author string, publishDate string, content H) H {
+func ArticleLayout(title, author string, publishDate string, content H, opts ...DocumentOption) H {
 	return Layout(title, func(b *Builder) Node {
 		return b.Article(
 			b.Header(
@@ -116,52 +74,19 @@ func ArticleLayout(title, author string, publishDate string, content H) H {
 			),
 			b.Section(content(b)),
 		)
-	})
+	}, opts...)
 }
 
-// CardLayout creates a card-style layout component.
+// CardLayout creates a card-style layout component. It renders the Card
+// component with actions in the actions slot.
 func CardLayout(title string, content H, actions H) H {
-	return func(b *Builder) Node {
-		return b.Article(Class("card"),
-			If(title != "", func(b *Builder) Node {
-				return b.Header(Class("card-header"),
-					b.H3(Class("card-title"), title),
-				)
-			})(b),
-			
-			b.Section(Class("card-content"),
-				content(b),
-			),
-			
-			If(actions != nil, func(b *Builder) Node {
-				return b.Footer(Class("card-actions"),
-					actions(b),
-				)
-			})(b),
-		)
-	}
+	return Card.H(CardProps{Title: title}, content, Slot(SlotActions, actions))
 }
 
-// ModalLayout creates a modal dialog layout.
+// ModalLayout creates a modal dialog layout. It renders the Modal
+// component.
 func ModalLayout(id, title string, content, actions H) H {
-	return func(b *Builder) Node {
-		return b.Dialog(ID(id), Class("modal"),
-			b.Header(Class("modal-header"),
-				b.H2(Class("modal-title"), title),
-				b.Button(Class("modal-close"), Type("button"), "×"),
-			),
-			
-			b.Section(Class("modal-body"),
-				content(b),
-			),
-			
-			If(actions != nil, func(b *Builder) Node {
-				return b.Footer(Class("modal-footer"),
-					actions(b),
-				)
-			})(b),
-		)
-	}
+	return Modal.H(ModalProps{ID: id, Title: title}, content, Slot(SlotActions, actions))
 }
 
 // Component creates a reusable component wrapper.
@@ -224,23 +149,21 @@ func FlexLayout(direction, justify, align string, content H) H {
 // HTTP Integration Helpers
 
 // RenderHandler creates an HTTP handler that renders a Minty template.
-func RenderHandler(template H) http.HandlerFunc {
+// The page is rendered into a buffer first, so a failed render sends an
+// error page with status 500 rather than a truncated page; see Handle.
+// Options such as ErrorViews configure the error responses.
+func RenderHandler(template H, opts ...HandlerOption) http.HandlerFunc {
+	o := handlerOptions(opts)
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		if err := Render(template, w); err != nil {
-			http.Error(w, "Template render error", http.StatusInternalServerError)
-		}
+		serve(w, r, template, nil, o)
 	}
 }
 
 // RenderHandlerFunc creates an HTTP handler from a function that returns a template.
-func RenderHandlerFunc(fn func(*http.Request) H) http.HandlerFunc {
+func RenderHandlerFunc(fn func(*http.Request) H, opts ...HandlerOption) http.HandlerFunc {
+	o := handlerOptions(opts)
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		template := fn(r)
-		if err := Render(template, w); err != nil {
-			http.Error(w, "Template render error", http.StatusInternalServerError)
-		}
+		serve(w, r, fn(r), nil, o)
 	}
 }
 
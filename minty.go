@@ -3,10 +3,9 @@
 package minty
 
 import (
-	"fmt"
+	"context"
 	"html"
 	"io"
-	"strings"
 )
 
 // Node represents any HTML content that can be rendered.
@@ -20,44 +19,206 @@ type H func(*Builder) Node
 // Element represents an HTML tag with attributes and children.
 type Element struct {
 	Tag         string
-	Attributes  map[string]string
+	Attributes  AttributeList
 	Children    []Node
 	SelfClosing bool
+
+	// issues records arguments the builder could not use as given; strict
+	// mode reports them (see strict.go).
+	issues []string
+}
+
+// AttributePair is a single attribute name and value.
+type AttributePair struct {
+	Name  string
+	Value string
+
+	// trusted values skip contextual sanitisation (see escape.go).
+	trusted bool
+}
+
+// AttributeList holds an element's attributes in insertion order.
+// Overriding an attribute keeps its original position, so identical
+// templates always render byte-identical output. Elements rarely carry
+// more than a handful of attributes, so lookups scan the slice linearly.
+type AttributeList []AttributePair
+
+// Get returns the value of the named attribute and whether it is present.
+func (l AttributeList) Get(name string) (string, bool) {
+	if i := l.index(name); i >= 0 {
+		return l[i].Value, true
+	}
+	return "", false
+}
+
+// Has reports whether the named attribute is present.
+func (l AttributeList) Has(name string) bool {
+	return l.index(name) >= 0
+}
+
+// Set adds an attribute or replaces the value of an existing one in place.
+func (l *AttributeList) Set(name, value string) {
+	l.set(name, value, false)
+}
+
+func (l *AttributeList) setTrusted(name, value string) {
+	l.set(name, value, true)
+}
+
+func (l *AttributeList) set(name, value string, trusted bool) {
+	if i := l.index(name); i >= 0 {
+		(*l)[i].Value = value
+		(*l)[i].trusted = trusted
+		return
+	}
+	*l = append(*l, AttributePair{Name: name, Value: value, trusted: trusted})
+}
+
+// Remove deletes the named attribute, preserving the order of the rest.
+func (l *AttributeList) Remove(name string) {
+	if i := l.index(name); i >= 0 {
+		*l = append((*l)[:i], (*l)[i+1:]...)
+	}
+}
+
+// Len returns the number of attributes.
+func (l AttributeList) Len() int {
+	return len(l)
+}
+
+func (l AttributeList) index(name string) int {
+	for i := range l {
+		if l[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetAttribute sets an attribute, replacing any existing value in place.
+func (e *Element) SetAttribute(name, value string) {
+	e.Attributes.Set(name, value)
+}
+
+// GetAttribute returns the value of an attribute and whether it is present.
+func (e *Element) GetAttribute(name string) (string, bool) {
+	return e.Attributes.Get(name)
+}
+
+// HasAttribute reports whether the element has the named attribute.
+func (e *Element) HasAttribute(name string) bool {
+	return e.Attributes.Has(name)
+}
+
+// RemoveAttribute deletes an attribute from the element.
+func (e *Element) RemoveAttribute(name string) {
+	e.Attributes.Remove(name)
 }
 
 // Render outputs the element as HTML.
 func (e *Element) Render(w io.Writer) error {
+	sw, ok := bufferedWriter(w)
+	if !ok {
+		return renderBuffered(e, w)
+	}
+	rw, _ := sw.(*renderWriter)
+	if rw != nil {
+		if err := rw.checkContext(); err != nil {
+			return err
+		}
+	}
+	if rw != nil && rw.compile != nil && hashesContent(e) {
+		return rw.compile.inline(e)
+	}
+
 	// Write opening tag
-	if _, err := w.Write([]byte("<" + e.Tag)); err != nil {
+	if err := sw.WriteByte('<'); err != nil {
+		return err
+	}
+	if _, err := sw.WriteString(e.Tag); err != nil {
 		return err
 	}
 
-	// Write attributes
-	for key, value := range e.Attributes {
-		if _, err := fmt.Fprintf(w, ` %s="%s"`, key, html.EscapeString(value)); err != nil {
+	// Write attributes, sanitising untrusted values for their context
+	animatesURL := animatesURLAttribute(e)
+	for _, attr := range e.Attributes {
+		if !validAttributeName(attr.Name) {
+			return &AttributeError{Tag: e.Tag, Name: attr.Name}
+		}
+		if rw != nil && rw.compile != nil {
+			if err := rw.compile.checkAttribute(e.Tag, attr); err != nil {
+				return err
+			}
+		}
+		if rw != nil && rw.csp != nil && rw.csp.dropsAttribute(e.Tag, attr) {
+			continue
+		}
+		value := attr.Value
+		if !attr.trusted {
+			value = sanitizeAttributeValue(e.Tag, attr.Name, value)
+			if animatesURL {
+				value = sanitizeAnimationValue(attr.Name, value)
+			}
+		}
+		if err := sw.WriteByte(' '); err != nil {
+			return err
+		}
+		if _, err := sw.WriteString(attr.Name); err != nil {
+			return err
+		}
+		if _, err := sw.WriteString(`="`); err != nil {
+			return err
+		}
+		if err := writeEscaped(sw, value); err != nil {
+			return err
+		}
+		if err := sw.WriteByte('"'); err != nil {
 			return err
 		}
 	}
 
 	if e.SelfClosing {
-		_, err := w.Write([]byte(" />"))
+		_, err := sw.WriteString(" />")
 		return err
 	}
 
-	if _, err := w.Write([]byte(">")); err != nil {
+	if err := sw.WriteByte('>'); err != nil {
 		return err
 	}
 
-	// Render children
-	for _, child := range e.Children {
-		if err := child.Render(w); err != nil {
+	if rw != nil && rw.compile != nil {
+		if err := rw.compile.checkRawText(e); err != nil {
 			return err
 		}
 	}
 
+	// Render children, hashing inline scripts and styles for the CSP
+	if rw != nil && rw.csp != nil && hashesContent(e) {
+		if err := rw.csp.renderHashed(e, sw); err != nil {
+			return err
+		}
+	} else {
+		for _, child := range e.Children {
+			if err := child.Render(sw); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Write closing tag
-	_, err := fmt.Fprintf(w, "</%s>", e.Tag)
-	return err
+	if _, err := sw.WriteString("</"); err != nil {
+		return err
+	}
+	if _, err := sw.WriteString(e.Tag); err != nil {
+		return err
+	}
+	if err := sw.WriteByte('>'); err != nil {
+		return err
+	}
+	if rw != nil && len(rw.flushAfter) > 0 {
+		return rw.closedTag(e.Tag)
+	}
+	return nil
 }
 
 // TextNode represents escaped text content.
@@ -67,8 +228,10 @@ type TextNode struct {
 
 // Render outputs the text with HTML escaping.
 func (t *TextNode) Render(w io.Writer) error {
-	escaped := html.EscapeString(t.Content)
-	_, err := w.Write([]byte(escaped))
+	if sw, ok := w.(stringWriter); ok {
+		return writeEscaped(sw, t.Content)
+	}
+	_, err := io.WriteString(w, html.EscapeString(t.Content))
 	return err
 }
 
@@ -79,7 +242,7 @@ type RawNode struct {
 
 // Render outputs unescaped content.
 func (r *RawNode) Render(w io.Writer) error {
-	_, err := w.Write([]byte(r.Content))
+	_, err := io.WriteString(w, r.Content)
 	return err
 }
 
@@ -90,6 +253,9 @@ type Fragment struct {
 
 // Render outputs all child nodes.
 func (f *Fragment) Render(w io.Writer) error {
+	if _, ok := bufferedWriter(w); !ok && len(f.Children) > 1 {
+		return renderBuffered(f, w)
+	}
 	for _, child := range f.Children {
 		if err := child.Render(w); err != nil {
 			return err
@@ -109,12 +275,17 @@ type StringAttribute struct {
 	Value string
 }
 
-// Apply adds the string attribute to an element.
+// Apply adds the string attribute to an element. Class, style and other
+// token list attributes merge with an existing value (see classes.go);
+// other attributes replace it.
 func (sa StringAttribute) Apply(element *Element) {
-	if element.Attributes == nil {
-		element.Attributes = make(map[string]string)
+	if existing, ok := element.Attributes.Get(sa.Name); ok {
+		if merged, ok := mergeAttribute(sa.Name, existing, sa.Value); ok {
+			element.Attributes.Set(sa.Name, merged)
+			return
+		}
 	}
-	element.Attributes[sa.Name] = sa.Value
+	element.Attributes.Set(sa.Name, sa.Value)
 }
 
 // BooleanAttribute represents a boolean HTML attribute.
@@ -124,10 +295,7 @@ type BooleanAttribute struct {
 
 // Apply adds the boolean attribute to an element.
 func (ba BooleanAttribute) Apply(element *Element) {
-	if element.Attributes == nil {
-		element.Attributes = make(map[string]string)
-	}
-	element.Attributes[ba.Name] = ba.Name
+	element.Attributes.Set(ba.Name, ba.Name)
 }
 
 // Raw creates a node with unescaped HTML content.
@@ -145,21 +313,42 @@ func (b *Builder) Text(content string) Node {
 	return &TextNode{Content: content}
 }
 
-// Render renders a template to the provided writer.
+// Render renders a template to the provided writer. Output is buffered
+// through a pooled writer unless w already buffers in memory.
 func Render(template H, w io.Writer) error {
-	node := template(B)
-	return node.Render(w)
+	node := template(NewBuilder(context.Background()))
+	if err := checkStrict(context.Background(), node); err != nil {
+		return err
+	}
+	if _, ok := bufferedWriter(w); ok {
+		return node.Render(w)
+	}
+	return renderBuffered(node, w)
 }
 
 // RenderToString renders a template and returns the HTML as a string.
+// Render errors produce an empty string; use RenderToStringE to see them.
 func RenderToString(template H) string {
-	var buf strings.Builder
-	if err := Render(template, &buf); err != nil {
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+	if err := Render(template, buf); err != nil {
 		return ""
 	}
 	return buf.String()
 }
 
+// RenderToStringE renders a template and returns the HTML as a string, or
+// the error that stopped rendering, including a *StrictError in strict
+// mode.
+func RenderToStringE(template H) (string, error) {
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+	if err := Render(template, buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
 // Txt creates a text node (standalone function, alias for b.Text).
 func Txt(content string) Node {
 	return &TextNode{Content: content}
@@ -171,27 +360,37 @@ func RawHTML(content string) Node {
 }
 
 // Document creates a complete HTML document structure.
-// It takes a title string, head nodes slice, and body node.
-func Document(title string, headNodes []Node, body Node) H {
+// It takes a title string, head nodes slice, and body node. Options set
+// the language (default "en"), direction and page metadata. Assets
+// registered with b.Require are emitted at the end of <head> and <body>.
+func Document(title string, headNodes []Node, body Node, opts ...DocumentOption) H {
 	return func(b *Builder) Node {
-		// Build head content with title and additional nodes
+		o := newDocumentOptions(opts)
+
+		// Build head content with title, metadata and additional nodes
 		headChildren := []interface{}{
 			b.Title(title),
 			b.Meta(Charset("UTF-8")),
 			b.Meta(Name("viewport"), Content("width=device-width, initial-scale=1")),
 		}
+		headChildren = append(headChildren, o.headNodes(b, title)...)
 		for _, node := range headNodes {
 			headChildren = append(headChildren, node)
 		}
-		
+		headChildren = append(headChildren, b.AssetSlot(AssetHead))
+
+		// Registered body assets go just before </body>
+		bodySlot := b.AssetSlot(AssetBodyEnd)
+		if el, ok := body.(*Element); ok && el.Tag == "body" {
+			el.Children = append(el.Children, bodySlot)
+		} else {
+			body = NewFragment(body, bodySlot)
+		}
+
+		htmlChildren := append(o.htmlAttributes(), b.Head(headChildren...), body)
 		return NewFragment(
 			Raw("<!DOCTYPE html>"),
-			b.Html(Lang("en"),
-				b.Head(headChildren...),
-				body,
-			),
+			b.Html(htmlChildren...),
 		)
 	}
 }
-
-
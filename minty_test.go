@@ -3,6 +3,9 @@ package minty
 import (
 	"bytes"
 	"fmt"
+	"html"
+	"io"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -335,7 +338,7 @@ func TestBasicLayout(t *testing.T) {
 	template := Layout("Test Page", content)
 	html := RenderToString(template)
 	
-	if !strings.Contains(html, "<html>") {
+	if !strings.Contains(html, `<html lang="en">`) {
 		t.Error("HTML wrapper missing from layout")
 	}
 	if !strings.Contains(html, "<title>Test Page</title>") {
@@ -860,3 +863,201 @@ func TestRepeatFunction(t *testing.T) {
 		t.Errorf("Expected %q, got %q", expected, html)
 	}
 }
+
+// Attribute order must be stable so identical pages produce identical bytes.
+func TestAttributeOrderIsDeterministic(t *testing.T) {
+	template := func(b *Builder) Node {
+		return b.Input(
+			Type("text"), Name("q"), ID("search"), Class("input"),
+			Placeholder("Search..."), HtmxGet("/search"), HtmxTarget("#results"),
+			Required(), Data("role", "search"),
+		)
+	}
+
+	expected := `<input type="text" name="q" id="search" class="input" placeholder="Search..." hx-get="/search" hx-target="#results" required="required" data-role="search" />`
+	for i := 0; i < 50; i++ {
+		if html := RenderToString(template); html != expected {
+			t.Fatalf("Render %d produced %q, expected %q", i, html, expected)
+		}
+	}
+}
+
+func TestAttributeOverrideKeepsPosition(t *testing.T) {
+	template := func(b *Builder) Node {
+		return b.A(Href("/old"), Class("link"), Href("/new"), "Go")
+	}
+
+	html := RenderToString(template)
+	if html != `<a href="/new" class="link">Go</a>` {
+		t.Errorf("Override should replace value in place, got: %s", html)
+	}
+}
+
+func TestElementAttributeAccessors(t *testing.T) {
+	el := B.Div(ID("main"), Class("box")).(*Element)
+
+	if v, ok := el.GetAttribute("id"); !ok || v != "main" {
+		t.Errorf("GetAttribute(id) = %q, %v", v, ok)
+	}
+	el.SetAttribute("role", "region")
+	el.RemoveAttribute("id")
+	if el.HasAttribute("id") {
+		t.Error("RemoveAttribute did not remove id")
+	}
+
+	var buf bytes.Buffer
+	el.Render(&buf)
+	if buf.String() != `<div class="box" role="region"></div>` {
+		t.Errorf("Unexpected render after edits: %s", buf.String())
+	}
+}
+
+// flushRecorder records how much output had been written at each flush.
+type flushRecorder struct {
+	bytes.Buffer
+	flushes []string
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushes = append(f.flushes, f.String())
+}
+
+func TestRenderStreamFlushesAfterHead(t *testing.T) {
+	page := func(b *Builder) Node {
+		return b.Html(
+			b.Head(b.Title("Streamed")),
+			b.Body(b.P("first"), FlushPoint(), b.P("second")),
+		)
+	}
+
+	var rec flushRecorder
+	if err := RenderStream(page, &rec); err != nil {
+		t.Fatalf("RenderStream failed: %v", err)
+	}
+
+	if len(rec.flushes) != 3 {
+		t.Fatalf("Expected flushes after </head>, at FlushPoint and at the end, got %d", len(rec.flushes))
+	}
+	if !strings.HasSuffix(rec.flushes[0], "</head>") {
+		t.Errorf("First flush should end at </head>, got %q", rec.flushes[0])
+	}
+	if !strings.HasSuffix(rec.flushes[1], "<p>first</p>") {
+		t.Errorf("Second flush should end at the flush point, got %q", rec.flushes[1])
+	}
+	if rec.String() != "<html><head><title>Streamed</title></head><body><p>first</p><p>second</p></body></html>" {
+		t.Errorf("Unexpected streamed output: %s", rec.String())
+	}
+}
+
+func TestRenderToUnbufferedWriter(t *testing.T) {
+	var rec flushRecorder
+	w := struct{ io.Writer }{&rec} // hides bytes.Buffer so the pooled writer is used
+
+	if err := Render(func(b *Builder) Node {
+		return b.Div(Class("a&b"), "x < y", Raw("<br>"))
+	}, w); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if rec.String() != `<div class="a&amp;b">x &lt; y<br></div>` {
+		t.Errorf("Unexpected output: %s", rec.String())
+	}
+}
+
+// Benchmarks
+
+// benchmarkPage builds a dashboard-sized page with a few thousand nodes.
+func benchmarkPage(b *Builder) Node {
+	rows := make([]Node, 0, 500)
+	for i := 0; i < 500; i++ {
+		rows = append(rows, b.Tr(Class("row"), Data("id", strconv.Itoa(i)),
+			b.Td(Class("cell name"), "Item ", i),
+			b.Td(Class("cell status"), b.Span(Class("badge"), "active & <ok>")),
+			b.Td(b.A(Href("/items/"+strconv.Itoa(i)), HxGet("/items/"+strconv.Itoa(i)), HxTarget("#detail"), "View")),
+		))
+	}
+	return b.Html(
+		b.Head(b.Title("Dashboard"), b.Meta(Charset("utf-8"))),
+		b.Body(b.Table(Class("table"), b.Tbody(NewFragment(rows...)))),
+	)
+}
+
+// legacyRender reproduces the previous fmt-based render path for comparison.
+func legacyRender(n Node, w io.Writer) error {
+	switch v := n.(type) {
+	case *Element:
+		if _, err := w.Write([]byte("<" + v.Tag)); err != nil {
+			return err
+		}
+		for _, attr := range v.Attributes {
+			if _, err := fmt.Fprintf(w, ` %s="%s"`, attr.Name, html.EscapeString(attr.Value)); err != nil {
+				return err
+			}
+		}
+		if v.SelfClosing {
+			_, err := w.Write([]byte(" />"))
+			return err
+		}
+		w.Write([]byte(">"))
+		for _, child := range v.Children {
+			if err := legacyRender(child, w); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintf(w, "</%s>", v.Tag)
+		return err
+	case *Fragment:
+		for _, child := range v.Children {
+			if err := legacyRender(child, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *TextNode:
+		_, err := w.Write([]byte(html.EscapeString(v.Content)))
+		return err
+	}
+	return n.Render(w)
+}
+
+func BenchmarkRenderLegacy(b *testing.B) {
+	node := benchmarkPage(B)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var buf strings.Builder
+		legacyRender(node, &buf)
+	}
+}
+
+func BenchmarkRenderToString(b *testing.B) {
+	node := benchmarkPage(B)
+	template := func(*Builder) Node { return node }
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		RenderToString(template)
+	}
+}
+
+func BenchmarkRenderToWriter(b *testing.B) {
+	node := benchmarkPage(B)
+	template := func(*Builder) Node { return node }
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Render(template, io.Discard)
+	}
+}
+
+func BenchmarkRenderStream(b *testing.B) {
+	node := benchmarkPage(B)
+	template := func(*Builder) Node { return node }
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		RenderStream(template, io.Discard)
+	}
+}
+
+func BenchmarkBuildAndRender(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		RenderToString(benchmarkPage)
+	}
+}
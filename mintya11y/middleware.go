@@ -0,0 +1,107 @@
+package mintya11y
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ReportFunc receives the violations found on a served page.
+type ReportFunc func(r *http.Request, violations []Violation)
+
+// ReportTo sets the function Middleware passes violations to.
+func ReportTo(fn ReportFunc) Option {
+	return func(o *Options) {
+		o.Report = fn
+	}
+}
+
+// LogReport logs violations with the standard logger.
+func LogReport(r *http.Request, violations []Violation) {
+	log.Printf("mintya11y: %s %s: %d accessibility violations\n%s",
+		r.Method, r.URL.RequestURI(), len(violations), indent(Format(violations)))
+}
+
+// Middleware checks every HTML response served by next, such as the pages
+// of mi.RenderHandler, and reports the violations. The response is passed
+// through unchanged. It buffers a copy of each HTML body, so enable it in
+// development only:
+//
+//	if dev {
+//	    handler = ma11y.Middleware(handler, ma11y.Warn("heading-order"))
+//	}
+func Middleware(next http.Handler, opts ...Option) http.Handler {
+	o := Options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	report := o.Report
+	if report == nil {
+		report = LogReport
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &recorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if !rec.html {
+			return
+		}
+		violations, err := CheckHTML(rec.body.String(), opts...)
+		if err != nil {
+			log.Printf("mintya11y: %s %s: cannot parse response: %v", r.Method, r.URL.RequestURI(), err)
+			return
+		}
+		if len(violations) > 0 {
+			report(r, violations)
+		}
+	})
+}
+
+// recorder passes a response through and keeps a copy of HTML bodies.
+type recorder struct {
+	http.ResponseWriter
+	body    bytes.Buffer
+	html    bool
+	started bool
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	rec.start()
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	if !rec.started {
+		if rec.Header().Get("Content-Type") == "" {
+			rec.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		rec.start()
+	}
+	if rec.html {
+		rec.body.Write(p)
+	}
+	return rec.ResponseWriter.Write(p)
+}
+
+// Flush lets streamed pages reach the client as they are written.
+func (rec *recorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+func (rec *recorder) start() {
+	if !rec.started {
+		rec.started = true
+		rec.html = strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html")
+	}
+}
+
+func indent(s string) string {
+	return "\t" + strings.ReplaceAll(s, "\n", "\n\t")
+}
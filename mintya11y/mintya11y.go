@@ -0,0 +1,328 @@
+// Package mintya11y checks minty node trees for common accessibility
+// problems: images without alt text, unlabelled form controls, buttons and
+// links without names, skipped heading levels, broken ARIA references and
+// similar mistakes that are cheap to catch before a page ships.
+//
+// Check an evaluated tree, or markup parsed back with CheckHTML:
+//
+//	for _, v := range ma11y.Check(page(mi.B)) {
+//	    fmt.Println(v) // main > img: image has no alt attribute [image-alt, WCAG 1.1.1]
+//	}
+//
+// Every rule has an ID and can be disabled or downgraded to a warning:
+//
+//	ma11y.Check(node, ma11y.Disable("heading-order"), ma11y.Warn("link-name"))
+//
+// In tests, mintytest's Document.Accessible fails on violations. During
+// development, Middleware logs the violations of every HTML page a
+// handler serves.
+//
+// The rules are static checks on markup. They do not replace testing with
+// assistive technology, and they cannot judge whether alt text or labels
+// are meaningful, only whether they are there.
+//
+// Import with: import ma11y "github.com/ha1tch/minty/mintya11y"
+package mintya11y
+
+import (
+	"fmt"
+	"strings"
+
+	mi "github.com/ha1tch/minty"
+)
+
+// Severity ranks a violation.
+type Severity int
+
+const (
+	// Error is a violation that blocks users of assistive technology.
+	Error Severity = iota
+	// Warning is a likely problem that needs a human to judge.
+	Warning
+)
+
+// String returns "error" or "warning".
+func (s Severity) String() string {
+	if s == Warning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Violation is one rule failure on one element.
+type Violation struct {
+	Rule     string // rule ID, e.g. "image-alt"
+	WCAG     string // success criterion, e.g. "1.1.1"
+	Severity Severity
+	Path     string // element path, e.g. "main > form#signup > input"
+	Message  string
+}
+
+// String returns the violation as "path: message [rule, WCAG x.y.z]".
+func (v Violation) String() string {
+	s := v.Path + ": " + v.Message + " [" + v.Rule
+	if v.WCAG != "" {
+		s += ", WCAG " + v.WCAG
+	}
+	s += "]"
+	if v.Severity == Warning {
+		s = "warning: " + s
+	}
+	return s
+}
+
+// Rule is a check run on every element of a tree.
+type Rule struct {
+	ID          string
+	WCAG        string
+	Description string
+	Severity    Severity
+
+	// Check returns a message describing the problem with the target
+	// element, or "" if there is none.
+	Check func(t *Target) string
+}
+
+// Target is the element a rule is checking, with its surroundings.
+type Target struct {
+	Element   *mi.Element
+	Ancestors []*mi.Element // outermost first
+	Document  *Document
+	index     int
+}
+
+// Attr returns an attribute of the target element.
+func (t *Target) Attr(name string) (string, bool) {
+	return t.Element.GetAttribute(name)
+}
+
+// Previous calls fn for the elements before the target in document
+// order, nearest first, until fn returns false.
+func (t *Target) Previous(fn func(*mi.Element) bool) {
+	for i := t.index - 1; i >= 0; i-- {
+		if !fn(t.Document.elements[i]) {
+			return
+		}
+	}
+}
+
+// Document indexes the tree being checked.
+type Document struct {
+	Root     mi.Node
+	elements []*mi.Element
+	ids      map[string]*mi.Element
+	labels   map[string]bool // ids named by label[for]
+	complete bool
+}
+
+// ElementByID returns the element with the given id, or nil.
+func (d *Document) ElementByID(id string) *mi.Element {
+	return d.ids[id]
+}
+
+// HasLabelFor reports whether a <label for=id> exists.
+func (d *Document) HasLabelFor(id string) bool {
+	return d.labels[id]
+}
+
+// Complete reports whether the tree is a whole page with an <html>
+// element, rather than a fragment whose references may point elsewhere.
+func (d *Document) Complete() bool {
+	return d.complete
+}
+
+// Options configures a check.
+type Options struct {
+	Disabled map[string]bool
+	Only     map[string]bool // when set, the rules to run
+	Severity map[string]Severity
+	Rules    []Rule // run in addition to DefaultRules
+
+	// Report receives the violations found by Middleware. It defaults to
+	// logging them with the standard logger.
+	Report ReportFunc
+}
+
+// Option configures Options.
+type Option func(*Options)
+
+// Disable turns off the rules with the given IDs.
+func Disable(ids ...string) Option {
+	return func(o *Options) {
+		if o.Disabled == nil {
+			o.Disabled = make(map[string]bool)
+		}
+		for _, id := range ids {
+			o.Disabled[id] = true
+		}
+	}
+}
+
+// Only runs just the rules with the given IDs.
+func Only(ids ...string) Option {
+	return func(o *Options) {
+		o.Only = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			o.Only[id] = true
+		}
+	}
+}
+
+// Warn reports the rules with the given IDs as warnings.
+func Warn(ids ...string) Option {
+	return WithSeverity(Warning, ids...)
+}
+
+// WithSeverity sets the severity of the rules with the given IDs.
+func WithSeverity(s Severity, ids ...string) Option {
+	return func(o *Options) {
+		if o.Severity == nil {
+			o.Severity = make(map[string]Severity)
+		}
+		for _, id := range ids {
+			o.Severity[id] = s
+		}
+	}
+}
+
+// WithRules adds custom rules.
+func WithRules(rules ...Rule) Option {
+	return func(o *Options) {
+		o.Rules = append(o.Rules, rules...)
+	}
+}
+
+// Check runs the enabled rules on every element under node and returns
+// the violations in document order.
+func Check(node mi.Node, opts ...Option) []Violation {
+	o := Options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var rules []Rule
+	for _, rule := range append(DefaultRules(), o.Rules...) {
+		if o.Disabled[rule.ID] || (o.Only != nil && !o.Only[rule.ID]) {
+			continue
+		}
+		if s, ok := o.Severity[rule.ID]; ok {
+			rule.Severity = s
+		}
+		rules = append(rules, rule)
+	}
+
+	doc := index(node)
+	var violations []Violation
+	i := 0
+	walk(node, nil, func(el *mi.Element, ancestors []*mi.Element) {
+		t := &Target{Element: el, Ancestors: ancestors, Document: doc, index: i}
+		i++
+		for _, rule := range rules {
+			if msg := rule.Check(t); msg != "" {
+				violations = append(violations, Violation{
+					Rule:     rule.ID,
+					WCAG:     rule.WCAG,
+					Severity: rule.Severity,
+					Path:     path(append(ancestors[:len(ancestors):len(ancestors)], el)),
+					Message:  msg,
+				})
+			}
+		}
+	})
+	return violations
+}
+
+// CheckHTML parses markup and checks it.
+func CheckHTML(html string, opts ...Option) ([]Violation, error) {
+	root, err := mi.ParseHTML(html)
+	if err != nil {
+		return nil, err
+	}
+	return Check(root, opts...), nil
+}
+
+// Errors returns the violations with Error severity.
+func Errors(violations []Violation) []Violation {
+	var out []Violation
+	for _, v := range violations {
+		if v.Severity == Error {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Format lists violations one per line, for logs and test failures.
+func Format(violations []Violation) string {
+	lines := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = v.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// index builds the document index in one pass.
+func index(node mi.Node) *Document {
+	doc := &Document{Root: node, ids: make(map[string]*mi.Element), labels: make(map[string]bool)}
+	walk(node, nil, func(el *mi.Element, _ []*mi.Element) {
+		doc.elements = append(doc.elements, el)
+		if id, ok := el.GetAttribute("id"); ok && id != "" {
+			if _, seen := doc.ids[id]; !seen {
+				doc.ids[id] = el
+			}
+		}
+		if el.Tag == "label" {
+			if id, ok := el.GetAttribute("for"); ok {
+				doc.labels[id] = true
+			}
+		}
+		if el.Tag == "html" {
+			doc.complete = true
+		}
+	})
+	return doc
+}
+
+// walk visits the elements under node in document order.
+func walk(node mi.Node, ancestors []*mi.Element, fn func(*mi.Element, []*mi.Element)) {
+	switch n := node.(type) {
+	case *mi.Element:
+		fn(n, ancestors)
+		ancestors = append(ancestors, n)
+		for _, child := range n.Children {
+			walk(child, ancestors, fn)
+		}
+	case *mi.Fragment:
+		for _, child := range n.Children {
+			walk(child, ancestors, fn)
+		}
+	}
+}
+
+// path describes a chain of elements as "tag#id > tag.class > tag",
+// leaving out the document-level html and body elements.
+func path(chain []*mi.Element) string {
+	var parts []string
+	for _, el := range chain {
+		if (el.Tag == "html" || el.Tag == "body") && len(chain) > 1 {
+			continue
+		}
+		part := el.Tag
+		if id, ok := el.GetAttribute("id"); ok && id != "" {
+			part += "#" + id
+		} else if class, ok := el.GetAttribute("class"); ok {
+			if fields := strings.Fields(class); len(fields) > 0 {
+				part += "." + fields[0]
+			}
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " > ")
+}
+
+// quote shortens and quotes a value for a message.
+func quote(s string) string {
+	if len(s) > 40 {
+		s = s[:37] + "..."
+	}
+	return fmt.Sprintf("%q", s)
+}
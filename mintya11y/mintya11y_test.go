@@ -0,0 +1,162 @@
+package mintya11y
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mi "github.com/ha1tch/minty"
+)
+
+func rules(violations []Violation) []string {
+	var out []string
+	for _, v := range violations {
+		out = append(out, v.Rule+" "+v.Path)
+	}
+	return out
+}
+
+func TestCheckFindsViolations(t *testing.T) {
+	page := mi.Document("", nil, mi.B.Body(
+		mi.B.Header(mi.B.A(mi.Href("/"), mi.B.Img(mi.Src("/logo.svg")))),
+		mi.B.Main(mi.ID("content"),
+			mi.B.H1("Orders"),
+			mi.B.H3("Recent"),
+			mi.B.Form(mi.ID("search"),
+				mi.B.Input(mi.Name("q"), mi.Placeholder("Search")),
+				mi.B.Select(mi.Name("status"), mi.AriaLabelledby("status-label")),
+				mi.B.Button(mi.Type("submit"), mi.B.Svg(mi.AriaHidden(true))),
+			),
+			mi.B.Div(mi.Attr("role", "tablist"),
+				mi.B.Button(mi.Attr("role", "tab"), "Open"),
+			),
+			mi.B.Svg(mi.Attr("role", "img")),
+			mi.B.Iframe(mi.Src("/map")),
+		),
+	))(mi.B)
+
+	got := rules(Check(page))
+	want := []string{
+		"document-title html",
+		"link-name header > a",
+		"image-alt header > a > img",
+		"heading-order main#content > h3",
+		"label main#content > form#search > input",
+		"label main#content > form#search > select",
+		"idref main#content > form#search > select",
+		"button-name main#content > form#search > button",
+		"tab-controls main#content > div > button",
+		"svg-img-alt main#content > svg",
+		"frame-title main#content > iframe",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("violations:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	violations, err := CheckHTML("<html><head><title>Orders</title></head><body></body></html>")
+	if err != nil || len(violations) != 1 || violations[0].Rule != RuleHTMLLang {
+		t.Errorf("expected html-has-lang, got %v (%v)", violations, err)
+	}
+}
+
+func TestCheckAcceptsAccessibleMarkup(t *testing.T) {
+	html := `<html lang="en"><head><title>Orders</title></head><body>
+		<a href="/"><img src="/logo.svg" alt="Minty"></a>
+		<img src="/divider.png" alt="">
+		<h1>Orders</h1><h2>Recent</h2><h3>Today</h3><h2>Older</h2>
+		<label>Search <input name="q"></label>
+		<label for="status">Status</label><select id="status" name="status"></select>
+		<input type="hidden" name="csrf" value="x">
+		<input type="submit">
+		<button aria-label="Close"><svg aria-hidden="true"></svg></button>
+		<div role="tablist"><button role="tab" id="t1" aria-controls="p1">Open</button></div>
+		<div role="tabpanel" id="p1" aria-labelledby="t1"></div>
+		<svg role="img"><title>Sales</title></svg>
+		<iframe src="/map" title="Store map"></iframe>
+	</body></html>`
+	violations, err := CheckHTML(html)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) > 0 {
+		t.Errorf("unexpected violations:\n%s", Format(violations))
+	}
+}
+
+func TestRulesAreConfigurable(t *testing.T) {
+	fragment := mi.B.Div(mi.B.H2("A"), mi.B.H4("B"), mi.B.Img(), mi.B.Label(mi.For("elsewhere"), "x"))
+
+	got := Check(fragment)
+	if len(got) != 2 || got[0].Rule != RuleHeadingOrder || got[0].Severity != Warning {
+		t.Fatalf("got %v", got)
+	}
+	if got[0].String() != "warning: div > h4: heading level jumps from h2 to h4 [heading-order, WCAG 1.3.1]" {
+		t.Errorf("String() = %s", got[0])
+	}
+	if len(Errors(got)) != 1 {
+		t.Errorf("Errors should drop warnings: %v", Errors(got))
+	}
+
+	if got := Check(fragment, Disable(RuleHeadingOrder), WithSeverity(Warning, RuleImageAlt)); len(got) != 1 || got[0].Severity != Warning {
+		t.Errorf("got %v", got)
+	}
+
+	noDivs := Rule{ID: "no-div", Check: func(t *Target) string {
+		if t.Element.Tag == "div" {
+			return "div used"
+		}
+		return ""
+	}}
+	if got := rules(Check(fragment, WithRules(noDivs), Only("no-div"))); len(got) != 1 || got[0] != "no-div div" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestRawContentNamesByTextOnly(t *testing.T) {
+	fragment := mi.B.Div(
+		mi.B.Button(mi.Raw(`<svg><path d="M0 0"></path></svg>`)),
+		mi.B.A(mi.Href("/"), mi.Raw(`<img src="/logo.svg">`)),
+		mi.B.A(mi.Href("/cart"), mi.Raw(`<span class="icon"></span> Cart &amp; checkout`)),
+		mi.B.Button(mi.Raw(`<img src="/x.svg" alt="Close">`)),
+	)
+	got := rules(Check(fragment))
+	want := []string{
+		"button-name div > button",
+		"link-name div > a",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("violations:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestMiddlewareReportsHTMLPages(t *testing.T) {
+	var reported []Violation
+	mux := http.NewServeMux()
+	mux.Handle("/page", mi.RenderHandler(func(b *mi.Builder) mi.Node {
+		return b.Main(b.Img(mi.Src("/x.png")))
+	}))
+	mux.HandleFunc("/data", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"img": "<img>"}`)
+	})
+	handler := Middleware(mux, ReportTo(func(r *http.Request, v []Violation) {
+		reported = append(reported, v...)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/page", nil))
+	if rec.Body.String() != `<main><img src="/x.png" /></main>` {
+		t.Errorf("response should pass through, got %q", rec.Body.String())
+	}
+	if len(reported) != 1 || reported[0].Rule != RuleImageAlt {
+		t.Errorf("reported %v", reported)
+	}
+
+	reported = nil
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/data", nil))
+	if len(reported) != 0 {
+		t.Errorf("non-HTML responses should be skipped, got %v", reported)
+	}
+}
@@ -0,0 +1,362 @@
+package mintya11y
+
+import (
+	"strconv"
+	"strings"
+
+	mi "github.com/ha1tch/minty"
+)
+
+// Rule IDs. They follow the names used by common accessibility checkers
+// where one exists.
+const (
+	RuleImageAlt      = "image-alt"
+	RuleSVGImageAlt   = "svg-img-alt"
+	RuleLabel         = "label"
+	RuleButtonName    = "button-name"
+	RuleLinkName      = "link-name"
+	RuleFrameTitle    = "frame-title"
+	RuleHeadingOrder  = "heading-order"
+	RuleTabControls   = "tab-controls"
+	RuleIDReference   = "idref"
+	RuleHTMLLang      = "html-has-lang"
+	RuleDocumentTitle = "document-title"
+)
+
+// DefaultRules returns the built-in rules.
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			ID: RuleImageAlt, WCAG: "1.1.1",
+			Description: "Images have alternative text",
+			Check:       checkImageAlt,
+		},
+		{
+			ID: RuleSVGImageAlt, WCAG: "1.1.1",
+			Description: "SVG images with role=img have an accessible name",
+			Check:       checkSVGImageAlt,
+		},
+		{
+			ID: RuleLabel, WCAG: "4.1.2",
+			Description: "Form controls have a label",
+			Check:       checkLabel,
+		},
+		{
+			ID: RuleButtonName, WCAG: "4.1.2",
+			Description: "Buttons have an accessible name",
+			Check:       checkButtonName,
+		},
+		{
+			ID: RuleLinkName, WCAG: "2.4.4",
+			Description: "Links have an accessible name",
+			Check:       checkLinkName,
+		},
+		{
+			ID: RuleFrameTitle, WCAG: "4.1.2",
+			Description: "Frames have a title",
+			Check:       checkFrameTitle,
+		},
+		{
+			ID: RuleHeadingOrder, WCAG: "1.3.1", Severity: Warning,
+			Description: "Heading levels increase by one",
+			Check:       checkHeadingOrder,
+		},
+		{
+			ID: RuleTabControls, WCAG: "4.1.2",
+			Description: "Tabs name the panel they control with aria-controls",
+			Check:       checkTabControls,
+		},
+		{
+			ID: RuleIDReference, WCAG: "1.3.1",
+			Description: "ARIA and label references point to existing ids",
+			Check:       checkIDReferences,
+		},
+		{
+			ID: RuleHTMLLang, WCAG: "3.1.1",
+			Description: "The html element has a lang attribute",
+			Check:       checkHTMLLang,
+		},
+		{
+			ID: RuleDocumentTitle, WCAG: "2.4.2",
+			Description: "Pages have a non-empty title",
+			Check:       checkDocumentTitle,
+		},
+	}
+}
+
+func checkImageAlt(t *Target) string {
+	el := t.Element
+	switch {
+	case el.Tag == "img":
+		if _, ok := t.Attr("alt"); ok || presentational(el) {
+			return ""
+		}
+		return `image has no alt attribute; use alt="" if it is decorative`
+	case el.Tag == "input" && inputType(el) == "image":
+		if accessibleName(t.Document, el) == "" && strings.TrimSpace(attr(el, "alt")) == "" {
+			return "image button has no alt text"
+		}
+	}
+	return ""
+}
+
+func checkSVGImageAlt(t *Target) string {
+	if t.Element.Tag != "svg" || attr(t.Element, "role") != "img" {
+		return ""
+	}
+	if accessibleName(t.Document, t.Element) != "" {
+		return ""
+	}
+	for _, child := range t.Element.Children {
+		if el, ok := child.(*mi.Element); ok && el.Tag == "title" && strings.TrimSpace(mi.TextContent(el)) != "" {
+			return ""
+		}
+	}
+	return "svg with role=img has no <title>, aria-label or aria-labelledby"
+}
+
+func checkLabel(t *Target) string {
+	el := t.Element
+	switch el.Tag {
+	case "input":
+		switch inputType(el) {
+		case "hidden", "submit", "reset", "button", "image":
+			return ""
+		}
+	case "select", "textarea":
+	default:
+		return ""
+	}
+	if labelledBy(t.Document, el) != "" || strings.TrimSpace(attr(el, "aria-label")) != "" ||
+		strings.TrimSpace(attr(el, "title")) != "" {
+		return ""
+	}
+	if id := attr(el, "id"); id != "" && t.Document.HasLabelFor(id) {
+		return ""
+	}
+	for _, a := range t.Ancestors {
+		if a.Tag == "label" {
+			return ""
+		}
+	}
+	return "form control " + describe(el) + " has no label"
+}
+
+func checkButtonName(t *Target) string {
+	el := t.Element
+	switch {
+	case el.Tag == "button", attr(el, "role") == "button" && el.Tag != "input":
+	case el.Tag == "input" && inputType(el) == "button":
+		if strings.TrimSpace(attr(el, "value")) != "" {
+			return ""
+		}
+	default:
+		return ""
+	}
+	if accessibleName(t.Document, el) == "" {
+		return "button has no text, aria-label or aria-labelledby"
+	}
+	return ""
+}
+
+func checkLinkName(t *Target) string {
+	if t.Element.Tag != "a" {
+		return ""
+	}
+	if _, ok := t.Attr("href"); !ok {
+		return ""
+	}
+	if accessibleName(t.Document, t.Element) == "" {
+		return "link has no text, aria-label or aria-labelledby"
+	}
+	return ""
+}
+
+func checkFrameTitle(t *Target) string {
+	if t.Element.Tag != "iframe" || presentational(t.Element) || attr(t.Element, "aria-hidden") == "true" {
+		return ""
+	}
+	if strings.TrimSpace(attr(t.Element, "title")) == "" && accessibleName(t.Document, t.Element) == "" {
+		return "iframe has no title"
+	}
+	return ""
+}
+
+func checkHeadingOrder(t *Target) string {
+	level := headingLevel(t.Element)
+	if level == 0 {
+		return ""
+	}
+	previous := 0
+	t.Previous(func(el *mi.Element) bool {
+		previous = headingLevel(el)
+		return previous == 0
+	})
+	if previous > 0 && level > previous+1 {
+		return "heading level jumps from h" + strconv.Itoa(previous) + " to h" + strconv.Itoa(level)
+	}
+	return ""
+}
+
+func checkTabControls(t *Target) string {
+	if attr(t.Element, "role") != "tab" {
+		return ""
+	}
+	if strings.TrimSpace(attr(t.Element, "aria-controls")) == "" {
+		return "tab has no aria-controls naming its panel"
+	}
+	return ""
+}
+
+// idrefAttributes name other elements by id.
+var idrefAttributes = []string{"aria-labelledby", "aria-describedby", "aria-controls", "aria-owns", "aria-activedescendant"}
+
+func checkIDReferences(t *Target) string {
+	// References in a fragment may point into the page around it
+	if !t.Document.Complete() {
+		return ""
+	}
+	var missing []string
+	for _, name := range idrefAttributes {
+		for _, id := range strings.Fields(attr(t.Element, name)) {
+			if t.Document.ElementByID(id) == nil {
+				missing = append(missing, name+"="+quote(id))
+			}
+		}
+	}
+	if t.Element.Tag == "label" {
+		if id, ok := t.Attr("for"); ok && t.Document.ElementByID(id) == nil {
+			missing = append(missing, "for="+quote(id))
+		}
+	}
+	if len(missing) > 0 {
+		return "no element with the id in " + strings.Join(missing, ", ")
+	}
+	return ""
+}
+
+func checkHTMLLang(t *Target) string {
+	if t.Element.Tag == "html" && strings.TrimSpace(attr(t.Element, "lang")) == "" {
+		return "html element has no lang attribute"
+	}
+	return ""
+}
+
+func checkDocumentTitle(t *Target) string {
+	if t.Element.Tag != "html" {
+		return ""
+	}
+	title := mi.FindFirst(t.Element, "head > title")
+	if title == nil || strings.TrimSpace(mi.TextContent(title)) == "" {
+		return "page has no <title>"
+	}
+	return ""
+}
+
+// accessibleName approximates the accessible name of an element from
+// aria-labelledby, aria-label and its content, in that order.
+func accessibleName(doc *Document, el *mi.Element) string {
+	if name := labelledBy(doc, el); name != "" {
+		return name
+	}
+	if name := strings.TrimSpace(attr(el, "aria-label")); name != "" {
+		return name
+	}
+	if name := strings.TrimSpace(contentText(el)); name != "" {
+		return name
+	}
+	return strings.TrimSpace(attr(el, "title"))
+}
+
+// labelledBy returns the text of the elements named by aria-labelledby.
+func labelledBy(doc *Document, el *mi.Element) string {
+	var parts []string
+	for _, id := range strings.Fields(attr(el, "aria-labelledby")) {
+		if ref := doc.ElementByID(id); ref != nil {
+			parts = append(parts, contentText(ref))
+		}
+	}
+	return strings.TrimSpace(strings.Join(parts, " "))
+}
+
+// contentText returns the text an element contributes to a name: its
+// text, the alt text of images and the labels of nested elements,
+// skipping subtrees hidden with aria-hidden, scripts and styles. Raw
+// markup is parsed so that only its text counts, not its tags.
+func contentText(node mi.Node) string {
+	var sb strings.Builder
+	var visit func(mi.Node)
+	visit = func(n mi.Node) {
+		switch n := n.(type) {
+		case *mi.TextNode:
+			sb.WriteString(n.Content)
+		case *mi.RawNode:
+			if root, err := mi.ParseHTML(n.Content); err == nil {
+				visit(root)
+			}
+		case *mi.Fragment:
+			for _, child := range n.Children {
+				visit(child)
+			}
+		case *mi.Element:
+			if attr(n, "aria-hidden") == "true" || n.Tag == "script" || n.Tag == "style" {
+				return
+			}
+			if label := attr(n, "aria-label"); label != "" {
+				sb.WriteString(" " + label + " ")
+				return
+			}
+			if n.Tag == "img" || (n.Tag == "input" && inputType(n) == "image") {
+				sb.WriteString(" " + attr(n, "alt") + " ")
+				return
+			}
+			for _, child := range n.Children {
+				visit(child)
+			}
+		}
+	}
+	visit(node)
+	return sb.String()
+}
+
+// headingLevel returns 1-6 for headings and 0 for other elements.
+func headingLevel(el *mi.Element) int {
+	if len(el.Tag) == 2 && el.Tag[0] == 'h' && el.Tag[1] >= '1' && el.Tag[1] <= '6' {
+		return int(el.Tag[1] - '0')
+	}
+	if attr(el, "role") == "heading" {
+		if level, err := strconv.Atoi(attr(el, "aria-level")); err == nil && level > 0 {
+			return level
+		}
+		return 2 // the default aria-level of role=heading
+	}
+	return 0
+}
+
+func presentational(el *mi.Element) bool {
+	role := attr(el, "role")
+	return role == "presentation" || role == "none"
+}
+
+func inputType(el *mi.Element) string {
+	if t := strings.ToLower(attr(el, "type")); t != "" {
+		return t
+	}
+	return "text"
+}
+
+func attr(el *mi.Element, name string) string {
+	value, _ := el.GetAttribute(name)
+	return value
+}
+
+// describe names a form control for a message, e.g. <input name="email">.
+func describe(el *mi.Element) string {
+	s := "<" + el.Tag
+	if name := attr(el, "name"); name != "" {
+		s += " name=" + quote(name)
+	} else if el.Tag == "input" {
+		s += " type=" + quote(inputType(el))
+	}
+	return s + ">"
+}
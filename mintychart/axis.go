@@ -0,0 +1,169 @@
+package mintychart
+
+import (
+	"math"
+
+	mi "github.com/ha1tch/minty"
+	msvg "github.com/ha1tch/minty/mintysvg"
+)
+
+// scale maps values to y coordinates over a rounded domain.
+type scale struct {
+	lo, hi, step float64
+	top, bottom  float64
+}
+
+// niceScale extends [min, max] to round numbers with about ticks steps.
+func niceScale(min, max float64, ticks int) (lo, hi, step float64) {
+	if min == max {
+		switch {
+		case max == 0:
+			max = 1
+		case max > 0:
+			min = 0
+		default:
+			max = 0
+		}
+	}
+	step = niceNum((max - min) / float64(ticks-1))
+	lo = math.Floor(min/step) * step
+	hi = math.Ceil(max/step) * step
+	return lo, hi, step
+}
+
+// niceNum returns a 1, 2 or 5 multiple of a power of ten close to x.
+func niceNum(x float64) float64 {
+	exp := math.Floor(math.Log10(x))
+	f := x / math.Pow(10, exp)
+	var nf float64
+	switch {
+	case f < 1.5:
+		nf = 1
+	case f < 3:
+		nf = 2
+	case f < 7:
+		nf = 5
+	default:
+		nf = 10
+	}
+	return nf * math.Pow(10, exp)
+}
+
+func (s scale) y(v float64) float64 {
+	return s.bottom - (v-s.lo)/(s.hi-s.lo)*(s.bottom-s.top)
+}
+
+// ticks returns the tick values from lo to hi.
+func (s scale) ticks() []float64 {
+	var out []float64
+	n := int(math.Round((s.hi - s.lo) / s.step))
+	// Round to the step's precision so 0.1 steps do not print as 0.30000000000000004
+	precision := math.Pow(10, math.Max(0, -math.Floor(math.Log10(s.step))))
+	for i := 0; i <= n; i++ {
+		out = append(out, math.Round((s.lo+float64(i)*s.step)*precision)/precision)
+	}
+	return out
+}
+
+// cartesian is the layout of a chart with a y axis and labelled slots
+// along x.
+type cartesian struct {
+	o                        *Options
+	labels                   []string
+	scale                    scale
+	left, right, top, bottom float64
+	legend                   [][]legendItem
+}
+
+// newCartesian lays out the plot area for values in [min, max].
+func newCartesian(o *Options, labels []string, min, max float64, legendItems []legendItem) *cartesian {
+	c := &cartesian{o: o, labels: labels}
+	lo, hi, step := niceScale(min, max, o.Ticks)
+	c.scale = scale{lo: lo, hi: hi, step: step}
+
+	widest := 0
+	for _, t := range c.scale.ticks() {
+		if n := len(o.Format(t)); n > widest {
+			widest = n
+		}
+	}
+	c.left = math.Max(28, float64(widest)*charWidth+12)
+	c.right = o.Width - 12
+	c.top = 12
+	c.bottom = o.Height - 24
+
+	if !o.HideLegend && len(legendItems) > 1 {
+		c.legend = legendRows(legendItems, o.Width-24)
+		c.bottom -= float64(len(c.legend))*legendRowHeight + 4
+	}
+	c.scale.top, c.scale.bottom = c.top, c.bottom
+	return c
+}
+
+// slot returns the x range of label i.
+func (c *cartesian) slot(i int) (x, width float64) {
+	width = (c.right - c.left) / float64(len(c.labels))
+	return c.left + float64(i)*width, width
+}
+
+// axes renders gridlines, y tick labels, the zero line and x labels.
+func (c *cartesian) axes() mi.Node {
+	nodes := []interface{}{mi.Class("minty-chart-axes")}
+	for _, t := range c.scale.ticks() {
+		y := round(c.scale.y(t))
+		opacity := 0.15
+		if t == 0 {
+			opacity = 0.4
+		}
+		nodes = append(nodes,
+			msvg.Line(msvg.X1(round(c.left)), msvg.X2(round(c.right)), msvg.Y1(y), msvg.Y2(y),
+				msvg.Stroke("currentColor"), msvg.StrokeOpacity(opacity), msvg.ShapeRendering("crispEdges")),
+			text(c.left-6, y+4, "end", c.o.Format(t), msvg.FillOpacity(0.7)),
+		)
+	}
+	for i, label := range c.labels {
+		x, w := c.slot(i)
+		nodes = append(nodes, text(x+w/2, c.bottom+16, "middle", label, msvg.FillOpacity(0.7)))
+	}
+	return msvg.G(nodes...)
+}
+
+// legendNode renders the legend below the x labels, if any.
+func (c *cartesian) legendNode() mi.Node {
+	if len(c.legend) == 0 {
+		return nil
+	}
+	return legend(c.legend, c.o.Width, c.bottom+24+legendRowHeight)
+}
+
+// seriesLegend returns legend items for named series.
+func seriesLegend(o *Options, series []Series) []legendItem {
+	items := make([]legendItem, 0, len(series))
+	for i, s := range series {
+		if s.Name != "" {
+			items = append(items, legendItem{label: s.Name, color: o.color(i, s.Color), class: i})
+		}
+	}
+	return items
+}
+
+// valueAt returns series value i, or NaN when missing.
+func valueAt(s Series, i int) float64 {
+	if i < len(s.Values) {
+		return s.Values[i]
+	}
+	return math.NaN()
+}
+
+// extent returns the range of all values, ignoring NaN.
+func extent(series []Series, n int) (min, max float64, ok bool) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		for i := 0; i < n; i++ {
+			if v := valueAt(s, i); !math.IsNaN(v) {
+				min, max, ok = math.Min(min, v), math.Max(max, v), true
+			}
+		}
+	}
+	return min, max, ok
+}
@@ -0,0 +1,97 @@
+package mintychart
+
+import (
+	"math"
+
+	mi "github.com/ha1tch/minty"
+	msvg "github.com/ha1tch/minty/mintysvg"
+)
+
+// Bar renders a grouped bar chart: one group per label, one bar per
+// series. Negative values hang below the zero line.
+func Bar(labels []string, series []Series, opts ...Option) mi.H {
+	o := newOptions(600, 300, "Bar chart", opts)
+	min, max, ok := extent(series, len(labels))
+	if !ok {
+		return o.noData("bar")
+	}
+	return func(b *mi.Builder) mi.Node {
+		c := newCartesian(o, labels, math.Min(min, 0), math.Max(max, 0), seriesLegend(o, series))
+		zero := c.scale.y(0)
+
+		bars := []interface{}{mi.Class("minty-chart-bars")}
+		for i, label := range labels {
+			x, w := c.slot(i)
+			inner := w * 0.7
+			barWidth := inner / float64(len(series))
+			for j, s := range series {
+				v := valueAt(s, i)
+				if math.IsNaN(v) {
+					continue
+				}
+				y := c.scale.y(v)
+				bars = append(bars, msvg.Rect(
+					msvg.X(round(x+(w-inner)/2+float64(j)*barWidth)), msvg.Y(round(math.Min(y, zero))),
+					msvg.Width(round(math.Max(barWidth-1, 1))), msvg.Height(round(math.Abs(zero-y))),
+					msvg.Fill(o.color(j, s.Color)), seriesClass(j),
+					tooltip(s.Name, label, o.Format(v)),
+				))
+			}
+		}
+		return o.svg("bar", c.axes(), msvg.G(bars...), c.legendNode())
+	}
+}
+
+// StackedBar renders one bar per label with the series stacked on top of
+// each other. Positive and negative values stack away from zero
+// separately.
+func StackedBar(labels []string, series []Series, opts ...Option) mi.H {
+	o := newOptions(600, 300, "Stacked bar chart", opts)
+	if _, _, ok := extent(series, len(labels)); !ok {
+		return o.noData("stacked-bar")
+	}
+
+	// The domain is the largest stack in either direction
+	min, max := 0.0, 0.0
+	for i := range labels {
+		pos, neg := 0.0, 0.0
+		for _, s := range series {
+			if v := valueAt(s, i); v > 0 {
+				pos += v
+			} else if v < 0 {
+				neg += v
+			}
+		}
+		min, max = math.Min(min, neg), math.Max(max, pos)
+	}
+
+	return func(b *mi.Builder) mi.Node {
+		c := newCartesian(o, labels, min, max, seriesLegend(o, series))
+
+		bars := []interface{}{mi.Class("minty-chart-bars")}
+		for i, label := range labels {
+			x, w := c.slot(i)
+			barWidth := w * 0.6
+			pos, neg := 0.0, 0.0
+			for j, s := range series {
+				v := valueAt(s, i)
+				if math.IsNaN(v) || v == 0 {
+					continue
+				}
+				base := &pos
+				if v < 0 {
+					base = &neg
+				}
+				y0, y1 := c.scale.y(*base), c.scale.y(*base+v)
+				*base += v
+				bars = append(bars, msvg.Rect(
+					msvg.X(round(x+(w-barWidth)/2)), msvg.Y(round(math.Min(y0, y1))),
+					msvg.Width(round(barWidth)), msvg.Height(round(math.Abs(y1-y0))),
+					msvg.Fill(o.color(j, s.Color)), seriesClass(j),
+					tooltip(s.Name, label, o.Format(v)),
+				))
+			}
+		}
+		return o.svg("stacked-bar", c.axes(), msvg.G(bars...), c.legendNode())
+	}
+}
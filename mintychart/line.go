@@ -0,0 +1,146 @@
+package mintychart
+
+import (
+	"math"
+
+	mi "github.com/ha1tch/minty"
+	msvg "github.com/ha1tch/minty/mintysvg"
+)
+
+// Line renders one line per series with a point at each label. Missing
+// values (NaN or a short Values slice) leave a gap in the line.
+func Line(labels []string, series []Series, opts ...Option) mi.H {
+	o := newOptions(600, 300, "Line chart", opts)
+	min, max, ok := extent(series, len(labels))
+	if !ok {
+		return o.noData("line")
+	}
+	return func(b *mi.Builder) mi.Node {
+		c := newCartesian(o, labels, min, max, seriesLegend(o, series))
+		return o.svg("line", c.axes(), c.lines(series, false), c.legendNode())
+	}
+}
+
+// Area renders a line chart with the area between each line and zero
+// filled in.
+func Area(labels []string, series []Series, opts ...Option) mi.H {
+	o := newOptions(600, 300, "Area chart", opts)
+	min, max, ok := extent(series, len(labels))
+	if !ok {
+		return o.noData("area")
+	}
+	return func(b *mi.Builder) mi.Node {
+		c := newCartesian(o, labels, math.Min(min, 0), math.Max(max, 0), seriesLegend(o, series))
+		return o.svg("area", c.axes(), c.lines(series, true), c.legendNode())
+	}
+}
+
+// lines renders each series as a path with points, optionally filled
+// down to the zero line.
+func (c *cartesian) lines(series []Series, fill bool) mi.Node {
+	zero := c.scale.y(math.Max(c.scale.lo, math.Min(0, c.scale.hi)))
+	groups := []interface{}{mi.Class("minty-chart-lines")}
+	for j, s := range series {
+		color := c.o.color(j, s.Color)
+		var line, area msvg.PathData
+		var drawn bool
+		var points []interface{}
+		var run []float64 // x, y pairs of the current unbroken run
+
+		flush := func() {
+			if fill && len(run) > 0 {
+				area = area.MoveTo(run[0], zero)
+				for k := 0; k < len(run); k += 2 {
+					area = area.LineTo(run[k], run[k+1])
+				}
+				area = area.LineTo(run[len(run)-2], zero).Close()
+			}
+			run = nil
+		}
+
+		for i, label := range c.labels {
+			v := valueAt(s, i)
+			if math.IsNaN(v) {
+				flush()
+				continue
+			}
+			x, w := c.slot(i)
+			px, py := round(x+w/2), round(c.scale.y(v))
+			if len(run) == 0 {
+				line = line.MoveTo(px, py)
+			} else {
+				line = line.LineTo(px, py)
+			}
+			run = append(run, px, py)
+			drawn = true
+			points = append(points, msvg.Circle(msvg.Cx(px), msvg.Cy(py), msvg.R(3),
+				msvg.Fill(color), tooltip(s.Name, label, c.o.Format(v))))
+		}
+		flush()
+		if !drawn {
+			continue
+		}
+
+		group := []interface{}{seriesClass(j)}
+		if fill {
+			group = append(group, msvg.Path(area, msvg.Fill(color), msvg.FillOpacity(0.2)))
+		}
+		group = append(group,
+			msvg.Path(line, msvg.Fill("none"), msvg.Stroke(color), msvg.StrokeWidth(2),
+				msvg.StrokeLinejoin("round"), msvg.StrokeLinecap("round")),
+		)
+		groups = append(groups, msvg.G(append(group, points...)...))
+	}
+	return msvg.G(groups...)
+}
+
+// Sparkline renders a small axis-free line for use inline with text or in
+// table cells. Without a Title it is marked decorative; give it one when
+// it carries information not repeated nearby.
+func Sparkline(values []float64, opts ...Option) mi.H {
+	o := newOptions(100, 24, "", opts)
+	series := Series{Values: values}
+	min, max, ok := extent([]Series{series}, len(values))
+	if !ok {
+		return o.noData("sparkline")
+	}
+	if min == max {
+		min, max = min-1, max+1
+	}
+	return func(b *mi.Builder) mi.Node {
+		const pad = 3 // room for the end dot
+		y := func(v float64) float64 {
+			return round(o.Height - pad - (v-min)/(max-min)*(o.Height-2*pad))
+		}
+		x := func(i int) float64 {
+			if len(values) == 1 {
+				return round(o.Width / 2)
+			}
+			return round(pad + float64(i)/float64(len(values)-1)*(o.Width-2*pad))
+		}
+
+		var line msvg.PathData
+		var pen bool
+		lastX, lastY := math.NaN(), math.NaN()
+		for i, v := range values {
+			if math.IsNaN(v) {
+				pen = false
+				continue
+			}
+			if pen {
+				line = line.LineTo(x(i), y(v))
+			} else {
+				line = line.MoveTo(x(i), y(v))
+			}
+			pen = true
+			lastX, lastY = x(i), y(v)
+		}
+
+		color := o.color(0, "")
+		return o.svg("sparkline",
+			msvg.Path(line, msvg.Fill("none"), msvg.Stroke(color), msvg.StrokeWidth(1.5),
+				msvg.StrokeLinejoin("round"), msvg.StrokeLinecap("round")),
+			msvg.Circle(msvg.Cx(lastX), msvg.Cy(lastY), msvg.R(2), msvg.Fill(color)),
+		)
+	}
+}
@@ -0,0 +1,349 @@
+// Package mintychart renders charts as static SVG with mintysvg.
+//
+// Charts are computed on the server from Go data and need no JavaScript,
+// so they work in full pages, htmx fragments and HTML email alike:
+//
+//	mchart.Bar([]string{"Q1", "Q2", "Q3"}, []mchart.Series{
+//	    {Name: "2024", Values: []float64{120, 180, 150}},
+//	    {Name: "2025", Values: []float64{140, 210, 190}},
+//	}, mchart.Title("Revenue by quarter"), mchart.ForTheme(theme))
+//
+// Every chart is an <svg role="img"> with <title> and <desc> for assistive
+// technology, and each bar, point and slice carries its own <title> that
+// browsers show as a tooltip. Text and axes use currentColor, so charts
+// follow the surrounding text colour in light and dark mode. Series
+// colours come from a palette (see ForTheme and Palette); each series
+// also gets a minty-chart-sN class so stylesheets can override them.
+//
+// Import with: import mchart "github.com/ha1tch/minty/mintychart"
+package mintychart
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	mi "github.com/ha1tch/minty"
+	msvg "github.com/ha1tch/minty/mintysvg"
+	mui "github.com/ha1tch/minty/mintyui"
+)
+
+// Series is a named set of values, one per label.
+type Series struct {
+	Name   string
+	Values []float64
+	Color  string // overrides the palette colour
+}
+
+// Slice is one segment of a pie or donut chart.
+type Slice struct {
+	Label string
+	Value float64
+	Color string // overrides the palette colour
+}
+
+// Options configures a chart.
+type Options struct {
+	Title       string // accessible name, also the <title> element
+	Description string // longer accessible description, the <desc> element
+	ID          string // id of the <svg>; enables aria-labelledby
+	Class       string // extra CSS classes on the <svg>
+	Width       float64
+	Height      float64
+	Palette     []string
+	Format      func(float64) string // value labels; defaults to Compact
+	Ticks       int                  // approximate number of y-axis ticks
+	HideLegend  bool
+	CenterLabel string // text in the middle of a donut
+}
+
+// Option configures Options.
+type Option func(*Options)
+
+// Title sets the accessible title of the chart.
+func Title(title string) Option {
+	return func(o *Options) {
+		o.Title = title
+	}
+}
+
+// Description sets the accessible description of the chart.
+func Description(desc string) Option {
+	return func(o *Options) {
+		o.Description = desc
+	}
+}
+
+// ID sets the id of the chart's <svg>, which also ties the title and
+// description to it with aria-labelledby and aria-describedby.
+func ID(id string) Option {
+	return func(o *Options) {
+		o.ID = id
+	}
+}
+
+// Class adds CSS classes to the chart's <svg>.
+func Class(class string) Option {
+	return func(o *Options) {
+		o.Class = class
+	}
+}
+
+// Size sets the chart size in pixels. The SVG scales down to fit narrower
+// containers.
+func Size(width, height float64) Option {
+	return func(o *Options) {
+		o.Width = width
+		o.Height = height
+	}
+}
+
+// Palette sets the series colours, used in order and repeated as needed.
+func Palette(colors ...string) Option {
+	return func(o *Options) {
+		o.Palette = colors
+	}
+}
+
+// ForTheme uses the palette matching a mintyui theme.
+func ForTheme(theme mui.Theme) Option {
+	return func(o *Options) {
+		if theme != nil {
+			o.Palette = ThemePalette(theme.GetName())
+		}
+	}
+}
+
+// Format sets how values are written on axes and in tooltips.
+func Format(format func(float64) string) Option {
+	return func(o *Options) {
+		o.Format = format
+	}
+}
+
+// Ticks sets the approximate number of y-axis ticks.
+func Ticks(n int) Option {
+	return func(o *Options) {
+		o.Ticks = n
+	}
+}
+
+// HideLegend omits the legend.
+func HideLegend() Option {
+	return func(o *Options) {
+		o.HideLegend = true
+	}
+}
+
+// CenterLabel sets the text shown in the hole of a donut chart.
+func CenterLabel(label string) Option {
+	return func(o *Options) {
+		o.CenterLabel = label
+	}
+}
+
+// DefaultPalette is used when no palette or theme is given.
+var DefaultPalette = []string{
+	"#4f46e5", "#0ea5e9", "#10b981", "#f59e0b", "#ef4444", "#8b5cf6", "#ec4899", "#14b8a6",
+}
+
+// themePalettes follow each theme's own colour names.
+var themePalettes = map[string][]string{
+	"Bootstrap": {"#0d6efd", "#198754", "#ffc107", "#dc3545", "#0dcaf0", "#6f42c1", "#fd7e14", "#20c997"},
+	"Bulma":     {"#485fc7", "#00d1b2", "#3e8ed0", "#48c78e", "#ffb70f", "#f14668", "#9b59b6", "#ff7f50"},
+	"Material":  {"#6200ee", "#03dac6", "#2196f3", "#4caf50", "#ff9800", "#b00020", "#9c27b0", "#009688"},
+	"Tailwind":  DefaultPalette,
+}
+
+// ThemePalette returns the palette for a theme name as reported by
+// Theme.GetName, or DefaultPalette for unknown themes.
+func ThemePalette(name string) []string {
+	if p, ok := themePalettes[name]; ok {
+		return p
+	}
+	return DefaultPalette
+}
+
+func newOptions(width, height float64, defaultTitle string, opts []Option) *Options {
+	o := &Options{Width: width, Height: height, Ticks: 5, Title: defaultTitle}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if len(o.Palette) == 0 {
+		o.Palette = DefaultPalette
+	}
+	if o.Format == nil {
+		o.Format = Compact
+	}
+	if o.Ticks < 2 {
+		o.Ticks = 2
+	}
+	return o
+}
+
+// color returns the colour of series i.
+func (o *Options) color(i int, override string) string {
+	if override != "" {
+		return override
+	}
+	return o.Palette[i%len(o.Palette)]
+}
+
+// Compact formats values with k, M and B suffixes and at most two
+// decimals: 950, 1.5k, 2.25M.
+func Compact(v float64) string {
+	a := math.Abs(v)
+	switch {
+	case a >= 1e9:
+		return trimmed(v/1e9) + "B"
+	case a >= 1e6:
+		return trimmed(v/1e6) + "M"
+	case a >= 1e3:
+		return trimmed(v/1e3) + "k"
+	}
+	return trimmed(v)
+}
+
+func trimmed(v float64) string {
+	return strconv.FormatFloat(round(v), 'f', -1, 64)
+}
+
+// round keeps coordinates to two decimals to keep the markup small.
+func round(v float64) float64 {
+	r := math.Round(v*100) / 100
+	if r == 0 {
+		return 0 // avoid -0
+	}
+	return r
+}
+
+// svg wraps chart content in the accessible root element.
+func (o *Options) svg(kind string, children ...interface{}) mi.Node {
+	class := "minty-chart minty-chart-" + kind
+	if o.Class != "" {
+		class += " " + o.Class
+	}
+	args := []interface{}{
+		msvg.ViewBox(0, 0, o.Width, o.Height),
+		msvg.Width(round(o.Width)), msvg.Height(round(o.Height)),
+		mi.Class(class),
+		mi.Style("max-width: 100%; height: auto"),
+		msvg.FontFamily("system-ui, -apple-system, sans-serif"),
+		msvg.FontSize(11),
+	}
+	if o.Title == "" {
+		// Untitled charts, such as sparklines beside a figure, are decorative
+		args = append(args, mi.AriaHidden(true))
+	} else {
+		args = append(args, mi.Role("img"))
+		if o.ID != "" {
+			args = append(args, mi.ID(o.ID), mi.AriaLabelledby(o.ID+"-title"))
+			if o.Description != "" {
+				args = append(args, mi.AriaDescribedby(o.ID+"-desc"))
+			}
+		}
+		args = append(args, msvg.Title(o.idAttr("-title"), o.Title))
+		if o.Description != "" {
+			args = append(args, msvg.Desc(o.idAttr("-desc"), o.Description))
+		}
+	}
+	return msvg.SVG(append(args, children...)...)
+}
+
+func (o *Options) idAttr(suffix string) mi.Attribute {
+	if o.ID == "" {
+		return nil
+	}
+	return mi.ID(o.ID + suffix)
+}
+
+// text creates a label in the current text colour.
+func text(x, y float64, anchor, content string, attrs ...interface{}) mi.Node {
+	args := []interface{}{
+		msvg.X(round(x)), msvg.Y(round(y)), msvg.TextAnchor(anchor), msvg.Fill("currentColor"),
+	}
+	return msvg.Text(append(append(args, attrs...), content)...)
+}
+
+// noData renders an empty chart with a message.
+func (o *Options) noData(kind string) mi.H {
+	return func(b *mi.Builder) mi.Node {
+		return o.svg(kind, text(o.Width/2, o.Height/2, "middle", "No data", msvg.FillOpacity(0.6)))
+	}
+}
+
+// seriesClass returns the class hook for series i.
+func seriesClass(i int) mi.Attribute {
+	return mi.Class(fmt.Sprintf("minty-chart-s%d", i+1))
+}
+
+// legendItem is one swatch and label.
+type legendItem struct {
+	label string
+	color string
+	class int
+}
+
+const (
+	legendRowHeight = 18
+	charWidth       = 6.2 // average glyph width at the chart font size
+)
+
+// legendRows lays the items out in rows that fit width.
+func legendRows(items []legendItem, width float64) [][]legendItem {
+	var rows [][]legendItem
+	var row []legendItem
+	x := 0.0
+	for _, it := range items {
+		w := legendItemWidth(it)
+		if len(row) > 0 && x+w > width {
+			rows = append(rows, row)
+			row, x = nil, 0
+		}
+		row = append(row, it)
+		x += w
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func legendItemWidth(it legendItem) float64 {
+	return 10 + 6 + float64(len([]rune(it.label)))*charWidth + 16
+}
+
+// legend renders centred rows starting at y.
+func legend(rows [][]legendItem, width, y float64) mi.Node {
+	var nodes []interface{}
+	nodes = append(nodes, mi.Class("minty-chart-legend"))
+	for r, row := range rows {
+		total := 0.0
+		for _, it := range row {
+			total += legendItemWidth(it)
+		}
+		x := (width - total + 16) / 2
+		rowY := y + float64(r)*legendRowHeight
+		for _, it := range row {
+			nodes = append(nodes,
+				msvg.Rect(msvg.X(round(x)), msvg.Y(round(rowY-9)), msvg.Width(10), msvg.Height(10), msvg.Rx(2),
+					msvg.Fill(it.color), seriesClass(it.class)),
+				text(x+16, rowY, "start", it.label),
+			)
+			x += legendItemWidth(it)
+		}
+	}
+	return msvg.G(nodes...)
+}
+
+// tooltip returns the <title> shown when hovering a mark.
+func tooltip(parts ...string) mi.Node {
+	var kept []string
+	for _, p := range parts {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return msvg.Title(strings.Join(kept, ": "))
+}
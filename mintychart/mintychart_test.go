@@ -0,0 +1,243 @@
+package mintychart
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	mi "github.com/ha1tch/minty"
+	"github.com/ha1tch/minty/themes/bootstrap"
+)
+
+func build(t *testing.T, h mi.H) (mi.Node, string) {
+	t.Helper()
+	node := h(mi.B)
+	var sb strings.Builder
+	if err := node.Render(&sb); err != nil {
+		t.Fatal(err)
+	}
+	return node, sb.String()
+}
+
+func titles(node mi.Node, selector string) []string {
+	var out []string
+	for _, el := range mi.FindAll(node, selector) {
+		out = append(out, mi.TextContent(el))
+	}
+	return out
+}
+
+func TestNiceScale(t *testing.T) {
+	tests := []struct {
+		min, max     float64
+		lo, hi, step float64
+	}{
+		{0, 95, 0, 100, 20},
+		{0, 1234, 0, 1500, 500},
+		{-35, 80, -40, 80, 20},
+		{0.12, 0.87, 0, 1, 0.2},
+		{5, 5, 0, 5, 1},
+		{0, 0, 0, 1, 0.2},
+	}
+	for _, tt := range tests {
+		lo, hi, step := niceScale(tt.min, tt.max, 5)
+		if !close(lo, tt.lo) || !close(hi, tt.hi) || !close(step, tt.step) {
+			t.Errorf("niceScale(%v, %v) = %v, %v, %v; want %v, %v, %v",
+				tt.min, tt.max, lo, hi, step, tt.lo, tt.hi, tt.step)
+		}
+	}
+
+	s := scale{lo: 0.1, hi: 0.9, step: 0.2}
+	got := s.ticks()
+	want := []float64{0.1, 0.3, 0.5, 0.7, 0.9}
+	if len(got) != len(want) {
+		t.Fatalf("ticks = %v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ticks = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func close(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestCompact(t *testing.T) {
+	tests := map[float64]string{
+		0:         "0",
+		950:       "950",
+		1500:      "1.5k",
+		-2000:     "-2k",
+		2250000:   "2.25M",
+		3.14159:   "3.14",
+		7.2e9:     "7.2B",
+		-0.000001: "0",
+	}
+	for v, want := range tests {
+		if got := Compact(v); got != want {
+			t.Errorf("Compact(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestBarChart(t *testing.T) {
+	node, html := build(t, Bar([]string{"Q1", "Q2", "Q3"}, []Series{
+		{Name: "2024", Values: []float64{120, 180, 150}},
+		{Name: "2025", Values: []float64{140, -20, 190}},
+	}, Title("Revenue by quarter"), Description("Quarterly revenue in kEUR"), ID("rev")))
+
+	root := node.(*mi.Element)
+	for name, want := range map[string]string{
+		"role":             "img",
+		"aria-labelledby":  "rev-title",
+		"aria-describedby": "rev-desc",
+		"xmlns":            "http://www.w3.org/2000/svg",
+	} {
+		if got := attr(root, name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if got := titles(node, "svg > title"); len(got) != 1 || got[0] != "Revenue by quarter" {
+		t.Errorf("chart title = %v", got)
+	}
+	if got := titles(node, "desc#rev-desc"); len(got) != 1 {
+		t.Errorf("missing desc: %s", html)
+	}
+
+	tips := titles(node, ".minty-chart-bars rect title")
+	if len(tips) != 6 || tips[0] != "2024: Q1: 120" || tips[3] != "2025: Q2: -20" {
+		t.Errorf("bar tooltips = %v", tips)
+	}
+
+	// The negative bar hangs from the zero line, which all positive bars sit on
+	bars := mi.FindAll(node, ".minty-chart-bars rect")
+	zero := attr(bars[0], "y")
+	if neg := bars[3]; attr(neg, "y") == zero {
+		t.Errorf("negative bar should start at the zero line, not end there: %v", neg.Attributes)
+	}
+	if len(mi.FindAll(node, ".minty-chart-s2")) != 4 {
+		t.Errorf("expected three bars and a legend swatch for series 2")
+	}
+	if got := titles(node, ".minty-chart-legend text"); strings.Join(got, ",") != "2024,2025" {
+		t.Errorf("legend = %v", got)
+	}
+	if !strings.Contains(html, `<text x=`) || !strings.Contains(html, ">-50</text>") {
+		t.Errorf("expected y axis down to -50: %s", html)
+	}
+}
+
+func TestStackedBarDomainCoversStacks(t *testing.T) {
+	node, _ := build(t, StackedBar([]string{"A", "B"}, []Series{
+		{Name: "x", Values: []float64{30, 40}},
+		{Name: "y", Values: []float64{50, 45}},
+	}))
+	labels := titles(node, ".minty-chart-axes text")
+	if labels[len(labels)-3] != "100" {
+		t.Errorf("top tick should cover the 85 stack: %v", labels)
+	}
+	if n := len(mi.FindAll(node, ".minty-chart-bars rect")); n != 4 {
+		t.Errorf("got %d segments", n)
+	}
+}
+
+func TestLineGapsAndArea(t *testing.T) {
+	node, _ := build(t, Line([]string{"a", "b", "c", "d"}, []Series{
+		{Name: "s", Values: []float64{1, math.NaN(), 3, 4}},
+	}))
+	path := mi.FindFirst(node, ".minty-chart-lines path")
+	if d := attr(path, "d"); strings.Count(d, "M") != 2 {
+		t.Errorf("gap should start a new subpath: %s", d)
+	}
+	if n := len(mi.FindAll(node, "circle")); n != 3 {
+		t.Errorf("got %d points, want 3", n)
+	}
+	if mi.FindFirst(node, ".minty-chart-legend") != nil {
+		t.Error("a single series should not get a legend")
+	}
+
+	area, _ := build(t, Area([]string{"a", "b"}, []Series{{Values: []float64{2, 4}}}))
+	paths := mi.FindAll(area, ".minty-chart-lines path")
+	if len(paths) != 2 || attr(paths[0], "fill-opacity") != "0.2" {
+		t.Errorf("area should draw a fill and a line")
+	}
+}
+
+func TestSparklineIsDecorativeByDefault(t *testing.T) {
+	node, html := build(t, Sparkline([]float64{3, 5, 2, 8}))
+	root := node.(*mi.Element)
+	if attr(root, "aria-hidden") != "true" || root.Attributes.Has("role") {
+		t.Errorf("untitled sparkline should be aria-hidden: %s", html)
+	}
+	if strings.Contains(html, "<title>") || strings.Contains(html, "<text") {
+		t.Errorf("sparkline should have no title or axes: %s", html)
+	}
+	if c := mi.FindFirst(node, "circle"); attr(c, "cx") != "97" {
+		t.Errorf("end dot should sit on the last point: %v", c.Attributes)
+	}
+
+	titled, _ := build(t, Sparkline([]float64{1, 2}, Title("Signups, last 7 days")))
+	if attr(titled, "role") != "img" {
+		t.Error("titled sparkline should be an image")
+	}
+}
+
+func TestPieAndDonut(t *testing.T) {
+	slices := []Slice{
+		{Label: "Laptops", Value: 50},
+		{Label: "Phones", Value: 30},
+		{Label: "Empty", Value: 0},
+		{Label: "Monitors", Value: 20},
+	}
+	node, _ := build(t, Pie(slices))
+	tips := titles(node, ".minty-chart-slices title")
+	want := []string{"Laptops: 50 (50%)", "Phones: 30 (30%)", "Monitors: 20 (20%)"}
+	if strings.Join(tips, "|") != strings.Join(want, "|") {
+		t.Errorf("slice tooltips = %v", tips)
+	}
+	if got := titles(node, ".minty-chart-legend text"); len(got) != 3 {
+		t.Errorf("legend = %v", got)
+	}
+	// Monitors keeps its own palette colour even though Empty was skipped
+	if got := attr(mi.FindAll(node, ".minty-chart-slices path")[2], "fill"); got != DefaultPalette[3] {
+		t.Errorf("Monitors fill = %s", got)
+	}
+
+	donut, html := build(t, Donut(slices, CenterLabel("100 units")))
+	if !strings.Contains(html, ">100 units</text>") {
+		t.Errorf("missing center label: %s", html)
+	}
+	if d := attr(mi.FindFirst(donut, "path"), "d"); strings.Count(d, "A") != 2 {
+		t.Errorf("donut slices need inner and outer arcs: %s", d)
+	}
+
+	whole, _ := build(t, Donut([]Slice{{Label: "All", Value: 1}}))
+	if mi.FindFirst(whole, ".minty-chart-slices circle") == nil {
+		t.Error("a single slice should render as a ring")
+	}
+}
+
+func TestNoDataAndThemes(t *testing.T) {
+	for _, h := range []mi.H{
+		Bar(nil, nil),
+		Line([]string{"a"}, []Series{{Values: []float64{math.NaN()}}}),
+		Pie([]Slice{{Label: "x", Value: 0}}),
+	} {
+		if _, html := build(t, h); !strings.Contains(html, ">No data</text>") {
+			t.Errorf("expected placeholder: %s", html)
+		}
+	}
+
+	node, _ := build(t, Bar([]string{"a"}, []Series{{Values: []float64{1}}}, ForTheme(bootstrap.NewBootstrapTheme())))
+	if got := attr(mi.FindFirst(node, ".minty-chart-bars rect"), "fill"); got != "#0d6efd" {
+		t.Errorf("bootstrap fill = %s", got)
+	}
+	if got := ThemePalette("Unknown"); &got[0] != &DefaultPalette[0] {
+		t.Error("unknown themes should fall back to the default palette")
+	}
+}
+
+func attr(node mi.Node, name string) string {
+	value, _ := node.(*mi.Element).Attributes.Get(name)
+	return value
+}
@@ -0,0 +1,110 @@
+package mintychart
+
+import (
+	"math"
+	"strconv"
+
+	mi "github.com/ha1tch/minty"
+	msvg "github.com/ha1tch/minty/mintysvg"
+)
+
+// Pie renders a pie chart. Slices with zero or negative values are
+// skipped.
+func Pie(slices []Slice, opts ...Option) mi.H {
+	o := newOptions(320, 320, "Pie chart", opts)
+	return o.pie("pie", slices, 0)
+}
+
+// Donut renders a pie chart with a hole in the middle. CenterLabel puts
+// a total or headline figure in the hole.
+func Donut(slices []Slice, opts ...Option) mi.H {
+	o := newOptions(320, 320, "Donut chart", opts)
+	return o.pie("donut", slices, 0.6)
+}
+
+// pie draws slices clockwise from 12 o'clock. hole is the inner radius as
+// a fraction of the outer radius.
+func (o *Options) pie(kind string, slices []Slice, hole float64) mi.H {
+	total := 0.0
+	var items []legendItem
+	for i, s := range slices {
+		if s.Value > 0 {
+			total += s.Value
+			items = append(items, legendItem{label: s.Label, color: o.color(i, s.Color), class: i})
+		}
+	}
+	if total == 0 {
+		return o.noData(kind)
+	}
+
+	return func(b *mi.Builder) mi.Node {
+		var rows [][]legendItem
+		bottom := o.Height
+		if !o.HideLegend {
+			rows = legendRows(items, o.Width-24)
+			bottom -= float64(len(rows))*legendRowHeight + 8
+		}
+		cx, cy := o.Width/2, bottom/2
+		r := math.Min(o.Width, bottom)/2 - 8
+
+		marks := []interface{}{mi.Class("minty-chart-slices")}
+		angle := -math.Pi / 2
+		for i, s := range slices {
+			if s.Value <= 0 {
+				continue
+			}
+			color := o.color(i, s.Color)
+			tip := tooltip(s.Label, o.Format(s.Value)+" ("+percent(s.Value/total)+")")
+			sweep := s.Value / total * 2 * math.Pi
+
+			if sweep >= 2*math.Pi-1e-9 {
+				// An arc cannot start and end at the same point, so a single
+				// slice is a whole circle (or ring)
+				if hole > 0 {
+					marks = append(marks, msvg.Circle(msvg.Cx(round(cx)), msvg.Cy(round(cy)), msvg.R(round(r*(1+hole)/2)),
+						msvg.Fill("none"), msvg.Stroke(color), msvg.StrokeWidth(round(r*(1-hole))), seriesClass(i), tip))
+				} else {
+					marks = append(marks, msvg.Circle(msvg.Cx(round(cx)), msvg.Cy(round(cy)), msvg.R(round(r)),
+						msvg.Fill(color), seriesClass(i), tip))
+				}
+				break
+			}
+
+			end := angle + sweep
+			large := sweep > math.Pi
+			point := func(radius, a float64) (float64, float64) {
+				return round(cx + radius*math.Cos(a)), round(cy + radius*math.Sin(a))
+			}
+			x0, y0 := point(r, angle)
+			x1, y1 := point(r, end)
+			d := msvg.MoveTo(x0, y0).ArcTo(round(r), round(r), 0, large, true, x1, y1)
+			if hole > 0 {
+				ri := r * hole
+				x2, y2 := point(ri, end)
+				x3, y3 := point(ri, angle)
+				d = d.LineTo(x2, y2).ArcTo(round(ri), round(ri), 0, large, false, x3, y3).Close()
+			} else {
+				d = d.LineTo(round(cx), round(cy)).Close()
+			}
+			marks = append(marks, msvg.Path(d, msvg.Fill(color), msvg.Stroke("white"), msvg.StrokeWidth(1),
+				seriesClass(i), tip))
+			angle = end
+		}
+
+		children := []interface{}{msvg.G(marks...)}
+		if hole > 0 && o.CenterLabel != "" {
+			children = append(children, text(cx, cy+6, "middle", o.CenterLabel,
+				msvg.FontSize(18), msvg.FontWeight("600")))
+		}
+		if len(rows) > 0 {
+			children = append(children, legend(rows, o.Width, bottom+legendRowHeight))
+		}
+		return o.svg(kind, children...)
+	}
+}
+
+// percent formats a share as a whole or one-decimal percentage.
+func percent(share float64) string {
+	p := math.Round(share*1000) / 10
+	return strconv.FormatFloat(p, 'f', -1, 64) + "%"
+}
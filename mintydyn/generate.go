@@ -34,9 +34,9 @@ func (db *DynamicBuilder[S, D, R]) generateComponent(b *mi.Builder, pattern Dete
 	// Build children
 	var children []interface{}
 
-	// Inject theme CSS if provided
+	// Inject theme CSS if provided, once per page
 	if css := theme.InjectCSS(); css != "" {
-		children = append(children, b.Style(mi.Raw(css)))
+		children = append(children, b.Require(mi.InlineStyleAsset("mintydyn-theme:"+css, css)))
 	}
 
 	// Generate configuration script (JSON data for JS)
@@ -48,8 +48,9 @@ func (db *DynamicBuilder[S, D, R]) generateComponent(b *mi.Builder, pattern Dete
 		children = append(children, node)
 	}
 
-	// Generate JavaScript
-	children = append(children, mi.Raw(db.generateJavaScript(pattern)))
+	// Register the shared runtime and mount this instance
+	children = append(children, b.Require(db.runtimeAssets(pattern)...))
+	children = append(children, b.Script(mi.Raw(db.generateJavaScript(pattern))))
 
 	// Combine all
 	containerAttrs = append(containerAttrs, children...)
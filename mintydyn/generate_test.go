@@ -0,0 +1,63 @@
+package mintydyn
+
+import (
+	"strings"
+	"testing"
+
+	mi "github.com/ha1tch/minty"
+)
+
+func TestRuntimeEmittedOncePerDocument(t *testing.T) {
+	tabs := func(id string) mi.H {
+		return Tabs(id, []ComponentState{
+			ActiveState("one", "One", "first"),
+			NewState("two", "Two", "second"),
+		})
+	}
+	page := func(b *mi.Builder) mi.Node {
+		return mi.Document("Tabs", nil,
+			b.Body(tabs("a")(b), tabs("b")(b), tabs("c")(b)),
+		)(b)
+	}
+	html := mi.RenderToString(page)
+
+	if n := strings.Count(html, "window.MintyDynComponent = window.MintyDynComponent ||"); n != 1 {
+		t.Errorf("runtime emitted %d times, want 1", n)
+	}
+	if n := strings.Count(html, "window.MintyDynStatesManager = window.MintyDynStatesManager ||"); n != 1 {
+		t.Errorf("states manager emitted %d times, want 1", n)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		mount := "window.DynComponent_" + id + " = new MintyDynComponent('" + id + "', 'DynComponent_" + id + "')"
+		if !strings.Contains(html, mount) {
+			t.Errorf("missing mount script for %q", id)
+		}
+	}
+	if strings.Contains(html, "window.MintyDynDataManager =") {
+		t.Error("data manager should not be emitted for tabs")
+	}
+	if strings.Index(html, "window.MintyDynComponent =") < strings.LastIndex(html, `id="c"`) {
+		t.Error("runtime should be emitted at the end of <body>")
+	}
+}
+
+func TestRuleTargetsAreValidated(t *testing.T) {
+	form := Form("insurance", []DependencyRule{{
+		ID:      "show-spouse",
+		Trigger: TriggerCondition{ComponentID: "marital-status", Event: "change", Condition: "equals", Value: "married"},
+		Actions: []DependencyAction{{TargetID: "spouse-section", Action: "show"}},
+	}})
+	page := func(section mi.Node) mi.H {
+		return func(b *mi.Builder) mi.Node {
+			return mi.Document("Insurance", nil, b.Body(form(b), section))(b)
+		}
+	}
+
+	issues := mi.ValidateTree(page(mi.NewFragment())(mi.NewBuilder(nil)))
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, `mintydyn rule "show-spouse" refers to missing id "spouse-section"`) {
+		t.Errorf("issues: %v", issues)
+	}
+	if issues := mi.ValidateTree(page(mi.B.Div(mi.ID("spouse-section")))(mi.NewBuilder(nil))); len(issues) != 0 {
+		t.Errorf("issues: %v", issues)
+	}
+}
@@ -3,6 +3,8 @@ package mintydyn
 import (
 	"fmt"
 	"strings"
+
+	mi "github.com/ha1tch/minty"
 )
 
 // =============================================================================
@@ -29,55 +31,58 @@ func sanitizeID(id string) string {
 	return sb.String()
 }
 
-// generateJavaScript creates all client-side code for the component.
+// generateJavaScript creates the per-instance script that mounts the
+// component. The shared runtime it relies on comes from runtimeAssets.
 func (db *DynamicBuilder[S, D, R]) generateJavaScript(pattern DetectedPattern) string {
-	var js strings.Builder
+	result := db.generateInitialization()
 
-	js.WriteString("<script>\n")
+	// Apply minification if enabled
+	if db.options.MinifyJS {
+		result = MinifyJS(result)
+	}
 
-	// Generate base component class
-	js.WriteString(db.generateBaseClass())
+	return result
+}
 
-	// Generate pattern-specific managers
+// runtimeAssets returns the client runtime needed by pattern as page
+// assets, so that it is emitted once however many components use it.
+func (db *DynamicBuilder[S, D, R]) runtimeAssets(pattern DetectedPattern) []mi.Asset {
+	assets := []mi.Asset{
+		db.runtimeAsset("mintydyn-runtime", runtimeBaseClass()+runtimeCoordination()),
+	}
 	if pattern.HasStates {
-		js.WriteString(db.generateStatesManager())
+		assets = append(assets, db.runtimeAsset("mintydyn-states", runtimeStatesManager()))
 	}
 	if pattern.HasData {
-		js.WriteString(db.generateDataManager())
+		assets = append(assets, db.runtimeAsset("mintydyn-data", runtimeDataManager()))
 	}
 	if pattern.HasRules {
-		js.WriteString(db.generateRulesManager())
+		assets = append(assets, db.runtimeAsset("mintydyn-rules", runtimeRulesManager()))
 	}
+	return assets
+}
 
-	// Generate coordination logic
-	js.WriteString(db.generateCoordinationLogic(pattern))
-
-	// Generate initialization
-	js.WriteString(db.generateInitialization())
-
-	js.WriteString("\n</script>")
-
-	result := js.String()
-	
-	// Apply minification if enabled
+func (db *DynamicBuilder[S, D, R]) runtimeAsset(key, js string) mi.Asset {
 	if db.options.MinifyJS {
-		result = MinifyJS(result)
+		js = MinifyJS(js)
 	}
-
-	return result
+	return mi.InlineScriptAsset(key, js)
 }
 
 // =============================================================================
 // BASE COMPONENT CLASS
 // =============================================================================
 
-func (db *DynamicBuilder[S, D, R]) generateBaseClass() string {
-	jsID := sanitizeID(db.id)
-	return fmt.Sprintf(`
-// Dynamic Component: %s
-class DynamicComponent_%s {
-    constructor() {
-        this.id = '%s';
+// The runtime classes are assigned to window only if absent, so a page that
+// ends up with the runtime inlined more than once still works.
+
+func runtimeBaseClass() string {
+	return `
+// Dynamic Component runtime
+window.MintyDynComponent = window.MintyDynComponent || class MintyDynComponent {
+    constructor(id, globalName) {
+        this.id = id;
+        this.globalName = globalName;
         this.container = document.getElementById(this.id);
         this.config = this.loadConfig();
         this.managers = {};
@@ -111,7 +116,7 @@ class DynamicComponent_%s {
             if (this.hooks.beforeInit) {
                 const result = await this.runHook('beforeInit', {});
                 if (result === false) {
-                    console.warn('DynamicComponent %s: beforeInit hook cancelled initialization');
+                    console.warn('DynamicComponent ' + this.id + ': beforeInit hook cancelled initialization');
                     return;
                 }
             }
@@ -128,7 +133,7 @@ class DynamicComponent_%s {
             this.trigger('component:ready');
             
         } catch (error) {
-            console.error('DynamicComponent %s: initialization failed:', error);
+            console.error('DynamicComponent ' + this.id + ': initialization failed:', error);
             this.trigger('component:error', { error });
         }
     }
@@ -186,7 +191,7 @@ class DynamicComponent_%s {
     
     init() {
         if (!this.container) {
-            console.error('DynamicComponent %s: container not found');
+            console.error('DynamicComponent ' + this.id + ': container not found');
             return;
         }
         
@@ -199,18 +204,18 @@ class DynamicComponent_%s {
         const pattern = this.config.pattern;
         
         if (pattern.hasStates) {
-            this.managers.states = new StatesManager_%s(this);
+            this.managers.states = new MintyDynStatesManager(this);
         }
         if (pattern.hasData) {
-            this.managers.data = new DataManager_%s(this);
+            this.managers.data = new MintyDynDataManager(this);
         }
         if (pattern.hasRules) {
-            this.managers.rules = new RulesManager_%s(this);
+            this.managers.rules = new MintyDynRulesManager(this);
         }
     }
     
     setupCoordination() {
-        // Implemented by generateCoordinationLogic
+        // Defined with the coordination logic below
     }
     
     bindEvents() {
@@ -376,23 +381,24 @@ class DynamicComponent_%s {
         this.state.initialized = false;
         
         // Remove from window
-        delete window['DynComponent_%s'];
+        if (this.globalName) {
+            delete window[this.globalName];
+        }
         
         this.trigger('component:destroyed');
     }
-}
-`, db.id, jsID, db.id, db.id, db.id, db.id, jsID, jsID, jsID, jsID)
+};
+`
 }
 
 // =============================================================================
 // STATES MANAGER
 // =============================================================================
 
-func (db *DynamicBuilder[S, D, R]) generateStatesManager() string {
-	jsID := sanitizeID(db.id)
-	return fmt.Sprintf(`
+func runtimeStatesManager() string {
+	return `
 // States Manager
-class StatesManager_%s {
+window.MintyDynStatesManager = window.MintyDynStatesManager || class MintyDynStatesManager {
     constructor(component) {
         this.component = component;
         this.states = component.config.states || [];
@@ -550,19 +556,18 @@ class StatesManager_%s {
     getState(stateId) {
         return this.states.find(s => s.id === stateId);
     }
-}
-`, jsID)
+};
+`
 }
 
 // =============================================================================
 // DATA MANAGER
 // =============================================================================
 
-func (db *DynamicBuilder[S, D, R]) generateDataManager() string {
-	jsID := sanitizeID(db.id)
-	return fmt.Sprintf(`
+func runtimeDataManager() string {
+	return `
 // Data Manager
-class DataManager_%s {
+window.MintyDynDataManager = window.MintyDynDataManager || class MintyDynDataManager {
     constructor(component) {
         this.component = component;
         this.schema = component.config.schema || { fields: [] };
@@ -886,19 +891,18 @@ class DataManager_%s {
             this.applyServerFilters();
         }
     }
-}
-`, jsID)
+};
+`
 }
 
 // =============================================================================
 // RULES MANAGER
 // =============================================================================
 
-func (db *DynamicBuilder[S, D, R]) generateRulesManager() string {
-	jsID := sanitizeID(db.id)
-	return fmt.Sprintf(`
+func runtimeRulesManager() string {
+	return `
 // Rules Manager
-class RulesManager_%s {
+window.MintyDynRulesManager = window.MintyDynRulesManager || class MintyDynRulesManager {
     constructor(component) {
         this.component = component;
         this.rules = component.config.rules || [];
@@ -1085,73 +1089,58 @@ class RulesManager_%s {
     clearRuleHistory() {
         this.ruleHistory = [];
     }
-}
-`, jsID)
+};
+`
 }
 
 // =============================================================================
 // COORDINATION LOGIC
 // =============================================================================
 
-func (db *DynamicBuilder[S, D, R]) generateCoordinationLogic(pattern DetectedPattern) string {
-	jsID := sanitizeID(db.id)
-	var js strings.Builder
-
-	js.WriteString(fmt.Sprintf(`
+func runtimeCoordination() string {
+	return `
 // Coordination Logic
-DynamicComponent_%s.prototype.setupCoordination = function() {
-    const pattern = this.config.pattern;
-`, jsID))
+MintyDynComponent.prototype.setupCoordination = function() {
+    switch (this.config.pattern.primaryPattern) {
+    case 'stateful-data':
+    case 'filterable-states':
+        // States + Data coordination
+        if (this.managers.states && this.managers.data) {
+            this.on('state:change', (event) => {
+                const stateContext = event.detail.to;
+                this.filterDataForState(stateContext);
+            });
+        }
+        break;
 
-	switch pattern.PrimaryPattern {
-	case PatternStatefulData, PatternFilterableStates:
-		js.WriteString(`
-    // States + Data coordination
-    if (this.managers.states && this.managers.data) {
-        this.on('state:change', (event) => {
-            const stateContext = event.detail.to;
-            this.filterDataForState(stateContext);
-        });
-    }
-`)
+    case 'dependent-states':
+        // States + Rules coordination
+        if (this.managers.states && this.managers.rules) {
+            this.on('rule:executed', (event) => {
+                const rule = event.detail.rule;
+                this.handleStateAffectingRule(rule);
+            });
+        }
+        break;
 
-	case PatternDependentStates:
-		js.WriteString(`
-    // States + Rules coordination
-    if (this.managers.states && this.managers.rules) {
-        this.on('rule:executed', (event) => {
-            const rule = event.detail.rule;
-            this.handleStateAffectingRule(rule);
-        });
-    }
-`)
+    case 'dependent-data':
+        // Data + Rules coordination
+        if (this.managers.data && this.managers.rules) {
+            this.on('rule:executed', (event) => {
+                const rule = event.detail.rule;
+                this.handleFilterAffectingRule(rule);
+            });
+        }
+        break;
 
-	case PatternDependentData:
-		js.WriteString(`
-    // Data + Rules coordination
-    if (this.managers.data && this.managers.rules) {
-        this.on('rule:executed', (event) => {
-            const rule = event.detail.rule;
-            this.handleFilterAffectingRule(rule);
-        });
+    case 'complete':
+        // Complete coordination
+        this.setupCompleteCoordination();
+        break;
     }
-`)
-
-	case PatternComplete:
-		js.WriteString(`
-    // Complete coordination
-    this.setupCompleteCoordination();
-`)
-	}
-
-	js.WriteString(`
 };
-`)
 
-	// Add helper methods based on pattern
-	if pattern.HasStates && pattern.HasData {
-		js.WriteString(fmt.Sprintf(`
-DynamicComponent_%s.prototype.filterDataForState = function(stateContext) {
+MintyDynComponent.prototype.filterDataForState = function(stateContext) {
     const stateFilters = this.getFiltersForState(stateContext);
     Object.entries(stateFilters).forEach(([field, value]) => {
         this.managers.data.updateFilter(field, value, false);
@@ -1159,16 +1148,12 @@ DynamicComponent_%s.prototype.filterDataForState = function(stateContext) {
     this.managers.data.renderResults();
 };
 
-DynamicComponent_%s.prototype.getFiltersForState = function(stateId) {
+MintyDynComponent.prototype.getFiltersForState = function(stateId) {
     // Override in specific implementations or define via hooks
     return {};
 };
-`, jsID, jsID))
-	}
 
-	if pattern.PrimaryPattern == PatternComplete {
-		js.WriteString(fmt.Sprintf(`
-DynamicComponent_%s.prototype.setupCompleteCoordination = function() {
+MintyDynComponent.prototype.setupCompleteCoordination = function() {
     // State changes affect data
     this.on('state:change', (event) => {
         this.filterDataForState(event.detail.to);
@@ -1194,15 +1179,15 @@ DynamicComponent_%s.prototype.setupCompleteCoordination = function() {
     });
 };
 
-DynamicComponent_%s.prototype.handleStateAffectingRule = function(rule) {
+MintyDynComponent.prototype.handleStateAffectingRule = function(rule) {
     // Rules can affect state visibility
 };
 
-DynamicComponent_%s.prototype.handleFilterAffectingRule = function(rule) {
+MintyDynComponent.prototype.handleFilterAffectingRule = function(rule) {
     // Rules can affect filter availability
 };
 
-DynamicComponent_%s.prototype.handleEmptyResults = function() {
+MintyDynComponent.prototype.handleEmptyResults = function() {
     // Show no-results state if available
     if (this.managers.states) {
         const noResultsState = this.managers.states.states.find(s => s.id === 'no-results');
@@ -1211,10 +1196,7 @@ DynamicComponent_%s.prototype.handleEmptyResults = function() {
         }
     }
 };
-`, jsID, jsID, jsID, jsID))
-	}
-
-	return js.String()
+`
 }
 
 // =============================================================================
@@ -1227,8 +1209,8 @@ func (db *DynamicBuilder[S, D, R]) generateInitialization() string {
 // Auto-initialization
 document.addEventListener('DOMContentLoaded', function() {
     if (document.getElementById('%s')) {
-        window.DynComponent_%s = new DynamicComponent_%s();
+        window.DynComponent_%s = new MintyDynComponent('%s', 'DynComponent_%s');
     }
 });
-`, db.id, jsID, jsID)
+`, db.id, jsID, db.id, jsID)
 }
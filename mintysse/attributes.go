@@ -0,0 +1,61 @@
+package mintysse
+
+import (
+	"net/url"
+	"strings"
+
+	mi "github.com/ha1tch/minty"
+)
+
+// Markup for the htmx sse extension. Connect opens the stream on an
+// element; elements inside it swap in the events named by Swap, or make a
+// request when an event named by Trigger arrives.
+
+// attributes applies several attributes as one.
+type attributes []mi.Attribute
+
+// Apply adds the attributes to an element.
+func (a attributes) Apply(el *mi.Element) {
+	for _, attr := range a {
+		attr.Apply(el)
+	}
+}
+
+// Connect loads the sse extension on the element and connects it to url,
+// such as the path of a Broker with its topics in the query.
+func Connect(url string) mi.Attribute {
+	return attributes{mi.HtmxExt("sse"), mi.Attr("sse-connect", url)}
+}
+
+// ConnectTopics is Connect for a Broker using QueryTopics.
+func ConnectTopics(path string, topics ...string) mi.Attribute {
+	var sb strings.Builder
+	sb.WriteString(path)
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	for _, t := range topics {
+		sb.WriteString(sep + "topic=" + url.QueryEscape(t))
+		sep = "&"
+	}
+	return Connect(sb.String())
+}
+
+// Swap replaces the element's content with the data of the named events.
+// Combine it with mi.HtmxSwap to choose another strategy, such as
+// "beforeend" for a feed.
+func Swap(events ...string) mi.Attribute {
+	return mi.Attr("sse-swap", strings.Join(events, ","))
+}
+
+// Trigger makes the element send its htmx request when the named event
+// arrives, instead of receiving the event data.
+func Trigger(event string) mi.Attribute {
+	return mi.HtmxTrigger("sse:" + event)
+}
+
+// Close closes the connection when the named event arrives.
+func Close(event string) mi.Attribute {
+	return mi.Attr("sse-close", event)
+}
@@ -0,0 +1,403 @@
+// Package mintysse pushes minty fragments to the browser with Server-Sent
+// Events, for pages that should update when something happens on the
+// server rather than by polling with hx-trigger="every 5s".
+//
+// A Broker is an http.Handler that clients connect to, subscribing to one
+// or more topics. Publish renders a fragment and sends it to every
+// subscriber of a topic as a named event:
+//
+//	events := msse.NewBroker()
+//	mux.Handle("/events", events)
+//
+//	// in the page, with the htmx sse extension loaded
+//	b.Div(msse.Connect("/events?topic=shipments"),
+//	    b.Div(msse.Swap("shipment-42"), ShipmentCard(s)(b)),
+//	)
+//
+//	// when the shipment changes
+//	events.Publish("shipments", "shipment-42", ShipmentCard(s))
+//
+// Each client has a bounded buffer. A client that falls behind is
+// disconnected instead of slowing the publisher down; the browser
+// reconnects with the Last-Event-ID header and the broker replays the
+// events it missed from the topic's recent history. Idle connections get a
+// heartbeat comment so that proxies keep them open.
+//
+// Import with: import msse "github.com/ha1tch/minty/mintysse"
+package mintysse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mi "github.com/ha1tch/minty"
+)
+
+// Event is one message sent to subscribers.
+type Event struct {
+	ID    string // assigned by the broker, increasing across all topics
+	Topic string
+	Name  string // the SSE event name; "" is delivered as "message"
+	Data  string
+}
+
+// Options configures a Broker.
+type Options struct {
+	// History is how many recent events each topic keeps for replay to
+	// reconnecting clients. The default is 100.
+	History int
+
+	// ClientBuffer is how many events may queue for one client before it
+	// is disconnected as too slow. The default is 64.
+	ClientBuffer int
+
+	// Heartbeat is the interval of the keep-alive comment sent to idle
+	// clients. The default is 15 seconds; a negative value disables it.
+	Heartbeat time.Duration
+
+	// Retry, if set, tells browsers how long to wait before reconnecting.
+	Retry time.Duration
+
+	// Topics returns the topics a request subscribes to. The default reads
+	// the "topic" query parameters. Use it to check that the user may see
+	// a topic: returning no topics answers 400, and an *mi.HTTPError
+	// answers with its status.
+	Topics func(r *http.Request) ([]string, error)
+}
+
+// Option configures Options.
+type Option func(*Options)
+
+// History sets how many events each topic keeps for replay.
+func History(n int) Option {
+	return func(o *Options) {
+		o.History = n
+	}
+}
+
+// ClientBuffer sets how many events may queue for one client.
+func ClientBuffer(n int) Option {
+	return func(o *Options) {
+		o.ClientBuffer = n
+	}
+}
+
+// Heartbeat sets the keep-alive interval; d < 0 disables it.
+func Heartbeat(d time.Duration) Option {
+	return func(o *Options) {
+		o.Heartbeat = d
+	}
+}
+
+// Retry sets the reconnection delay sent to browsers.
+func Retry(d time.Duration) Option {
+	return func(o *Options) {
+		o.Retry = d
+	}
+}
+
+// Topics sets the function that chooses the topics of a request.
+func Topics(fn func(r *http.Request) ([]string, error)) Option {
+	return func(o *Options) {
+		o.Topics = fn
+	}
+}
+
+// QueryTopics reads the topics from the "topic" query parameters,
+// e.g. /events?topic=shipments&topic=metrics.
+func QueryTopics(r *http.Request) ([]string, error) {
+	return r.URL.Query()["topic"], nil
+}
+
+// ErrClosed is returned by Publish after Close.
+var ErrClosed = errors.New("mintysse: broker closed")
+
+// Broker fans events out to subscribed clients. It is safe for concurrent
+// use.
+type Broker struct {
+	opts Options
+
+	mu     sync.Mutex
+	lastID uint64
+	topics map[string]*topic
+	closed bool
+}
+
+// topic holds the subscribers and recent events of one topic.
+type topic struct {
+	clients map[*client]bool
+	history []Event
+}
+
+// client is one connected browser.
+type client struct {
+	events chan Event
+	done   chan struct{} // closed when the broker drops the client
+	once   sync.Once
+}
+
+func (c *client) drop() {
+	c.once.Do(func() { close(c.done) })
+}
+
+// NewBroker creates a broker.
+func NewBroker(opts ...Option) *Broker {
+	o := Options{History: 100, ClientBuffer: 64, Heartbeat: 15 * time.Second, Topics: QueryTopics}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.ClientBuffer < 1 {
+		o.ClientBuffer = 1
+	}
+	return &Broker{opts: o, topics: make(map[string]*topic)}
+}
+
+// Publish renders fragment and sends it to the subscribers of topic as the
+// named event.
+func (b *Broker) Publish(topic, event string, fragment mi.H) error {
+	return b.PublishContext(context.Background(), topic, event, fragment)
+}
+
+// PublishContext is Publish with the fragment rendered in ctx, for
+// components that read the locale or other context values.
+func (b *Broker) PublishContext(ctx context.Context, topic, event string, fragment mi.H) error {
+	var sb strings.Builder
+	if err := mi.RenderWithContext(ctx, fragment, &sb); err != nil {
+		return err
+	}
+	return b.PublishData(topic, event, sb.String())
+}
+
+// PublishData sends data to the subscribers of topic as the named event.
+func (b *Broker) PublishData(topicName, event, data string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	b.lastID++
+	e := Event{ID: strconv.FormatUint(b.lastID, 10), Topic: topicName, Name: event, Data: data}
+
+	t := b.topic(topicName)
+	if b.opts.History > 0 {
+		t.history = append(t.history, e)
+		if len(t.history) > b.opts.History {
+			t.history = append(t.history[:0:0], t.history[len(t.history)-b.opts.History:]...)
+		}
+	}
+	for c := range t.clients {
+		select {
+		case c.events <- e:
+		default:
+			// too slow: it reconnects and catches up from history
+			b.unsubscribe(c)
+		}
+	}
+	return nil
+}
+
+// Subscribers returns the number of clients subscribed to topic.
+func (b *Broker) Subscribers(topicName string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t, ok := b.topics[topicName]; ok {
+		return len(t.clients)
+	}
+	return 0
+}
+
+// Close disconnects every client and stops accepting events and
+// connections.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for _, t := range b.topics {
+		for c := range t.clients {
+			c.drop()
+		}
+	}
+	b.topics = make(map[string]*topic)
+}
+
+func (b *Broker) topic(name string) *topic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{clients: make(map[*client]bool)}
+		b.topics[name] = t
+	}
+	return t
+}
+
+// subscribe registers c and returns the events after lastEventID that it
+// missed, in order. Holding the lock across both keeps replay and live
+// events from overlapping or leaving a gap.
+func (b *Broker) subscribe(c *client, topics []string, lastEventID string) ([]Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	after, err := strconv.ParseUint(lastEventID, 10, 64)
+	replay := err == nil
+	var missed []Event
+	for _, name := range topics {
+		t := b.topic(name)
+		if t.clients[c] {
+			continue // listed twice
+		}
+		t.clients[c] = true
+		if !replay {
+			continue
+		}
+		for _, e := range t.history {
+			if id, _ := strconv.ParseUint(e.ID, 10, 64); id > after {
+				missed = append(missed, e)
+			}
+		}
+	}
+	// merge the topics' histories into publication order
+	sortEvents(missed)
+	return missed, nil
+}
+
+// unsubscribe removes c from every topic. The caller holds b.mu.
+func (b *Broker) unsubscribe(c *client) {
+	for name, t := range b.topics {
+		delete(t.clients, c)
+		if len(t.clients) == 0 && len(t.history) == 0 {
+			delete(b.topics, name)
+		}
+	}
+	c.drop()
+}
+
+func sortEvents(events []Event) {
+	id := func(e Event) uint64 {
+		n, _ := strconv.ParseUint(e.ID, 10, 64)
+		return n
+	}
+	// insertion sort: the slices are short and mostly ordered
+	for i := 1; i < len(events); i++ {
+		for j := i; j > 0 && id(events[j]) < id(events[j-1]); j-- {
+			events[j], events[j-1] = events[j-1], events[j]
+		}
+	}
+}
+
+// ServeHTTP streams the events of the requested topics to the client until
+// it disconnects, it falls behind, or the broker is closed.
+func (b *Broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	topics, err := b.opts.Topics(r)
+	if err == nil && len(topics) == 0 {
+		err = mi.NewHTTPError(http.StatusBadRequest, "no topic")
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		var he *mi.HTTPError
+		if errors.As(err, &he) {
+			status = he.Status
+		}
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	c := &client{events: make(chan Event, b.opts.ClientBuffer), done: make(chan struct{})}
+	missed, err := b.subscribe(c, topics, r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		http.Error(w, "event stream closed", http.StatusServiceUnavailable)
+		return
+	}
+	defer func() {
+		b.mu.Lock()
+		b.unsubscribe(c)
+		b.mu.Unlock()
+	}()
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no") // stop nginx from buffering the stream
+	w.WriteHeader(http.StatusOK)
+
+	var sb strings.Builder
+	if b.opts.Retry > 0 {
+		sb.WriteString("retry: " + strconv.FormatInt(b.opts.Retry.Milliseconds(), 10) + "\n\n")
+	}
+	for _, e := range missed {
+		writeEvent(&sb, e)
+	}
+	if !b.send(w, rc, sb.String()) {
+		return
+	}
+
+	var heartbeat <-chan time.Time
+	if b.opts.Heartbeat > 0 {
+		ticker := time.NewTicker(b.opts.Heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+	for {
+		select {
+		case e := <-c.events:
+			sb.Reset()
+			writeEvent(&sb, e)
+			// send whatever else has queued in the same write
+			for drained := false; !drained; {
+				select {
+				case e := <-c.events:
+					writeEvent(&sb, e)
+				default:
+					drained = true
+				}
+			}
+			if !b.send(w, rc, sb.String()) {
+				return
+			}
+		case <-heartbeat:
+			if !b.send(w, rc, ": heartbeat\n\n") {
+				return
+			}
+		case <-c.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// send writes s and flushes it to the client.
+func (b *Broker) send(w http.ResponseWriter, rc *http.ResponseController, s string) bool {
+	if _, err := w.Write([]byte(s)); err != nil {
+		return false
+	}
+	return rc.Flush() == nil
+}
+
+// writeEvent formats e in the text/event-stream format. Every line of the
+// data gets its own data field, so multi-line HTML arrives intact.
+func writeEvent(sb *strings.Builder, e Event) {
+	sb.WriteString("id: " + e.ID + "\n")
+	if e.Name != "" {
+		sb.WriteString("event: " + singleLine(e.Name) + "\n")
+	}
+	data := strings.ReplaceAll(strings.ReplaceAll(e.Data, "\r\n", "\n"), "\r", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		sb.WriteString("data: " + line + "\n")
+	}
+	sb.WriteString("\n")
+}
+
+// singleLine drops line breaks, which would end a field early.
+func singleLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
@@ -0,0 +1,165 @@
+package mintysse
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mi "github.com/ha1tch/minty"
+)
+
+// connect opens a stream and returns a reader of its events.
+func connect(t *testing.T, url string, header http.Header) *bufio.Reader {
+	t.Helper()
+	req, _ := http.NewRequest("GET", url, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+// next reads one event or comment block.
+func next(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading stream: %v (read %q)", err, lines)
+		}
+		if line == "\n" {
+			return strings.Join(lines, "")
+		}
+		lines = append(lines, line)
+	}
+}
+
+func waitForSubscribers(t *testing.T, b *Broker, topic string, n int) {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		if b.Subscribers(topic) == n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("%s has %d subscribers, want %d", topic, b.Subscribers(topic), n)
+}
+
+func TestPublishRendersFragments(t *testing.T) {
+	broker := NewBroker(Heartbeat(-1))
+	srv := httptest.NewServer(broker)
+	defer srv.Close()
+	defer broker.Close()
+
+	stream := connect(t, srv.URL+"?topic=shipments&topic=metrics", nil)
+	waitForSubscribers(t, broker, "shipments", 1)
+
+	broker.Publish("shipments", "shipment-7", func(b *mi.Builder) mi.Node {
+		return b.Div(mi.Class("card"), b.P("In transit"))
+	})
+	broker.PublishData("metrics", "", "line one\nline two")
+	broker.PublishData("other", "ignored", "x")
+
+	if got := next(t, stream); got != "id: 1\nevent: shipment-7\ndata: <div class=\"card\"><p>In transit</p></div>\n" {
+		t.Errorf("event = %q", got)
+	}
+	if got := next(t, stream); got != "id: 2\ndata: line one\ndata: line two\n" {
+		t.Errorf("event = %q", got)
+	}
+}
+
+func TestReconnectReplaysMissedEvents(t *testing.T) {
+	broker := NewBroker(Heartbeat(-1), History(2), Retry(2*time.Second))
+	srv := httptest.NewServer(broker)
+	defer srv.Close()
+	defer broker.Close()
+
+	for _, status := range []string{"packed", "shipped", "delivered"} {
+		broker.PublishData("shipments", "status", status)
+	}
+	broker.PublishData("metrics", "count", "3")
+
+	stream := connect(t, srv.URL+"?topic=shipments&topic=metrics&topic=shipments", http.Header{"Last-Event-ID": {"1"}})
+	if got := next(t, stream); got != "retry: 2000\n" {
+		t.Errorf("retry = %q", got)
+	}
+	for _, want := range []string{"id: 2\nevent: status\ndata: shipped\n", "id: 3\nevent: status\ndata: delivered\n", "id: 4\nevent: count\ndata: 3\n"} {
+		if got := next(t, stream); got != want {
+			t.Errorf("event = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestHeartbeatAndTopicErrors(t *testing.T) {
+	broker := NewBroker(Heartbeat(10 * time.Millisecond))
+	srv := httptest.NewServer(broker)
+	defer srv.Close()
+	defer broker.Close()
+
+	stream := connect(t, srv.URL+"?topic=shipments", nil)
+	if got := next(t, stream); got != ": heartbeat\n" {
+		t.Errorf("got %q", got)
+	}
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status without topics = %d", resp.StatusCode)
+	}
+}
+
+func TestSlowClientsAreDropped(t *testing.T) {
+	broker := NewBroker(ClientBuffer(1))
+	c := &client{events: make(chan Event, 1), done: make(chan struct{})}
+	if _, err := broker.subscribe(c, []string{"shipments"}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	broker.PublishData("shipments", "", "1")
+	if broker.Subscribers("shipments") != 1 {
+		t.Fatal("client should still be subscribed")
+	}
+	broker.PublishData("shipments", "", "2")
+	select {
+	case <-c.done:
+	default:
+		t.Error("client with a full buffer should be dropped")
+	}
+	if broker.Subscribers("shipments") != 0 {
+		t.Error("dropped client should be unsubscribed")
+	}
+
+	broker.Close()
+	if err := broker.PublishData("shipments", "", "3"); err != ErrClosed {
+		t.Errorf("Publish after Close = %v", err)
+	}
+}
+
+func TestMarkupHelpers(t *testing.T) {
+	got := mi.RenderToString(func(b *mi.Builder) mi.Node {
+		return b.Div(mi.HtmxExt("json-enc"), ConnectTopics("/events", "shipments", "a&b"),
+			b.Ul(Swap("shipment-added", "shipment-moved"), mi.HtmxSwap("beforeend")),
+			b.Button(Trigger("refresh"), mi.HtmxGet("/metrics"), "Refresh"),
+		)
+	})
+	want := `<div hx-ext="json-enc, sse" sse-connect="/events?topic=shipments&amp;topic=a%26b">` +
+		`<ul sse-swap="shipment-added,shipment-moved" hx-swap="beforeend"></ul>` +
+		`<button hx-trigger="sse:refresh" hx-get="/metrics">Refresh</button></div>`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
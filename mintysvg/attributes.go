@@ -0,0 +1,348 @@
+package mintysvg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	mi "github.com/ha1tch/minty"
+)
+
+// Attribute helpers. SVG attribute names are case-sensitive, so the
+// helpers write the exact spelling (viewBox, gradientUnits). Lengths and
+// coordinates accept numbers or strings ("50%", "2em"); floats are
+// written without trailing zeros.
+
+// attr creates a string attribute.
+func attr(name, value string) mi.Attribute {
+	return mi.StringAttribute{Name: name, Value: value}
+}
+
+// Number formats a float the way the helpers write it: shortest form,
+// no exponent, no trailing zeros.
+func Number(v float64) string {
+	if v == 0 {
+		return "0" // avoid "-0"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// length formats a number or a length string.
+func length(v interface{}) string {
+	switch n := v.(type) {
+	case float64:
+		return Number(n)
+	case float32:
+		return Number(float64(n))
+	case int:
+		return strconv.Itoa(n)
+	case string:
+		return n
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// numbers formats a list of numbers separated by spaces.
+func numbers(values []float64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = Number(v)
+	}
+	return strings.Join(parts, " ")
+}
+
+// URL returns the url(#id) reference used by fill, stroke, clip-path,
+// mask, filter and marker attributes.
+func URL(id string) string {
+	return "url(#" + id + ")"
+}
+
+// Geometry
+
+// ViewBox creates a viewBox attribute.
+func ViewBox(minX, minY, width, height float64) mi.Attribute {
+	return attr("viewBox", numbers([]float64{minX, minY, width, height}))
+}
+
+// PreserveAspectRatio creates a preserveAspectRatio attribute, e.g.
+// "xMidYMid meet" or "none".
+func PreserveAspectRatio(value string) mi.Attribute {
+	return attr("preserveAspectRatio", value)
+}
+
+// X creates an x attribute.
+func X(v interface{}) mi.Attribute { return attr("x", length(v)) }
+
+// Y creates a y attribute.
+func Y(v interface{}) mi.Attribute { return attr("y", length(v)) }
+
+// Width creates a width attribute.
+func Width(v interface{}) mi.Attribute { return attr("width", length(v)) }
+
+// Height creates a height attribute.
+func Height(v interface{}) mi.Attribute { return attr("height", length(v)) }
+
+// X1 creates an x1 attribute (lines and linear gradients).
+func X1(v interface{}) mi.Attribute { return attr("x1", length(v)) }
+
+// Y1 creates a y1 attribute (lines and linear gradients).
+func Y1(v interface{}) mi.Attribute { return attr("y1", length(v)) }
+
+// X2 creates an x2 attribute (lines and linear gradients).
+func X2(v interface{}) mi.Attribute { return attr("x2", length(v)) }
+
+// Y2 creates a y2 attribute (lines and linear gradients).
+func Y2(v interface{}) mi.Attribute { return attr("y2", length(v)) }
+
+// Cx creates a cx attribute.
+func Cx(v interface{}) mi.Attribute { return attr("cx", length(v)) }
+
+// Cy creates a cy attribute.
+func Cy(v interface{}) mi.Attribute { return attr("cy", length(v)) }
+
+// R creates an r attribute.
+func R(v interface{}) mi.Attribute { return attr("r", length(v)) }
+
+// Rx creates an rx attribute.
+func Rx(v interface{}) mi.Attribute { return attr("rx", length(v)) }
+
+// Ry creates an ry attribute.
+func Ry(v interface{}) mi.Attribute { return attr("ry", length(v)) }
+
+// Fx creates an fx attribute (radial gradient focal point).
+func Fx(v interface{}) mi.Attribute { return attr("fx", length(v)) }
+
+// Fy creates an fy attribute (radial gradient focal point).
+func Fy(v interface{}) mi.Attribute { return attr("fy", length(v)) }
+
+// Dx creates a dx attribute.
+func Dx(v interface{}) mi.Attribute { return attr("dx", length(v)) }
+
+// Dy creates a dy attribute.
+func Dy(v interface{}) mi.Attribute { return attr("dy", length(v)) }
+
+// D creates the d attribute of a path; see PathData for a builder.
+func D(value string) mi.Attribute { return attr("d", value) }
+
+// Points creates a points attribute from x, y pairs.
+func Points(coords ...float64) mi.Attribute {
+	pairs := make([]string, 0, len(coords)/2)
+	for i := 0; i+1 < len(coords); i += 2 {
+		pairs = append(pairs, Number(coords[i])+","+Number(coords[i+1]))
+	}
+	return attr("points", strings.Join(pairs, " "))
+}
+
+// PathLength creates a pathLength attribute.
+func PathLength(v float64) mi.Attribute { return attr("pathLength", Number(v)) }
+
+// Href creates an href attribute, e.g. Href("#icon-star") on <use>.
+func Href(value string) mi.Attribute { return attr("href", value) }
+
+// XlinkHref creates an xlink:href attribute for older SVG consumers.
+func XlinkHref(value string) mi.Attribute { return attr("xlink:href", value) }
+
+// Painting
+
+// Fill creates a fill attribute: a colour, "none", "currentColor" or URL(id).
+func Fill(value string) mi.Attribute { return attr("fill", value) }
+
+// FillOpacity creates a fill-opacity attribute.
+func FillOpacity(v float64) mi.Attribute { return attr("fill-opacity", Number(v)) }
+
+// FillRule creates a fill-rule attribute: "nonzero" or "evenodd".
+func FillRule(value string) mi.Attribute { return attr("fill-rule", value) }
+
+// ClipRule creates a clip-rule attribute: "nonzero" or "evenodd".
+func ClipRule(value string) mi.Attribute { return attr("clip-rule", value) }
+
+// Stroke creates a stroke attribute.
+func Stroke(value string) mi.Attribute { return attr("stroke", value) }
+
+// StrokeWidth creates a stroke-width attribute.
+func StrokeWidth(v interface{}) mi.Attribute { return attr("stroke-width", length(v)) }
+
+// StrokeOpacity creates a stroke-opacity attribute.
+func StrokeOpacity(v float64) mi.Attribute { return attr("stroke-opacity", Number(v)) }
+
+// StrokeLinecap creates a stroke-linecap attribute: "butt", "round" or "square".
+func StrokeLinecap(value string) mi.Attribute { return attr("stroke-linecap", value) }
+
+// StrokeLinejoin creates a stroke-linejoin attribute: "miter", "round" or "bevel".
+func StrokeLinejoin(value string) mi.Attribute { return attr("stroke-linejoin", value) }
+
+// StrokeMiterlimit creates a stroke-miterlimit attribute.
+func StrokeMiterlimit(v float64) mi.Attribute { return attr("stroke-miterlimit", Number(v)) }
+
+// StrokeDasharray creates a stroke-dasharray attribute.
+func StrokeDasharray(dashes ...float64) mi.Attribute {
+	return attr("stroke-dasharray", numbers(dashes))
+}
+
+// StrokeDashoffset creates a stroke-dashoffset attribute.
+func StrokeDashoffset(v float64) mi.Attribute { return attr("stroke-dashoffset", Number(v)) }
+
+// Opacity creates an opacity attribute.
+func Opacity(v float64) mi.Attribute { return attr("opacity", Number(v)) }
+
+// VectorEffect creates a vector-effect attribute, e.g. "non-scaling-stroke".
+func VectorEffect(value string) mi.Attribute { return attr("vector-effect", value) }
+
+// ShapeRendering creates a shape-rendering attribute, e.g. "crispEdges".
+func ShapeRendering(value string) mi.Attribute { return attr("shape-rendering", value) }
+
+// Visibility creates a visibility attribute.
+func Visibility(value string) mi.Attribute { return attr("visibility", value) }
+
+// ClipPathRef creates a clip-path attribute referencing a <clipPath> by id.
+func ClipPathRef(id string) mi.Attribute { return attr("clip-path", URL(id)) }
+
+// MaskRef creates a mask attribute referencing a <mask> by id.
+func MaskRef(id string) mi.Attribute { return attr("mask", URL(id)) }
+
+// FilterRef creates a filter attribute referencing a <filter> by id.
+func FilterRef(id string) mi.Attribute { return attr("filter", URL(id)) }
+
+// MarkerStart creates a marker-start attribute referencing a <marker> by id.
+func MarkerStart(id string) mi.Attribute { return attr("marker-start", URL(id)) }
+
+// MarkerMid creates a marker-mid attribute referencing a <marker> by id.
+func MarkerMid(id string) mi.Attribute { return attr("marker-mid", URL(id)) }
+
+// MarkerEnd creates a marker-end attribute referencing a <marker> by id.
+func MarkerEnd(id string) mi.Attribute { return attr("marker-end", URL(id)) }
+
+// Gradients, patterns, clipping, masking and markers
+
+// GradientUnits creates a gradientUnits attribute: "userSpaceOnUse" or
+// "objectBoundingBox".
+func GradientUnits(value string) mi.Attribute { return attr("gradientUnits", value) }
+
+// GradientTransform creates a gradientTransform attribute.
+func GradientTransform(t Transform) mi.Attribute { return attr("gradientTransform", t.String()) }
+
+// SpreadMethod creates a spreadMethod attribute: "pad", "reflect" or "repeat".
+func SpreadMethod(value string) mi.Attribute { return attr("spreadMethod", value) }
+
+// Offset creates the offset attribute of a gradient stop, e.g. "0.5" or "50%".
+func Offset(v interface{}) mi.Attribute { return attr("offset", length(v)) }
+
+// StopColor creates a stop-color attribute.
+func StopColor(value string) mi.Attribute { return attr("stop-color", value) }
+
+// StopOpacity creates a stop-opacity attribute.
+func StopOpacity(v float64) mi.Attribute { return attr("stop-opacity", Number(v)) }
+
+// PatternUnits creates a patternUnits attribute.
+func PatternUnits(value string) mi.Attribute { return attr("patternUnits", value) }
+
+// PatternContentUnits creates a patternContentUnits attribute.
+func PatternContentUnits(value string) mi.Attribute { return attr("patternContentUnits", value) }
+
+// PatternTransform creates a patternTransform attribute.
+func PatternTransform(t Transform) mi.Attribute { return attr("patternTransform", t.String()) }
+
+// ClipPathUnits creates a clipPathUnits attribute.
+func ClipPathUnits(value string) mi.Attribute { return attr("clipPathUnits", value) }
+
+// MaskUnits creates a maskUnits attribute.
+func MaskUnits(value string) mi.Attribute { return attr("maskUnits", value) }
+
+// MaskContentUnits creates a maskContentUnits attribute.
+func MaskContentUnits(value string) mi.Attribute { return attr("maskContentUnits", value) }
+
+// MarkerUnits creates a markerUnits attribute.
+func MarkerUnits(value string) mi.Attribute { return attr("markerUnits", value) }
+
+// MarkerWidth creates a markerWidth attribute.
+func MarkerWidth(v float64) mi.Attribute { return attr("markerWidth", Number(v)) }
+
+// MarkerHeight creates a markerHeight attribute.
+func MarkerHeight(v float64) mi.Attribute { return attr("markerHeight", Number(v)) }
+
+// RefX creates a refX attribute.
+func RefX(v float64) mi.Attribute { return attr("refX", Number(v)) }
+
+// RefY creates a refY attribute.
+func RefY(v float64) mi.Attribute { return attr("refY", Number(v)) }
+
+// Orient creates an orient attribute, e.g. "auto" or "auto-start-reverse".
+func Orient(value string) mi.Attribute { return attr("orient", value) }
+
+// Text
+
+// TextAnchor creates a text-anchor attribute: "start", "middle" or "end".
+func TextAnchor(value string) mi.Attribute { return attr("text-anchor", value) }
+
+// DominantBaseline creates a dominant-baseline attribute, e.g. "middle".
+func DominantBaseline(value string) mi.Attribute { return attr("dominant-baseline", value) }
+
+// FontFamily creates a font-family attribute.
+func FontFamily(value string) mi.Attribute { return attr("font-family", value) }
+
+// FontSize creates a font-size attribute.
+func FontSize(v interface{}) mi.Attribute { return attr("font-size", length(v)) }
+
+// FontWeight creates a font-weight attribute.
+func FontWeight(value string) mi.Attribute { return attr("font-weight", value) }
+
+// TextLength creates a textLength attribute.
+func TextLength(v interface{}) mi.Attribute { return attr("textLength", length(v)) }
+
+// LengthAdjust creates a lengthAdjust attribute.
+func LengthAdjust(value string) mi.Attribute { return attr("lengthAdjust", value) }
+
+// StartOffset creates the startOffset attribute of a <textPath>.
+func StartOffset(v interface{}) mi.Attribute { return attr("startOffset", length(v)) }
+
+// Filters
+
+// In creates an in attribute naming a filter input.
+func In(value string) mi.Attribute { return attr("in", value) }
+
+// In2 creates an in2 attribute naming a second filter input.
+func In2(value string) mi.Attribute { return attr("in2", value) }
+
+// Result creates a result attribute naming a filter output.
+func Result(value string) mi.Attribute { return attr("result", value) }
+
+// StdDeviation creates a stdDeviation attribute.
+func StdDeviation(values ...float64) mi.Attribute { return attr("stdDeviation", numbers(values)) }
+
+// Mode creates a mode attribute of <feBlend>.
+func Mode(value string) mi.Attribute { return attr("mode", value) }
+
+// Operator creates an operator attribute of <feComposite>.
+func Operator(value string) mi.Attribute { return attr("operator", value) }
+
+// FloodColor creates a flood-color attribute.
+func FloodColor(value string) mi.Attribute { return attr("flood-color", value) }
+
+// FloodOpacity creates a flood-opacity attribute.
+func FloodOpacity(v float64) mi.Attribute { return attr("flood-opacity", Number(v)) }
+
+// Animation
+
+// AttributeName creates the attributeName attribute of an animation.
+func AttributeName(value string) mi.Attribute { return attr("attributeName", value) }
+
+// AttributeType creates the type attribute of <animateTransform>, e.g. "rotate".
+func AttributeType(value string) mi.Attribute { return attr("type", value) }
+
+// Values creates a values attribute.
+func Values(values ...string) mi.Attribute { return attr("values", strings.Join(values, ";")) }
+
+// From creates a from attribute.
+func From(value string) mi.Attribute { return attr("from", value) }
+
+// To creates a to attribute.
+func To(value string) mi.Attribute { return attr("to", value) }
+
+// Dur creates a dur attribute, e.g. "1.5s".
+func Dur(value string) mi.Attribute { return attr("dur", value) }
+
+// Begin creates a begin attribute.
+func Begin(value string) mi.Attribute { return attr("begin", value) }
+
+// RepeatCount creates a repeatCount attribute, e.g. "indefinite".
+func RepeatCount(value string) mi.Attribute { return attr("repeatCount", value) }
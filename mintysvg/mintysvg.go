@@ -0,0 +1,200 @@
+// Package mintysvg builds SVG documents and inline SVG with minty.
+//
+// It covers the SVG element set with the case-sensitive names SVG needs
+// (linearGradient, clipPath, textPath...), writes childless elements in
+// the self-closing form (<path d="..." />) while still allowing children
+// such as <title> or <animate>, and provides typed attribute helpers,
+// a transform builder and a path-data builder:
+//
+//	msvg.SVG(msvg.ViewBox(0, 0, 24, 24), mi.Class("w-6 h-6"),
+//	    msvg.Defs(
+//	        msvg.LinearGradient(mi.ID("fade"), msvg.X2("0"), msvg.Y2("1"),
+//	            msvg.Stop(msvg.Offset("0"), msvg.StopColor("#4f46e5")),
+//	            msvg.Stop(msvg.Offset("1"), msvg.StopColor("#4f46e5"), msvg.StopOpacity(0)),
+//	        ),
+//	    ),
+//	    msvg.Path(msvg.MoveTo(2, 20).LineTo(8, 12).LineTo(22, 18),
+//	        msvg.Fill(msvg.URL("fade")),
+//	        msvg.Translate(0, 1).Scale(0.9),
+//	    ),
+//	)
+//
+// Elements accept the same mixed arguments as the minty builders:
+// attributes (from this package or minty's core helpers such as mi.Class
+// and mi.ID), nodes and strings.
+//
+// Import with: import msvg "github.com/ha1tch/minty/mintysvg"
+package mintysvg
+
+import (
+	mi "github.com/ha1tch/minty"
+)
+
+// Namespace is the SVG namespace URI.
+const Namespace = "http://www.w3.org/2000/svg"
+
+// element creates an SVG element that self-closes when it has no children.
+func element(tag string, args []interface{}) mi.Node {
+	node := mi.B.Element(tag, args...)
+	if el, ok := node.(*mi.Element); ok && len(el.Children) == 0 {
+		el.SelfClosing = true
+	}
+	return node
+}
+
+// SVG creates an <svg> root element, adding the SVG namespace unless an
+// xmlns attribute is given. The namespace makes the output valid as a
+// standalone .svg file and in email clients.
+func SVG(args ...interface{}) mi.Node {
+	node := element("svg", args)
+	if el, ok := node.(*mi.Element); ok && !el.Attributes.Has("xmlns") {
+		el.Attributes = append(mi.AttributeList{{Name: "xmlns", Value: Namespace}}, el.Attributes...)
+	}
+	return node
+}
+
+// Structure
+
+// G creates a <g> group element.
+func G(args ...interface{}) mi.Node { return element("g", args) }
+
+// Defs creates a <defs> element for reusable definitions.
+func Defs(args ...interface{}) mi.Node { return element("defs", args) }
+
+// Symbol creates a <symbol> element.
+func Symbol(args ...interface{}) mi.Node { return element("symbol", args) }
+
+// Use creates a <use> element referencing another element.
+func Use(args ...interface{}) mi.Node { return element("use", args) }
+
+// A creates an SVG <a> element.
+func A(args ...interface{}) mi.Node { return element("a", args) }
+
+// Switch creates a <switch> element.
+func Switch(args ...interface{}) mi.Node { return element("switch", args) }
+
+// View creates a <view> element.
+func View(args ...interface{}) mi.Node { return element("view", args) }
+
+// ForeignObject creates a <foreignObject> element for embedding HTML.
+func ForeignObject(args ...interface{}) mi.Node { return element("foreignObject", args) }
+
+// Image creates an <image> element.
+func Image(args ...interface{}) mi.Node { return element("image", args) }
+
+// Descriptive elements
+
+// Title creates a <title> element, the accessible name of its parent.
+func Title(args ...interface{}) mi.Node { return element("title", args) }
+
+// Desc creates a <desc> element, the accessible description of its parent.
+func Desc(args ...interface{}) mi.Node { return element("desc", args) }
+
+// Metadata creates a <metadata> element.
+func Metadata(args ...interface{}) mi.Node { return element("metadata", args) }
+
+// Shapes
+
+// Path creates a <path> element.
+func Path(args ...interface{}) mi.Node { return element("path", args) }
+
+// Rect creates a <rect> element.
+func Rect(args ...interface{}) mi.Node { return element("rect", args) }
+
+// Circle creates a <circle> element.
+func Circle(args ...interface{}) mi.Node { return element("circle", args) }
+
+// Ellipse creates an <ellipse> element.
+func Ellipse(args ...interface{}) mi.Node { return element("ellipse", args) }
+
+// Line creates a <line> element.
+func Line(args ...interface{}) mi.Node { return element("line", args) }
+
+// Polyline creates a <polyline> element.
+func Polyline(args ...interface{}) mi.Node { return element("polyline", args) }
+
+// Polygon creates a <polygon> element.
+func Polygon(args ...interface{}) mi.Node { return element("polygon", args) }
+
+// Text
+
+// Text creates a <text> element.
+func Text(args ...interface{}) mi.Node { return element("text", args) }
+
+// TSpan creates a <tspan> element.
+func TSpan(args ...interface{}) mi.Node { return element("tspan", args) }
+
+// TextPath creates a <textPath> element.
+func TextPath(args ...interface{}) mi.Node { return element("textPath", args) }
+
+// Paint servers, clipping and masking
+
+// LinearGradient creates a <linearGradient> element.
+func LinearGradient(args ...interface{}) mi.Node { return element("linearGradient", args) }
+
+// RadialGradient creates a <radialGradient> element.
+func RadialGradient(args ...interface{}) mi.Node { return element("radialGradient", args) }
+
+// Stop creates a gradient <stop> element.
+func Stop(args ...interface{}) mi.Node { return element("stop", args) }
+
+// Pattern creates a <pattern> element.
+func Pattern(args ...interface{}) mi.Node { return element("pattern", args) }
+
+// ClipPath creates a <clipPath> element.
+func ClipPath(args ...interface{}) mi.Node { return element("clipPath", args) }
+
+// Mask creates a <mask> element.
+func Mask(args ...interface{}) mi.Node { return element("mask", args) }
+
+// Marker creates a <marker> element.
+func Marker(args ...interface{}) mi.Node { return element("marker", args) }
+
+// Filters
+
+// Filter creates a <filter> element.
+func Filter(args ...interface{}) mi.Node { return element("filter", args) }
+
+// FeGaussianBlur creates an <feGaussianBlur> filter primitive.
+func FeGaussianBlur(args ...interface{}) mi.Node { return element("feGaussianBlur", args) }
+
+// FeOffset creates an <feOffset> filter primitive.
+func FeOffset(args ...interface{}) mi.Node { return element("feOffset", args) }
+
+// FeDropShadow creates an <feDropShadow> filter primitive.
+func FeDropShadow(args ...interface{}) mi.Node { return element("feDropShadow", args) }
+
+// FeBlend creates an <feBlend> filter primitive.
+func FeBlend(args ...interface{}) mi.Node { return element("feBlend", args) }
+
+// FeColorMatrix creates an <feColorMatrix> filter primitive.
+func FeColorMatrix(args ...interface{}) mi.Node { return element("feColorMatrix", args) }
+
+// FeComposite creates an <feComposite> filter primitive.
+func FeComposite(args ...interface{}) mi.Node { return element("feComposite", args) }
+
+// FeFlood creates an <feFlood> filter primitive.
+func FeFlood(args ...interface{}) mi.Node { return element("feFlood", args) }
+
+// FeMerge creates an <feMerge> filter primitive.
+func FeMerge(args ...interface{}) mi.Node { return element("feMerge", args) }
+
+// FeMergeNode creates an <feMergeNode> element.
+func FeMergeNode(args ...interface{}) mi.Node { return element("feMergeNode", args) }
+
+// Animation
+
+// Animate creates an <animate> element.
+func Animate(args ...interface{}) mi.Node { return element("animate", args) }
+
+// AnimateTransform creates an <animateTransform> element.
+func AnimateTransform(args ...interface{}) mi.Node { return element("animateTransform", args) }
+
+// AnimateMotion creates an <animateMotion> element.
+func AnimateMotion(args ...interface{}) mi.Node { return element("animateMotion", args) }
+
+// MPath creates an <mpath> element.
+func MPath(args ...interface{}) mi.Node { return element("mpath", args) }
+
+// Set creates a <set> element.
+func Set(args ...interface{}) mi.Node { return element("set", args) }
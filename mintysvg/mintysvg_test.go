@@ -0,0 +1,141 @@
+package mintysvg
+
+import (
+	"strings"
+	"testing"
+
+	mi "github.com/ha1tch/minty"
+)
+
+func render(t *testing.T, node mi.Node) string {
+	t.Helper()
+	var sb strings.Builder
+	if err := node.Render(&sb); err != nil {
+		t.Fatal(err)
+	}
+	return sb.String()
+}
+
+func TestEmptyElementsSelfClose(t *testing.T) {
+	got := render(t, G(
+		Path(D("M0 0L10 10")),
+		Circle(Cx(5), Cy(5.5), R("2")),
+	))
+	want := `<g><path d="M0 0L10 10" /><circle cx="5" cy="5.5" r="2" /></g>`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestElementsAcceptChildren(t *testing.T) {
+	got := render(t, Rect(Width(10), Height(10),
+		Title("Revenue"),
+		Animate(AttributeName("opacity"), Values("0", "1"), Dur("1s"), RepeatCount("indefinite")),
+	))
+	want := `<rect width="10" height="10"><title>Revenue</title>` +
+		`<animate attributeName="opacity" values="0;1" dur="1s" repeatCount="indefinite" /></rect>`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestSVGRootAddsNamespace(t *testing.T) {
+	got := render(t, SVG(ViewBox(0, 0, 24, 24), mi.Class("icon")))
+	want := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24" class="icon" />`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	custom := render(t, SVG(mi.Attr("xmlns", "urn:example")))
+	if strings.Count(custom, "xmlns=") != 1 {
+		t.Errorf("explicit xmlns duplicated: %s", custom)
+	}
+}
+
+func TestCamelCaseElementsAndAttributes(t *testing.T) {
+	got := render(t, Defs(
+		LinearGradient(mi.ID("fade"), GradientUnits("userSpaceOnUse"), GradientTransform(Rotate(90)),
+			Stop(Offset("0%"), StopColor("#fff"), StopOpacity(0.25)),
+		),
+		ClipPath(mi.ID("clip"), ClipPathUnits("objectBoundingBox")),
+		Marker(mi.ID("arrow"), MarkerWidth(6), MarkerHeight(6), RefX(3), RefY(3), Orient("auto")),
+		TextPath(Href("#curve"), StartOffset("50%")),
+	))
+	for _, want := range []string{
+		`<linearGradient id="fade" gradientUnits="userSpaceOnUse" gradientTransform="rotate(90)">`,
+		`<stop offset="0%" stop-color="#fff" stop-opacity="0.25" />`,
+		`</linearGradient>`,
+		`<clipPath id="clip" clipPathUnits="objectBoundingBox" />`,
+		`<marker id="arrow" markerWidth="6" markerHeight="6" refX="3" refY="3" orient="auto" />`,
+		`<textPath href="#curve" startOffset="50%" />`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in:\n%s", want, got)
+		}
+	}
+}
+
+func TestPresentationAttributes(t *testing.T) {
+	got := render(t, Polyline(
+		Points(0, 10, 5.5, 0, 10, 10),
+		StrokeDasharray(4, 2.5),
+		PreserveAspectRatio("none"),
+		ClipPathRef("clip"), MaskRef("m"), MarkerEnd("arrow"),
+		Fill(URL("fade")),
+	))
+	want := `<polyline points="0,10 5.5,0 10,10" stroke-dasharray="4 2.5" preserveAspectRatio="none" ` +
+		`clip-path="url(#clip)" mask="url(#m)" marker-end="url(#arrow)" fill="url(#fade)" />`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	base := Translate(10, 20)
+	rotated := base.Rotate(-45)
+	scaled := base.ScaleXY(2, 0.5)
+
+	if got := rotated.String(); got != "translate(10 20) rotate(-45)" {
+		t.Errorf("rotated = %q", got)
+	}
+	if got := scaled.String(); got != "translate(10 20) scale(2 0.5)" {
+		t.Errorf("scaled = %q, base must not be shared", got)
+	}
+	if got := RotateAround(90, 12, 12).SkewX(10).Matrix(1, 0, 0, 1, 0, 0).String(); got != "rotate(90 12 12) skewX(10) matrix(1 0 0 1 0 0)" {
+		t.Errorf("chain = %q", got)
+	}
+
+	got := render(t, G(Translate(0, 1.5).Scale(2)))
+	if got != `<g transform="translate(0 1.5) scale(2)" />` {
+		t.Errorf("transform attribute = %s", got)
+	}
+}
+
+func TestPathData(t *testing.T) {
+	p := MoveTo(0, 10).LineTo(10, 0).HLineTo(20).VLineTo(10).
+		QuadTo(15, 15, 10, 10).CurveTo(1, 2, 3, 4, 5, 6).
+		ArcTo(5, 5, 0, true, false, 0, 10).Close()
+	want := "M0 10 L10 0 H20 V10 Q15 15 10 10 C1 2 3 4 5 6 A5 5 0 1 0 0 10 Z"
+	if got := p.String(); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+	if got := render(t, Path(p)); got != `<path d="`+want+`" />` {
+		t.Errorf("path = %s", got)
+	}
+}
+
+func TestAnimationsOfURLAttributesAreSanitised(t *testing.T) {
+	got := render(t, G(
+		Set(AttributeName("href"), To("javascript:alert(1)")),
+		Animate(AttributeName("xlink:href"), Values("#a", "javascript:alert(1)"), From("data:text/html,x")),
+		Animate(AttributeName("opacity"), From("0"), To("1")),
+		Set(AttributeName("href"), To("#next")),
+	))
+	want := `<g><set attributeName="href" to="about:invalid#ZmintyZ" />` +
+		`<animate attributeName="xlink:href" values="#a;about:invalid#ZmintyZ" from="about:invalid#ZmintyZ" />` +
+		`<animate attributeName="opacity" from="0" to="1" />` +
+		`<set attributeName="href" to="#next" /></g>`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,170 @@
+package mintysvg
+
+import (
+	"strings"
+
+	mi "github.com/ha1tch/minty"
+)
+
+// Transform is a list of SVG transform functions. It is itself an
+// attribute that sets transform, so it can be passed straight to an
+// element:
+//
+//	msvg.G(msvg.Translate(40, 10).Rotate(-90), ...)
+//
+// Transforms are values; each method returns a new Transform.
+type Transform struct {
+	ops []string
+}
+
+func (t Transform) with(op string) Transform {
+	ops := make([]string, len(t.ops), len(t.ops)+1)
+	copy(ops, t.ops)
+	return Transform{ops: append(ops, op)}
+}
+
+// Translate starts a transform with translate(x, y).
+func Translate(x, y float64) Transform { return Transform{}.Translate(x, y) }
+
+// Rotate starts a transform with rotate(degrees).
+func Rotate(degrees float64) Transform { return Transform{}.Rotate(degrees) }
+
+// RotateAround starts a transform with rotate(degrees, cx, cy).
+func RotateAround(degrees, cx, cy float64) Transform {
+	return Transform{}.RotateAround(degrees, cx, cy)
+}
+
+// Scale starts a transform with a uniform scale(s).
+func Scale(s float64) Transform { return Transform{}.Scale(s) }
+
+// ScaleXY starts a transform with scale(sx, sy).
+func ScaleXY(sx, sy float64) Transform { return Transform{}.ScaleXY(sx, sy) }
+
+// SkewX starts a transform with skewX(degrees).
+func SkewX(degrees float64) Transform { return Transform{}.SkewX(degrees) }
+
+// SkewY starts a transform with skewY(degrees).
+func SkewY(degrees float64) Transform { return Transform{}.SkewY(degrees) }
+
+// Matrix starts a transform with matrix(a, b, c, d, e, f).
+func Matrix(a, b, c, d, e, f float64) Transform { return Transform{}.Matrix(a, b, c, d, e, f) }
+
+// Translate appends translate(x, y).
+func (t Transform) Translate(x, y float64) Transform {
+	return t.with("translate(" + Number(x) + " " + Number(y) + ")")
+}
+
+// Rotate appends rotate(degrees).
+func (t Transform) Rotate(degrees float64) Transform {
+	return t.with("rotate(" + Number(degrees) + ")")
+}
+
+// RotateAround appends rotate(degrees, cx, cy).
+func (t Transform) RotateAround(degrees, cx, cy float64) Transform {
+	return t.with("rotate(" + numbers([]float64{degrees, cx, cy}) + ")")
+}
+
+// Scale appends a uniform scale(s).
+func (t Transform) Scale(s float64) Transform {
+	return t.with("scale(" + Number(s) + ")")
+}
+
+// ScaleXY appends scale(sx, sy).
+func (t Transform) ScaleXY(sx, sy float64) Transform {
+	return t.with("scale(" + Number(sx) + " " + Number(sy) + ")")
+}
+
+// SkewX appends skewX(degrees).
+func (t Transform) SkewX(degrees float64) Transform {
+	return t.with("skewX(" + Number(degrees) + ")")
+}
+
+// SkewY appends skewY(degrees).
+func (t Transform) SkewY(degrees float64) Transform {
+	return t.with("skewY(" + Number(degrees) + ")")
+}
+
+// Matrix appends matrix(a, b, c, d, e, f).
+func (t Transform) Matrix(a, b, c, d, e, f float64) Transform {
+	return t.with("matrix(" + numbers([]float64{a, b, c, d, e, f}) + ")")
+}
+
+// String returns the transform list, e.g. "translate(10 20) rotate(45)".
+func (t Transform) String() string {
+	return strings.Join(t.ops, " ")
+}
+
+// Apply sets the transform attribute on an element.
+func (t Transform) Apply(el *mi.Element) {
+	if len(t.ops) > 0 {
+		el.Attributes.Set("transform", t.String())
+	}
+}
+
+// PathData builds the d attribute of a path:
+//
+//	msvg.D(msvg.MoveTo(0, 10).LineTo(10, 0).LineTo(20, 10).Close().String())
+//
+// Uppercase commands use absolute coordinates. Like Transform, PathData
+// is a value and each method returns a new PathData.
+type PathData struct {
+	cmds []string
+}
+
+func (p PathData) with(cmd string, values ...float64) PathData {
+	cmds := make([]string, len(p.cmds), len(p.cmds)+1)
+	copy(cmds, p.cmds)
+	if len(values) > 0 {
+		cmd += numbers(values)
+	}
+	return PathData{cmds: append(cmds, cmd)}
+}
+
+// MoveTo starts path data with an absolute move.
+func MoveTo(x, y float64) PathData { return PathData{}.MoveTo(x, y) }
+
+// MoveTo appends an absolute move (M).
+func (p PathData) MoveTo(x, y float64) PathData { return p.with("M", x, y) }
+
+// LineTo appends an absolute line (L).
+func (p PathData) LineTo(x, y float64) PathData { return p.with("L", x, y) }
+
+// HLineTo appends an absolute horizontal line (H).
+func (p PathData) HLineTo(x float64) PathData { return p.with("H", x) }
+
+// VLineTo appends an absolute vertical line (V).
+func (p PathData) VLineTo(y float64) PathData { return p.with("V", y) }
+
+// CurveTo appends a cubic Bézier curve (C).
+func (p PathData) CurveTo(x1, y1, x2, y2, x, y float64) PathData {
+	return p.with("C", x1, y1, x2, y2, x, y)
+}
+
+// QuadTo appends a quadratic Bézier curve (Q).
+func (p PathData) QuadTo(x1, y1, x, y float64) PathData { return p.with("Q", x1, y1, x, y) }
+
+// ArcTo appends an elliptical arc (A).
+func (p PathData) ArcTo(rx, ry, rotation float64, largeArc, sweep bool, x, y float64) PathData {
+	return p.with("A", rx, ry, rotation, flag(largeArc), flag(sweep), x, y)
+}
+
+// Close appends a close-path command (Z).
+func (p PathData) Close() PathData { return p.with("Z") }
+
+// String returns the path data.
+func (p PathData) String() string {
+	return strings.Join(p.cmds, " ")
+}
+
+// Apply sets the d attribute on an element, so PathData can be passed to
+// Path directly.
+func (p PathData) Apply(el *mi.Element) {
+	el.Attributes.Set("d", p.String())
+}
+
+func flag(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,136 @@
+package mintytest
+
+import (
+	"flag"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	mi "github.com/ha1tch/minty"
+)
+
+// Golden files
+//
+// MatchGolden compares a document with testdata/<name>.golden. Both sides
+// are normalised first: one element per line, indented by depth, with
+// attributes sorted by name and whitespace-only text dropped, so snapshots
+// stay stable and diffs point at the element that changed.
+//
+// Run the tests with MINTYTEST_UPDATE=1, or pass -mintytest.update to a
+// package that imports mintytest, to write the current output as the golden
+// file:
+//
+//	MINTYTEST_UPDATE=1 go test ./... -run TestSignupPage
+//	go test ./web -run TestSignupPage -mintytest.update
+
+var update = flag.Bool("mintytest.update", false, "update mintytest golden files")
+
+// updating reports whether golden files should be rewritten.
+func updating() bool {
+	return *update || os.Getenv("MINTYTEST_UPDATE") != ""
+}
+
+// GoldenDir is the directory golden files are read from and written to.
+var GoldenDir = "testdata"
+
+// MatchGolden fails the test if the normalised document differs from the
+// golden file name, or rewrites the file when updating is enabled.
+func (d *Document) MatchGolden(t testing.TB, name string) {
+	t.Helper()
+	got := Normalize(d.Root)
+	path := filepath.Join(GoldenDir, name+".golden")
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mintytest: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("mintytest: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("mintytest: %v (run with MINTYTEST_UPDATE=1 to create it)", err)
+	}
+	if want := string(data); got != want {
+		t.Errorf("mintytest: output differs from %s (run with MINTYTEST_UPDATE=1 to accept):\n%s", path, diff(want, got))
+	}
+}
+
+// Golden renders template and compares it with the golden file name.
+func Golden(t testing.TB, name string, template mi.H) {
+	t.Helper()
+	Render(t, template).MatchGolden(t, name)
+}
+
+// Normalize returns the canonical golden-file form of a node tree.
+func Normalize(node mi.Node) string {
+	var sb strings.Builder
+	normalize(&sb, node, 0)
+	return sb.String()
+}
+
+func normalize(sb *strings.Builder, node mi.Node, depth int) {
+	indent := strings.Repeat("  ", depth)
+	switch n := node.(type) {
+	case *mi.Fragment:
+		for _, child := range n.Children {
+			normalize(sb, child, depth)
+		}
+	case *mi.Element:
+		attrs := append(mi.AttributeList(nil), n.Attributes...)
+		sort.SliceStable(attrs, func(i, j int) bool { return attrs[i].Name < attrs[j].Name })
+
+		sb.WriteString(indent + "<" + n.Tag)
+		for _, a := range attrs {
+			sb.WriteString(" " + a.Name + `="` + html.EscapeString(a.Value) + `"`)
+		}
+		if n.SelfClosing {
+			sb.WriteString(" />\n")
+			return
+		}
+		sb.WriteString(">\n")
+		for _, child := range n.Children {
+			normalize(sb, child, depth+1)
+		}
+		sb.WriteString(indent + "</" + n.Tag + ">\n")
+	case *mi.TextNode:
+		if text := strings.TrimSpace(n.Content); text != "" {
+			sb.WriteString(indent + html.EscapeString(text) + "\n")
+		}
+	case *mi.RawNode:
+		if raw := strings.TrimSpace(n.Content); raw != "" {
+			sb.WriteString(indent + raw + "\n")
+		}
+	}
+}
+
+// diff reports the first differing line with a little context.
+func diff(want, got string) string {
+	w := strings.Split(want, "\n")
+	g := strings.Split(got, "\n")
+	i := 0
+	for i < len(w) && i < len(g) && w[i] == g[i] {
+		i++
+	}
+	from := i - 3
+	if from < 0 {
+		from = 0
+	}
+	var sb strings.Builder
+	for j := from; j < i; j++ {
+		sb.WriteString("  " + w[j] + "\n")
+	}
+	for j := i; j < len(w) && j < i+3; j++ {
+		sb.WriteString("- " + w[j] + "\n")
+	}
+	for j := i; j < len(g) && j < i+3; j++ {
+		sb.WriteString("+ " + g[j] + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,54 @@
+package mintytest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Response is a recorded handler response with its body parsed.
+type Response struct {
+	*Document
+	Recorder *httptest.ResponseRecorder
+}
+
+// Status returns the response status code.
+func (r *Response) Status() int {
+	return r.Recorder.Code
+}
+
+// Header returns the response headers.
+func (r *Response) Header() http.Header {
+	return r.Recorder.Header()
+}
+
+// Serve sends req to h and parses the response body.
+func Serve(t testing.TB, h http.Handler, req *http.Request) *Response {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return &Response{Document: Parse(t, rec.Body.String()), Recorder: rec}
+}
+
+// Get sends a plain GET request for target to h.
+func Get(t testing.TB, h http.Handler, target string) *Response {
+	t.Helper()
+	return Serve(t, h, httptest.NewRequest(http.MethodGet, target, nil))
+}
+
+// HTMXGet sends a GET request for target to h with the HX-Request header
+// set, as htmx does.
+func HTMXGet(t testing.TB, h http.Handler, target string) *Response {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("HX-Request", "true")
+	return Serve(t, h, req)
+}
+
+// HTMXPair requests target twice, without and with the HX-Request header,
+// and returns both responses. Use it to check that an HTMXHandler serves
+// the full page to browsers and only the fragment to htmx.
+func HTMXPair(t testing.TB, h http.Handler, target string) (full, fragment *Response) {
+	t.Helper()
+	return Get(t, h, target), HTMXGet(t, h, target)
+}
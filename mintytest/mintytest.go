@@ -0,0 +1,153 @@
+// Package mintytest provides structural assertions, golden files and HTTP
+// helpers for testing minty templates and handlers.
+//
+// Instead of searching rendered HTML for substrings, render the template,
+// parse it back into a tree and ask questions with CSS selectors:
+//
+//	doc := mtest.Render(t, SignupForm())
+//	doc.HasElement(t, "form#signup[method=post]")
+//	if got := doc.AttrOf("input[name=email]", "type"); got != "email" {
+//	    t.Errorf("email input type = %q", got)
+//	}
+//	doc.MatchGolden(t, "signup")
+//	doc.Accessible(t)
+//
+// Import with: import mtest "github.com/ha1tch/minty/mintytest"
+package mintytest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	mi "github.com/ha1tch/minty"
+	ma11y "github.com/ha1tch/minty/mintya11y"
+)
+
+// Document is rendered HTML parsed back into a node tree.
+type Document struct {
+	// HTML is the markup as rendered.
+	HTML string
+
+	// Root holds the parsed top-level nodes.
+	Root *mi.Fragment
+}
+
+// Render renders template in strict mode and parses the result, failing
+// the test on misused builder arguments (see mi.SetStrict).
+func Render(t testing.TB, template mi.H) *Document {
+	t.Helper()
+	var sb strings.Builder
+	if err := mi.RenderWithContext(mi.WithStrict(context.Background(), true), template, &sb); err != nil {
+		t.Fatalf("mintytest: render failed: %v", err)
+	}
+	return Parse(t, sb.String())
+}
+
+// Parse parses markup, such as a response body, into a Document.
+func Parse(t testing.TB, html string) *Document {
+	t.Helper()
+	root, err := mi.ParseHTML(html)
+	if err != nil {
+		t.Fatalf("mintytest: cannot parse HTML: %v\n%s", err, html)
+	}
+	return &Document{HTML: html, Root: root}
+}
+
+// FindAll returns the elements matching selector in document order.
+func (d *Document) FindAll(selector string) []*mi.Element {
+	return mi.FindAll(d.Root, selector)
+}
+
+// Find returns the first element matching selector, or nil.
+func (d *Document) Find(selector string) *mi.Element {
+	return mi.FindFirst(d.Root, selector)
+}
+
+// HasElement fails the test unless an element matches selector, and
+// returns the first match.
+func (d *Document) HasElement(t testing.TB, selector string) *mi.Element {
+	t.Helper()
+	el := d.Find(selector)
+	if el == nil {
+		t.Errorf("mintytest: no element matches %q in:\n%s", selector, d.HTML)
+	}
+	return el
+}
+
+// NoElement fails the test if any element matches selector.
+func (d *Document) NoElement(t testing.TB, selector string) {
+	t.Helper()
+	if n := d.CountOf(selector); n > 0 {
+		t.Errorf("mintytest: expected no element to match %q, found %d in:\n%s", selector, n, d.HTML)
+	}
+}
+
+// CountOf returns the number of elements matching selector.
+func (d *Document) CountOf(selector string) int {
+	return len(d.FindAll(selector))
+}
+
+// TextOf returns the text of the first element matching selector with
+// runs of whitespace collapsed, or "" if none matches.
+func (d *Document) TextOf(selector string) string {
+	el := d.Find(selector)
+	if el == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(mi.TextContent(el)), " ")
+}
+
+// AttrOf returns an attribute of the first element matching selector, or
+// "" if there is no match or the attribute is absent.
+func (d *Document) AttrOf(selector, name string) string {
+	el := d.Find(selector)
+	if el == nil {
+		return ""
+	}
+	value, _ := el.GetAttribute(name)
+	return value
+}
+
+// HasText fails the test unless the first element matching selector has
+// the given text, compared with whitespace collapsed.
+func (d *Document) HasText(t testing.TB, selector, want string) {
+	t.Helper()
+	if d.HasElement(t, selector) == nil {
+		return
+	}
+	want = strings.Join(strings.Fields(want), " ")
+	if got := d.TextOf(selector); got != want {
+		t.Errorf("mintytest: text of %q = %q, want %q", selector, got, want)
+	}
+}
+
+// HasAttr fails the test unless the first element matching selector has
+// the attribute with the given value.
+func (d *Document) HasAttr(t testing.TB, selector, name, want string) {
+	t.Helper()
+	el := d.HasElement(t, selector)
+	if el == nil {
+		return
+	}
+	got, ok := el.GetAttribute(name)
+	if !ok {
+		t.Errorf("mintytest: %q has no %s attribute", selector, name)
+	} else if got != want {
+		t.Errorf("mintytest: %s of %q = %q, want %q", name, selector, got, want)
+	}
+}
+
+// Accessible fails the test for each accessibility violation with Error
+// severity in the document and logs the warnings. Options select and
+// configure the rules (see mintya11y).
+func (d *Document) Accessible(t testing.TB, opts ...ma11y.Option) {
+	t.Helper()
+	for _, v := range ma11y.Check(d.Root, opts...) {
+		if v.Severity == ma11y.Error {
+			t.Errorf("mintytest: %s", v)
+		} else {
+			t.Logf("mintytest: %s", v)
+		}
+	}
+}
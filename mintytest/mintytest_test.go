@@ -0,0 +1,184 @@
+package mintytest
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	mi "github.com/ha1tch/minty"
+	ma11y "github.com/ha1tch/minty/mintya11y"
+)
+
+func signupForm(b *mi.Builder) mi.Node {
+	return b.Form(mi.ID("signup"), mi.Method("post"), mi.Action("/signup"),
+		b.Label(mi.For("email"), "Email"),
+		b.Input(mi.Type("email"), mi.Name("email"), mi.ID("email"), mi.Required()),
+		b.Ul(mi.Class("errors"),
+			b.Li("Email is required"),
+			b.Li("Email is  invalid"),
+		),
+		b.Button(mi.Type("submit"), mi.Class("btn primary"), "Sign up"),
+	)
+}
+
+func TestAssertions(t *testing.T) {
+	doc := Render(t, signupForm)
+
+	doc.HasElement(t, "form#signup[method=post]")
+	doc.NoElement(t, "form[method=get]")
+	doc.HasText(t, "button.primary", "Sign up")
+	doc.HasAttr(t, "input[name=email]", "type", "email")
+
+	if got := doc.CountOf("ul.errors > li"); got != 2 {
+		t.Errorf("CountOf = %d, want 2", got)
+	}
+	if got := doc.TextOf("ul.errors li"); got != "Email is required" {
+		t.Errorf("TextOf = %q", got)
+	}
+	if got := doc.AttrOf("label", "for"); got != "email" {
+		t.Errorf("AttrOf = %q", got)
+	}
+	if got := doc.AttrOf("input", "required"); got != "required" {
+		t.Errorf("AttrOf(required) = %q", got)
+	}
+	if doc.AttrOf("select", "name") != "" || doc.TextOf("select") != "" {
+		t.Error("missing elements should report empty strings")
+	}
+}
+
+func TestAssertionFailures(t *testing.T) {
+	doc := Render(t, signupForm)
+
+	rec := &recordingTB{TB: t}
+	doc.HasElement(rec, "form#login")
+	doc.NoElement(rec, "input")
+	doc.HasText(rec, "button", "Register")
+	doc.HasAttr(rec, "input", "type", "text")
+	doc.HasAttr(rec, "input", "placeholder", "")
+
+	if len(rec.errors) != 5 {
+		t.Fatalf("got %d failures, want 5: %q", len(rec.errors), rec.errors)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	a, err := mi.ParseHTML(`<p class="x" id="a">  Hi  <b>there</b></p>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := mi.ParseHTML("<p id=\"a\" class=\"x\">\n  Hi\n  <b>there</b>\n</p>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Normalize(a) != Normalize(b) {
+		t.Errorf("attribute order or whitespace changed the normal form:\n%s\n%s", Normalize(a), Normalize(b))
+	}
+	want := "<p class=\"x\" id=\"a\">\n  Hi\n  <b>\n    there\n  </b>\n</p>\n"
+	if got := Normalize(a); got != want {
+		t.Errorf("Normalize = %q, want %q", got, want)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	Golden(t, "signup", signupForm)
+}
+
+func TestGoldenMismatch(t *testing.T) {
+	if updating() {
+		t.Skip("updating golden files")
+	}
+	rec := &recordingTB{TB: t}
+	Golden(rec, "signup", func(b *mi.Builder) mi.Node {
+		return b.Form(mi.ID("signup"))
+	})
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "+ </form>") {
+		t.Errorf("expected a diff report, got %q", rec.errors)
+	}
+}
+
+func TestGoldenUpdateFromEnvironment(t *testing.T) {
+	dir := GoldenDir
+	GoldenDir = t.TempDir()
+	t.Cleanup(func() { GoldenDir = dir })
+	t.Setenv("MINTYTEST_UPDATE", "1")
+
+	Golden(t, "created", signupForm)
+	if _, err := os.Stat(filepath.Join(GoldenDir, "created.golden")); err != nil {
+		t.Errorf("golden file not written: %v", err)
+	}
+}
+
+func TestHTMXPair(t *testing.T) {
+	h := mi.HTMXHandler(
+		func(b *mi.Builder) mi.Node {
+			return mi.Document("Signup", nil, b.Body(b.Main(signupForm(b))))(b)
+		},
+		signupForm,
+	)
+
+	full, fragment := HTMXPair(t, h, "/signup")
+	if full.Status() != http.StatusOK || fragment.Status() != http.StatusOK {
+		t.Fatalf("status = %d, %d", full.Status(), fragment.Status())
+	}
+	full.HasElement(t, "html > body main > form#signup")
+	fragment.HasElement(t, "form#signup")
+	fragment.NoElement(t, "html")
+	if ct := fragment.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestRenderIsStrict(t *testing.T) {
+	rec := &recordingTB{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Render(rec, func(b *mi.Builder) mi.Node {
+			return b.Div(mi.ID("total"), struct{ Amount int }{42})
+		})
+	}()
+	<-done
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "div#total: argument of type struct") {
+		t.Errorf("expected a strict mode failure, got %q", rec.errors)
+	}
+}
+
+func TestAccessible(t *testing.T) {
+	Render(t, signupForm).Accessible(t)
+
+	rec := &recordingTB{TB: t}
+	doc := Render(t, func(b *mi.Builder) mi.Node {
+		return b.Main(b.H1("Shop"), b.H3("Offers"), b.Img(mi.Src("/sale.png")), b.Button(mi.Class("close")))
+	})
+	doc.Accessible(rec)
+	if len(rec.errors) != 2 || !strings.Contains(rec.errors[0], "main > img: image has no alt attribute") {
+		t.Errorf("expected image and button errors, got %q", rec.errors)
+	}
+
+	rec = &recordingTB{TB: t}
+	doc.Accessible(rec, ma11y.Disable(ma11y.RuleImageAlt, ma11y.RuleButtonName))
+	if len(rec.errors) != 0 {
+		t.Errorf("disabled rules reported %q", rec.errors)
+	}
+}
+
+// recordingTB captures failures instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
@@ -4,6 +4,7 @@ package mintyui
 
 import (
 	"fmt"
+	"strings"
 
 	mi "github.com/ha1tch/minty"
 	"github.com/ha1tch/minty/mintyex"
@@ -201,6 +202,28 @@ func ErrorMessage(message string) mi.H {
 	}
 }
 
+// ErrorViews returns a handler option that renders the error responses of
+// mi.Handle and the other minty handlers with theme: a themed card on a
+// full page, and ErrorMessage as an alert for htmx fragments.
+//
+//	mux.Handle("/orders", mi.Handle(ordersPage, mui.ErrorViews(theme)))
+func ErrorViews(theme Theme) mi.HandlerOption {
+	return mi.ErrorViews(
+		func(e *mi.HTTPError) mi.H {
+			return func(b *mi.Builder) mi.Node {
+				return mi.Document(e.Title(), nil, b.Body(
+					theme.Container(theme.Card(e.Title(), ErrorMessage(e.Text())))(b),
+				))(b)
+			}
+		},
+		func(e *mi.HTTPError) mi.H {
+			return func(b *mi.Builder) mi.Node {
+				return b.Div(mi.Role("alert"), ErrorMessage(e.Text())(b))
+			}
+		},
+	)
+}
+
 // SuccessMessage creates a success message component
 func SuccessMessage(message string) mi.H {
 	return func(b *mi.Builder) mi.Node {
@@ -334,16 +357,48 @@ func FormGroup(label string, required bool, input mi.H, help string, errors []st
 			labelText += " *"
 		}
 		
+		// Link the label, help and errors to the first form control in
+		// the input, giving it an id if it has none
+		inputNode := input(b)
+		field := mi.FindFirst(inputNode, "input, select, textarea")
+		labelAttrs := []interface{}{mi.Style(labelStyle)}
+		var fieldID string
+		if field != nil {
+			fieldID, _ = field.GetAttribute("id")
+			if fieldID == "" {
+				name, _ := field.GetAttribute("name")
+				fieldID = b.UID(name)
+				field.SetAttribute("id", fieldID)
+			}
+			labelAttrs = append(labelAttrs, mi.For(fieldID))
+		}
+		var describedBy []string
+		describe := func(suffix string) interface{} {
+			if field == nil {
+				return nil
+			}
+			id := b.UID(fieldID + suffix)
+			describedBy = append(describedBy, id)
+			return mi.ID(id)
+		}
+		
 		var children []mi.Node
-		children = append(children, b.Label(mi.Style(labelStyle), labelText))
-		children = append(children, input(b))
+		children = append(children, b.Label(append(labelAttrs, labelText)...))
+		children = append(children, inputNode)
 		
 		if help != "" {
-			children = append(children, b.P(mi.Style(helpStyle), help))
+			children = append(children, b.P(mi.Style(helpStyle), describe("-help"), help))
 		}
 		
 		for _, err := range errors {
-			children = append(children, b.P(mi.Style(errorStyle), err))
+			children = append(children, b.P(mi.Style(errorStyle), describe("-error"), err))
+		}
+		
+		if field != nil && len(describedBy) > 0 {
+			mi.AriaDescribedby(strings.Join(describedBy, " ")).Apply(field)
+			if len(errors) > 0 {
+				field.SetAttribute("aria-invalid", "true")
+			}
 		}
 		
 		return b.Div(mi.Style(groupStyle),
@@ -0,0 +1,414 @@
+package minty
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// HTML parsing
+//
+// ParseHTML turns markup back into a node tree: elements become *Element,
+// text becomes *TextNode, and doctypes, comments and the content of script
+// and style elements become *RawNode. It is a small, forgiving parser meant
+// for markup minty produces and for hand-written templates, not a full
+// HTML5 tree builder: unknown end tags are ignored, unclosed elements are
+// closed at the end of their parent, and only the common implied end tags
+// (p, li, option, tr, td, th, dt, dd) are handled.
+//
+// Tag and attribute names are lowercased, except inside <svg> and <math>,
+// where the camelCase names of the HTML spec's adjustment tables (viewBox,
+// linearGradient, definitionURL...) are restored so the tree matches the
+// markup minty renders.
+
+// voidElements never have content or an end tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawTextElements hold text that is not parsed as markup.
+var rawTextElements = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true,
+}
+
+// impliedEnd lists elements closed by the start of a sibling of the given
+// tags.
+var impliedEnd = map[string][]string{
+	"li":     {"li"},
+	"option": {"option"},
+	"dt":     {"dt", "dd"},
+	"dd":     {"dt", "dd"},
+	"tr":     {"tr"},
+	"td":     {"td", "th", "tr"},
+	"th":     {"td", "th", "tr"},
+	"p": {"p", "div", "ul", "ol", "dl", "table", "section", "article", "aside",
+		"header", "footer", "nav", "form", "h1", "h2", "h3", "h4", "h5", "h6",
+		"pre", "blockquote", "hr", "main", "figure"},
+}
+
+// svgTagCase restores the case of SVG element names.
+var svgTagCase = map[string]string{
+	"altglyph": "altGlyph", "altglyphdef": "altGlyphDef", "altglyphitem": "altGlyphItem",
+	"animatecolor": "animateColor", "animatemotion": "animateMotion",
+	"animatetransform": "animateTransform", "clippath": "clipPath",
+	"feblend": "feBlend", "fecolormatrix": "feColorMatrix",
+	"fecomponenttransfer": "feComponentTransfer", "fecomposite": "feComposite",
+	"feconvolvematrix": "feConvolveMatrix", "fediffuselighting": "feDiffuseLighting",
+	"fedisplacementmap": "feDisplacementMap", "fedistantlight": "feDistantLight",
+	"fedropshadow": "feDropShadow", "feflood": "feFlood", "fefunca": "feFuncA",
+	"fefuncb": "feFuncB", "fefuncg": "feFuncG", "fefuncr": "feFuncR",
+	"fegaussianblur": "feGaussianBlur", "feimage": "feImage", "femerge": "feMerge",
+	"femergenode": "feMergeNode", "femorphology": "feMorphology", "feoffset": "feOffset",
+	"fepointlight": "fePointLight", "fespecularlighting": "feSpecularLighting",
+	"fespotlight": "feSpotLight", "fetile": "feTile", "feturbulence": "feTurbulence",
+	"foreignobject": "foreignObject", "glyphref": "glyphRef",
+	"lineargradient": "linearGradient", "radialgradient": "radialGradient",
+	"textpath": "textPath",
+}
+
+// svgAttrCase restores the case of SVG attribute names.
+var svgAttrCase = map[string]string{
+	"attributename": "attributeName", "attributetype": "attributeType",
+	"basefrequency": "baseFrequency", "baseprofile": "baseProfile", "calcmode": "calcMode",
+	"clippathunits": "clipPathUnits", "diffuseconstant": "diffuseConstant",
+	"edgemode": "edgeMode", "filterunits": "filterUnits", "glyphref": "glyphRef",
+	"gradienttransform": "gradientTransform", "gradientunits": "gradientUnits",
+	"kernelmatrix": "kernelMatrix", "kernelunitlength": "kernelUnitLength",
+	"keypoints": "keyPoints", "keysplines": "keySplines", "keytimes": "keyTimes",
+	"lengthadjust": "lengthAdjust", "limitingconeangle": "limitingConeAngle",
+	"markerheight": "markerHeight", "markerunits": "markerUnits", "markerwidth": "markerWidth",
+	"maskcontentunits": "maskContentUnits", "maskunits": "maskUnits",
+	"numoctaves": "numOctaves", "pathlength": "pathLength",
+	"patterncontentunits": "patternContentUnits", "patterntransform": "patternTransform",
+	"patternunits": "patternUnits", "pointsatx": "pointsAtX", "pointsaty": "pointsAtY",
+	"pointsatz": "pointsAtZ", "preservealpha": "preserveAlpha",
+	"preserveaspectratio": "preserveAspectRatio", "primitiveunits": "primitiveUnits",
+	"refx": "refX", "refy": "refY", "repeatcount": "repeatCount", "repeatdur": "repeatDur",
+	"requiredextensions": "requiredExtensions", "requiredfeatures": "requiredFeatures",
+	"specularconstant": "specularConstant", "specularexponent": "specularExponent",
+	"spreadmethod": "spreadMethod", "startoffset": "startOffset",
+	"stddeviation": "stdDeviation", "stitchtiles": "stitchTiles",
+	"surfacescale": "surfaceScale", "systemlanguage": "systemLanguage",
+	"tablevalues": "tableValues", "targetx": "targetX", "targety": "targetY",
+	"textlength": "textLength", "viewbox": "viewBox", "viewtarget": "viewTarget",
+	"xchannelselector": "xChannelSelector", "ychannelselector": "yChannelSelector",
+	"zoomandpan": "zoomAndPan",
+}
+
+// mathAttrCase restores the case of MathML attribute names.
+var mathAttrCase = map[string]string{
+	"definitionurl": "definitionURL",
+}
+
+// namespace returns "svg" or "math" for an element named tag opened at the
+// current position, or "" for HTML. The content of foreignObject is HTML
+// again.
+func (p *htmlParser) namespace(tag string) string {
+	switch tag {
+	case "svg", "math":
+		return tag
+	}
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		switch p.stack[i].Tag {
+		case "foreignObject":
+			return ""
+		case "svg", "math":
+			return p.stack[i].Tag
+		}
+	}
+	return ""
+}
+
+// adjustCase restores the case of a lowercased name in foreign content.
+func adjustCase(table map[string]string, name string) string {
+	if adjusted, ok := table[name]; ok {
+		return adjusted
+	}
+	return name
+}
+
+// ParseError reports markup ParseHTML cannot make sense of.
+type ParseError struct {
+	Offset int
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("minty: parse error at offset %d: %s", e.Offset, e.Reason)
+}
+
+// ParseHTML parses markup into a fragment holding its top-level nodes.
+func ParseHTML(src string) (*Fragment, error) {
+	p := &htmlParser{src: src}
+	root := &Fragment{}
+	if err := p.parse(root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+type htmlParser struct {
+	src   string
+	pos   int
+	stack []*Element
+}
+
+// appendChild adds a node to the innermost open element or the root.
+func (p *htmlParser) appendChild(root *Fragment, n Node) {
+	if len(p.stack) == 0 {
+		root.Children = append(root.Children, n)
+		return
+	}
+	top := p.stack[len(p.stack)-1]
+	top.Children = append(top.Children, n)
+}
+
+func (p *htmlParser) parse(root *Fragment) error {
+	for p.pos < len(p.src) {
+		lt := strings.IndexByte(p.src[p.pos:], '<')
+		if lt < 0 {
+			p.text(root, p.src[p.pos:])
+			p.pos = len(p.src)
+			break
+		}
+		if lt > 0 {
+			p.text(root, p.src[p.pos:p.pos+lt])
+			p.pos += lt
+		}
+
+		rest := p.src[p.pos:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				return &ParseError{Offset: p.pos, Reason: "unterminated comment"}
+			}
+			p.appendChild(root, &RawNode{Content: rest[:4+end+3]})
+			p.pos += 4 + end + 3
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return &ParseError{Offset: p.pos, Reason: "unterminated declaration"}
+			}
+			p.appendChild(root, &RawNode{Content: rest[:end+1]})
+			p.pos += end + 1
+		case strings.HasPrefix(rest, "</"):
+			if err := p.endTag(); err != nil {
+				return err
+			}
+		case len(rest) > 1 && isASCIILetter(rest[1]):
+			if err := p.startTag(root); err != nil {
+				return err
+			}
+		default:
+			// A lone '<' is text
+			p.text(root, "<")
+			p.pos++
+		}
+	}
+	p.stack = nil
+	return nil
+}
+
+func (p *htmlParser) text(root *Fragment, s string) {
+	if s != "" {
+		p.appendChild(root, &TextNode{Content: html.UnescapeString(s)})
+	}
+}
+
+func (p *htmlParser) endTag() error {
+	end := strings.IndexByte(p.src[p.pos:], '>')
+	if end < 0 {
+		return &ParseError{Offset: p.pos, Reason: "unterminated end tag"}
+	}
+	name := strings.ToLower(strings.TrimSpace(p.src[p.pos+2 : p.pos+end]))
+	p.pos += end + 1
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		if strings.EqualFold(p.stack[i].Tag, name) {
+			p.stack = p.stack[:i]
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *htmlParser) startTag(root *Fragment) error {
+	start := p.pos
+	p.pos++ // '<'
+	name := strings.ToLower(p.name())
+	ns := p.namespace(name)
+	attrCase := map[string]string(nil)
+	switch ns {
+	case "svg":
+		name = adjustCase(svgTagCase, name)
+		attrCase = svgAttrCase
+	case "math":
+		attrCase = mathAttrCase
+	}
+	el := &Element{Tag: name}
+
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return &ParseError{Offset: start, Reason: "unterminated start tag <" + name}
+		}
+		if p.src[p.pos] == '>' {
+			p.pos++
+			break
+		}
+		if strings.HasPrefix(p.src[p.pos:], "/>") {
+			p.pos += 2
+			el.SelfClosing = true
+			break
+		}
+		if p.src[p.pos] == '/' {
+			p.pos++
+			continue
+		}
+		attrName := p.name()
+		if attrName == "" {
+			return &ParseError{Offset: p.pos, Reason: fmt.Sprintf("unexpected %q in <%s>", p.src[p.pos], name)}
+		}
+		value := ""
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == '=' {
+			p.pos++
+			p.skipSpace()
+			v, err := p.attrValue()
+			if err != nil {
+				return err
+			}
+			value = html.UnescapeString(v)
+		}
+		// The first occurrence of a repeated attribute wins, as in browsers
+		if attrName = adjustCase(attrCase, strings.ToLower(attrName)); !el.Attributes.Has(attrName) {
+			el.Attributes.Set(attrName, value)
+		}
+	}
+
+	// Close elements whose end tag is implied by this one
+	for len(p.stack) > 0 {
+		top := p.stack[len(p.stack)-1]
+		if !containsString(impliedEnd[top.Tag], name) {
+			break
+		}
+		p.stack = p.stack[:len(p.stack)-1]
+	}
+
+	p.appendChild(root, el)
+	if voidElements[name] {
+		el.SelfClosing = true
+		return nil
+	}
+	if el.SelfClosing {
+		return nil
+	}
+
+	if rawTextElements[name] && (ns == "" || name == "script" || name == "style") {
+		closing := rawTextEnd(p.src[p.pos:], name)
+		content := p.src[p.pos : p.pos+closing]
+		if content != "" {
+			if name == "script" || name == "style" {
+				el.Children = append(el.Children, &RawNode{Content: content})
+			} else {
+				el.Children = append(el.Children, &TextNode{Content: html.UnescapeString(content)})
+			}
+		}
+		p.pos += closing
+		if p.pos < len(p.src) {
+			if end := strings.IndexByte(p.src[p.pos:], '>'); end >= 0 {
+				p.pos += end + 1
+			} else {
+				p.pos = len(p.src)
+			}
+		}
+		return nil
+	}
+
+	p.stack = append(p.stack, el)
+	return nil
+}
+
+// name reads a tag or attribute name.
+func (p *htmlParser) name() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c <= ' ' || c == '>' || c == '/' || c == '=' || c == '"' || c == '\'' || c == '<' {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *htmlParser) attrValue() (string, error) {
+	if p.pos >= len(p.src) {
+		return "", &ParseError{Offset: p.pos, Reason: "missing attribute value"}
+	}
+	if q := p.src[p.pos]; q == '"' || q == '\'' {
+		end := strings.IndexByte(p.src[p.pos+1:], q)
+		if end < 0 {
+			return "", &ParseError{Offset: p.pos, Reason: "unterminated attribute value"}
+		}
+		v := p.src[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return v, nil
+	}
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] > ' ' && p.src[p.pos] != '>' {
+		p.pos++
+	}
+	return p.src[start:p.pos], nil
+}
+
+// rawTextEnd returns the offset of the end tag that closes a raw text
+// element, or len(src) if there is none. The name is matched ignoring ASCII
+// case and must be followed by whitespace, '/' or '>', so "</scripty" does
+// not end a script.
+func rawTextEnd(src, name string) int {
+	for i := 0; ; {
+		j := strings.Index(src[i:], "</")
+		if j < 0 {
+			return len(src)
+		}
+		start := i + j
+		end := start + 2 + len(name)
+		if end < len(src) && asciiEqualFold(src[start+2:end], name) {
+			if c := src[end]; isSelectorSpace(c) || c == '/' || c == '>' {
+				return start
+			}
+		}
+		i = start + 2
+	}
+}
+
+// asciiEqualFold reports whether s equals the lower-case name, folding only
+// ASCII letters as the HTML tokenizer does.
+func asciiEqualFold(s, name string) bool {
+	if len(s) != len(name) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		if c != name[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *htmlParser) skipSpace() {
+	for p.pos < len(p.src) && isSelectorSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
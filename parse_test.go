@@ -0,0 +1,119 @@
+package minty
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseHTMLRoundTrip(t *testing.T) {
+	page := Document("Round & trip", nil, B.Body(
+		B.Form(Action("/save"), Method("post"),
+			B.Label(For("email"), "Email <required>"),
+			B.Input(Type("email"), ID("email"), Name("email"), Required()),
+			B.Textarea(Name("notes"), "a < b"),
+			B.Select(Name("plan"), B.Option(Value("a"), Selected(), "A")),
+		),
+		B.Script(Raw("if (a < b && c) { go('</div>') }")),
+		Raw("<!-- note -->"),
+		B.Ul(B.Li("one"), B.Li("two")),
+	))
+	html := RenderToString(page)
+
+	root, err := ParseHTML(html)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := RenderToString(func(*Builder) Node { return root }); got != html {
+		t.Errorf("round trip changed the markup:\ngot:  %s\nwant: %s", got, html)
+	}
+	if v, _ := FindFirst(root, "input").GetAttribute("required"); v != "required" {
+		t.Errorf("required attribute = %q", v)
+	}
+}
+
+func TestParseHTMLKeepsForeignNameCase(t *testing.T) {
+	src := `<div viewBox="x"><svg viewBox="0 0 10 10" preserveAspectRatio="none">` +
+		`<defs><linearGradient id="g" gradientUnits="userSpaceOnUse"></linearGradient></defs>` +
+		`<title>Chart</title><foreignObject><div tabIndex="1">html</div></foreignObject></svg>` +
+		`<math definitionURL="u"><mi>x</mi></math></div>`
+	root, err := ParseHTML(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := RenderToString(func(*Builder) Node { return root })
+	want := `<div viewbox="x"><svg viewBox="0 0 10 10" preserveAspectRatio="none">` +
+		`<defs><linearGradient id="g" gradientUnits="userSpaceOnUse"></linearGradient></defs>` +
+		`<title>Chart</title><foreignObject><div tabindex="1">html</div></foreignObject></svg>` +
+		`<math definitionURL="u"><mi>x</mi></math></div>`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	if FindFirst(root, "linearGradient") == nil {
+		t.Error("selector should find linearGradient")
+	}
+}
+
+func TestParseHTMLImpliedEndTags(t *testing.T) {
+	root, err := ParseHTML(`<ul><li>one<li>two</ul><p>a<p>b<div>c</div><br>tail</span>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := RenderToString(func(*Builder) Node { return root })
+	want := `<ul><li>one</li><li>two</li></ul><p>a</p><p>b</p><div>c</div><br />tail`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestParseHTMLAttributes(t *testing.T) {
+	root, err := ParseHTML(`<A HREF=/x data-x='1 &amp; 2' hidden class="a" class="b"/>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := FindFirst(root, "a")
+	if a == nil || !a.SelfClosing {
+		t.Fatalf("expected self-closing <a>, got %#v", a)
+	}
+	want := AttributeList{
+		{Name: "href", Value: "/x"},
+		{Name: "data-x", Value: "1 & 2"},
+		{Name: "hidden", Value: ""},
+		{Name: "class", Value: "a"},
+	}
+	if len(a.Attributes) != len(want) {
+		t.Fatalf("attributes = %+v", a.Attributes)
+	}
+	for i := range want {
+		if a.Attributes[i] != want[i] {
+			t.Errorf("attribute %d = %+v, want %+v", i, a.Attributes[i], want[i])
+		}
+	}
+}
+
+func TestParseHTMLErrors(t *testing.T) {
+	for _, src := range []string{`<div class="x`, `<!-- open`, `<p`} {
+		_, err := ParseHTML(src)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("ParseHTML(%q) error = %v, want *ParseError", src, err)
+		}
+	}
+	root, err := ParseHTML("a < b")
+	if err != nil || TextContent(root) != "a < b" {
+		t.Errorf("a lone < should be text, got %v", err)
+	}
+}
+
+func TestParseHTMLRawTextEndTag(t *testing.T) {
+	root, err := ParseHTML("<script>a = '</scripty>'; b = 'İ'</SCRIPT ><p>after</p>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := FindFirst(root, "script")
+	if got := RenderToString(func(*Builder) Node { return script }); got != "<script>a = '</scripty>'; b = 'İ'</script>" {
+		t.Errorf("script = %s", got)
+	}
+	if p := FindFirst(root, "p"); p == nil || TextContent(p) != "after" {
+		t.Errorf("content after the script was not parsed: %s", RenderToString(func(*Builder) Node { return root }))
+	}
+}
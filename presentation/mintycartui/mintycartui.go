@@ -4,6 +4,7 @@ package mintycartui
 
 import (
 	"fmt"
+	"time"
 
 	mi "github.com/ha1tch/minty"
 	mui "github.com/ha1tch/minty/mintyui"
@@ -71,7 +72,8 @@ func ProductList(theme mui.Theme, products []mica.Product) mi.H {
 	}
 }
 
-// AddToCartButton creates an add to cart button
+// AddToCartButton creates an add to cart button. It swaps the response
+// into the CartWidget count; answer it with AddToCartResponse.
 func AddToCartButton(theme mui.Theme, product mica.Product) mi.H {
 	return mui.DomainButton(theme, Domain, "Add to Cart", "primary",
 		mi.HxPost("/api/cart/add"),
@@ -90,17 +92,39 @@ func CartWidget(theme mui.Theme, cart mica.Cart) mi.H {
 	displayData := mica.PrepareCartForDisplay(cart)
 	
 	return func(b *mi.Builder) mi.Node {
-		return b.Div(mi.Class("mica_cart_widget"),
+		return b.Div(mi.ID("cart-widget"), mi.Class("mica_cart_widget"),
 			b.A(mi.Href("/cart"), mi.Class("mica_cart_link"),
 				b.Span(mi.Class("mica_cart_icon"), "🛒"),
 				b.Span(mi.ID("cart-count"), mi.Class("mica_cart_count"), 
 					fmt.Sprintf("%d", displayData.ItemCount)),
-				b.Span(mi.Class("mica_cart_total"), displayData.FormattedTotal),
+				cartTotal(displayData)(b),
 			),
 		)
 	}
 }
 
+// cartTotal displays the cart total inside CartWidget
+func cartTotal(displayData mica.CartDisplayData) mi.H {
+	return func(b *mi.Builder) mi.Node {
+		return b.Span(mi.ID("cart-total"), mi.Class("mica_cart_total"), displayData.FormattedTotal)
+	}
+}
+
+// AddToCartResponse answers an AddToCartButton request. The new item count
+// goes into the button's target and the CartWidget total is swapped out of
+// band, so the whole widget is current after one response:
+//
+//	mica.AddItemToCart(&cart, product, 1)
+//	mintycartui.AddToCartResponse(theme, cart).ServeHTTP(w, r)
+func AddToCartResponse(theme mui.Theme, cart mica.Cart) *mi.Response {
+	displayData := mica.PrepareCartForDisplay(cart)
+	
+	count := func(b *mi.Builder) mi.Node {
+		return b.Text(fmt.Sprintf("%d", displayData.ItemCount))
+	}
+	return mi.NewResponse(count).OOB("", "", cartTotal(displayData))
+}
+
 // CartPage displays full cart with items and checkout
 func CartPage(theme mui.Theme, cart mica.Cart) mi.H {
 	displayData := mica.PrepareCartForDisplay(cart)
@@ -406,3 +430,32 @@ func IntegrateWithMainApp(theme mui.Theme, ecommerceService *mica.EcommerceServi
 	// Use presentation adapters to create UI
 	return EcommerceDashboard(theme, dashboardData)
 }
+
+// Fragment cache tags for CachedEcommerceDashboard. InvalidateOnChange
+// clears them whenever the service changes the matching data.
+const (
+	CacheTagOrders    = Domain + ":" + mica.ChangeOrders
+	CacheTagProducts  = Domain + ":" + mica.ChangeProducts
+	CacheTagCustomers = Domain + ":" + mica.ChangeCustomers
+)
+
+// InvalidateOnChange keeps cached dashboards in step with the service by
+// invalidating the matching CacheTag constant after every change, such as
+// CreateOrder invalidating CacheTagOrders.
+func InvalidateOnChange(ecommerceService *mica.EcommerceService) {
+	ecommerceService.OnChange(func(kind string) {
+		mi.InvalidateTags(Domain + ":" + kind)
+	})
+}
+
+// CachedEcommerceDashboard renders the dashboard for a service at most once
+// per ttl. cacheKey identifies the service, e.g. "main" or a tenant ID; use
+// a different key for every service rendered through the same cache. The
+// dashboard data is only prepared when the cached copy has expired or been
+// invalidated with one of the CacheTag constants.
+func CachedEcommerceDashboard(theme mui.Theme, ecommerceService *mica.EcommerceService, cacheKey string, ttl time.Duration) mi.H {
+	key := Domain + ":dashboard:" + theme.GetName() + ":" + cacheKey
+	return mi.Cached(key, ttl, func(b *mi.Builder) mi.Node {
+		return IntegrateWithMainApp(theme, ecommerceService)(b)
+	}, mi.CacheTags(CacheTagOrders, CacheTagProducts, CacheTagCustomers))
+}
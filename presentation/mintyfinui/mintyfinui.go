@@ -6,6 +6,7 @@ package mintyfinui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	mi "github.com/ha1tch/minty"
 	mui "github.com/ha1tch/minty/mintyui"
@@ -639,3 +640,31 @@ func IntegrateWithMainApp(theme mui.Theme, financeService *mifi.FinanceService)
 	// Use presentation adapters to create UI
 	return FinancialDashboard(theme, dashboardData, recentTransactions, pendingInvoices)
 }
+
+// Fragment cache tags for CachedFinancialDashboard. InvalidateOnChange
+// clears them whenever the service changes the matching data.
+const (
+	CacheTagAccounts     = Domain + ":" + mifi.ChangeAccounts
+	CacheTagTransactions = Domain + ":" + mifi.ChangeTransactions
+	CacheTagInvoices     = Domain + ":" + mifi.ChangeInvoices
+)
+
+// InvalidateOnChange keeps cached dashboards in step with the service by
+// invalidating the matching CacheTag constant after every change.
+func InvalidateOnChange(financeService *mifi.FinanceService) {
+	financeService.OnChange(func(kind string) {
+		mi.InvalidateTags(Domain + ":" + kind)
+	})
+}
+
+// CachedFinancialDashboard renders the dashboard for a service at most once
+// per ttl. cacheKey identifies the service, e.g. "main" or a tenant ID; use
+// a different key for every service rendered through the same cache. The
+// dashboard data is only prepared when the cached copy has expired or been
+// invalidated with one of the CacheTag constants.
+func CachedFinancialDashboard(theme mui.Theme, financeService *mifi.FinanceService, cacheKey string, ttl time.Duration) mi.H {
+	key := Domain + ":dashboard:" + theme.GetName() + ":" + cacheKey
+	return mi.Cached(key, ttl, func(b *mi.Builder) mi.Node {
+		return IntegrateWithMainApp(theme, financeService)(b)
+	}, mi.CacheTags(CacheTagAccounts, CacheTagTransactions, CacheTagInvoices))
+}
@@ -8,6 +8,7 @@ import (
 	mi "github.com/ha1tch/minty"
 	mui "github.com/ha1tch/minty/mintyui"
 	miex "github.com/ha1tch/minty/mintyex"
+	msse "github.com/ha1tch/minty/mintysse"
 	mimo "github.com/ha1tch/minty/domains/mintymove"
 )
 
@@ -124,6 +125,20 @@ func VehicleList(theme mui.Theme, vehicles []mimo.Vehicle) mi.H {
 
 // LogisticsDashboard creates a complete logistics dashboard
 func LogisticsDashboard(theme mui.Theme, dashboardData mimo.DashboardData) mi.H {
+	return logisticsDashboard(theme, dashboardData, "")
+}
+
+// LiveLogisticsDashboard creates a logistics dashboard that updates its
+// metrics and shipment cards when PublishMetrics and PublishShipment are
+// called, over Server-Sent Events from a mintysse broker served at
+// eventsPath. The page must load htmx and its sse extension.
+func LiveLogisticsDashboard(theme mui.Theme, dashboardData mimo.DashboardData, eventsPath string) mi.H {
+	return logisticsDashboard(theme, dashboardData, eventsPath)
+}
+
+func logisticsDashboard(theme mui.Theme, dashboardData mimo.DashboardData, eventsPath string) mi.H {
+	live := eventsPath != ""
+	
 	return mui.Dashboard(theme, "Logistics Dashboard",
 		// Sidebar
 		func(b *mi.Builder) mi.Node {
@@ -140,11 +155,17 @@ func LogisticsDashboard(theme mui.Theme, dashboardData mimo.DashboardData) mi.H
 		
 		// Main content
 		func(b *mi.Builder) mi.Node {
-			return b.Div(mi.Class("mimo_dashboard_main"),
-				// Logistics metrics
-				MetricsSection(theme, dashboardData)(b),
-				// Recent shipments
-				RecentShipmentsSection(theme, dashboardData.RecentShipments)(b),
+			if !live {
+				return b.Div(mi.Class("mimo_dashboard_main"),
+					// Logistics metrics
+					MetricsSection(theme, dashboardData)(b),
+					// Recent shipments
+					RecentShipmentsSection(theme, dashboardData.RecentShipments)(b),
+				)
+			}
+			return b.Div(mi.Class("mimo_dashboard_main"), msse.ConnectTopics(eventsPath, LiveTopic),
+				b.Div(msse.Swap(MetricsEvent), MetricsSection(theme, dashboardData)(b)),
+				recentShipmentsSection(theme, dashboardData.RecentShipments, true)(b),
 			)
 		},
 	)
@@ -171,6 +192,10 @@ func MetricsSection(theme mui.Theme, data mimo.DashboardData) mi.H {
 
 // RecentShipmentsSection displays recent shipments
 func RecentShipmentsSection(theme mui.Theme, shipments []mimo.ShipmentDisplayData) mi.H {
+	return recentShipmentsSection(theme, shipments, false)
+}
+
+func recentShipmentsSection(theme mui.Theme, shipments []mimo.ShipmentDisplayData, live bool) mi.H {
 	return func(b *mi.Builder) mi.Node {
 		return b.Section(mi.Class("mimo_shipments_section"),
 			b.H2("Recent Shipments"),
@@ -178,6 +203,12 @@ func RecentShipmentsSection(theme mui.Theme, shipments []mimo.ShipmentDisplayDat
 				func(b *mi.Builder) mi.Node {
 					return b.Div(mi.Class("mimo_shipment_list"),
 						mi.NewFragment(miex.Each(shipments, func(data mimo.ShipmentDisplayData) mi.H {
+							if live {
+								return func(b *mi.Builder) mi.Node {
+									return b.Div(msse.Swap(ShipmentEvent(data.Shipment.ID)),
+										ShipmentCard(theme, data.Shipment)(b))
+								}
+							}
 							return ShipmentCard(theme, data.Shipment)
 						})...),
 					)
@@ -192,6 +223,33 @@ func RecentShipmentsSection(theme mui.Theme, shipments []mimo.ShipmentDisplayDat
 	}
 }
 
+// =====================================================
+// LIVE UPDATES
+// =====================================================
+
+// LiveTopic is the mintysse topic LiveLogisticsDashboard subscribes to
+const LiveTopic = "logistics"
+
+// MetricsEvent is the event that replaces the dashboard metrics
+const MetricsEvent = "metrics"
+
+// ShipmentEvent returns the event that replaces one shipment's card
+func ShipmentEvent(shipmentID string) string {
+	return "shipment-" + shipmentID
+}
+
+// PublishShipment sends the current card of shipment to live dashboards,
+// e.g. when its status changes. Shipments not on a dashboard are ignored
+// by it.
+func PublishShipment(broker *msse.Broker, theme mui.Theme, shipment mimo.Shipment) error {
+	return broker.Publish(LiveTopic, ShipmentEvent(shipment.ID), ShipmentCard(theme, shipment))
+}
+
+// PublishMetrics sends updated metrics to live dashboards
+func PublishMetrics(broker *msse.Broker, theme mui.Theme, data mimo.DashboardData) error {
+	return broker.Publish(LiveTopic, MetricsEvent, MetricsSection(theme, data))
+}
+
 // =====================================================
 // HELPER FUNCTIONS
 // =====================================================
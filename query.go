@@ -0,0 +1,455 @@
+package minty
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tree traversal and queries
+//
+// Once a template has been evaluated the result is an ordinary tree of
+// Element, Fragment, TextNode and RawNode values. Walk visits it, FindAll
+// and FindFirst query it with a subset of CSS selectors, and Transform or Modify
+// rewrite it before rendering:
+//
+//	page := mi.Transformed(layout, func(n mi.Node) mi.Node {
+//	    return mi.Modify(n, "img", func(img *mi.Element) {
+//	        img.SetAttribute("loading", "lazy")
+//	    })
+//	})
+//
+// Supported selectors: tag, *, #id, .class, [attr], [attr=value],
+// [attr~=value], [attr^=value], [attr$=value], [attr*=value], compounds
+// such as a.external[href], the descendant (space) and child (>)
+// combinators, and comma-separated groups.
+
+// Walk visits node and its descendants depth-first in document order.
+// If fn returns false the children of that node are skipped.
+func Walk(node Node, fn func(Node) bool) {
+	walk(node, nil, func(n Node, _ []*Element) bool {
+		return fn(n)
+	})
+}
+
+// walk is Walk with the chain of enclosing elements, outermost first.
+func walk(node Node, ancestors []*Element, fn func(Node, []*Element) bool) {
+	if node == nil || !fn(node, ancestors) {
+		return
+	}
+	switch n := node.(type) {
+	case *Element:
+		ancestors = append(ancestors, n)
+		for _, child := range n.Children {
+			walk(child, ancestors, fn)
+		}
+	case *Fragment:
+		for _, child := range n.Children {
+			walk(child, ancestors, fn)
+		}
+	}
+}
+
+// TextContent returns the concatenated text of the TextNodes under node.
+func TextContent(node Node) string {
+	var sb strings.Builder
+	Walk(node, func(n Node) bool {
+		if t, ok := n.(*TextNode); ok {
+			sb.WriteString(t.Content)
+		}
+		return true
+	})
+	return sb.String()
+}
+
+// FindAll returns the elements under node (including node itself) that
+// match selector, in document order. It panics if the selector is invalid;
+// use ParseSelector to handle the error.
+func FindAll(node Node, selector string) []*Element {
+	return MustParseSelector(selector).FindAll(node)
+}
+
+// FindFirst returns the first element matching selector, or nil.
+func FindFirst(node Node, selector string) *Element {
+	return MustParseSelector(selector).Find(node)
+}
+
+// Transform rewrites the tree bottom-up: fn is called for every node after
+// its children have been transformed, and its result replaces the node.
+// Returning nil removes the node. Elements and fragments are modified in
+// place; the returned node is the new root.
+func Transform(node Node, fn func(Node) Node) Node {
+	if node == nil {
+		return nil
+	}
+	switch n := node.(type) {
+	case *Element:
+		n.Children = transformChildren(n.Children, fn)
+	case *Fragment:
+		n.Children = transformChildren(n.Children, fn)
+	}
+	return fn(node)
+}
+
+func transformChildren(children []Node, fn func(Node) Node) []Node {
+	out := children[:0]
+	for _, child := range children {
+		if replaced := Transform(child, fn); replaced != nil {
+			out = append(out, replaced)
+		}
+	}
+	return out
+}
+
+// Modify calls fn on every element under node that matches selector and
+// returns node. It panics if the selector is invalid.
+func Modify(node Node, selector string, fn func(*Element)) Node {
+	for _, el := range FindAll(node, selector) {
+		fn(el)
+	}
+	return node
+}
+
+// Transformed returns a template that evaluates template and passes the
+// result through each transform in turn.
+func Transformed(template H, transforms ...func(Node) Node) H {
+	return func(b *Builder) Node {
+		node := template(b)
+		for _, t := range transforms {
+			node = t(node)
+		}
+		return node
+	}
+}
+
+// Selector is a parsed selector group.
+type Selector struct {
+	source string
+	groups [][]selectorStep
+}
+
+// selectorStep is a compound selector and the combinator linking it to the
+// step on its left (' ' for descendant, '>' for child).
+type selectorStep struct {
+	combinator byte
+	tag        string
+	id         string
+	classes    []string
+	attrs      []attrMatcher
+}
+
+type attrMatcher struct {
+	name  string
+	op    string // "", "=", "~=", "^=", "$=", "*="
+	value string
+}
+
+// SelectorError reports an unsupported or malformed selector.
+type SelectorError struct {
+	Selector string
+	Offset   int
+	Reason   string
+}
+
+func (e *SelectorError) Error() string {
+	return fmt.Sprintf("minty: invalid selector %q at offset %d: %s", e.Selector, e.Offset, e.Reason)
+}
+
+// MustParseSelector is like ParseSelector but panics on error.
+func MustParseSelector(selector string) *Selector {
+	s, err := ParseSelector(selector)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// ParseSelector parses a selector in the supported subset.
+func ParseSelector(selector string) (*Selector, error) {
+	p := &selectorParser{src: selector}
+	sel := &Selector{source: selector}
+	for {
+		group, err := p.group()
+		if err != nil {
+			return nil, err
+		}
+		sel.groups = append(sel.groups, group)
+		p.skipSpace()
+		if p.pos == len(p.src) {
+			return sel, nil
+		}
+		if p.src[p.pos] != ',' {
+			return nil, p.errorf("unexpected %q", p.src[p.pos])
+		}
+		p.pos++
+	}
+}
+
+// String returns the selector source.
+func (s *Selector) String() string {
+	return s.source
+}
+
+// FindAll returns the matching elements under node in document order.
+func (s *Selector) FindAll(node Node) []*Element {
+	var found []*Element
+	walk(node, nil, func(n Node, ancestors []*Element) bool {
+		if el, ok := n.(*Element); ok && s.match(el, ancestors) {
+			found = append(found, el)
+		}
+		return true
+	})
+	return found
+}
+
+// Find returns the first matching element under node, or nil.
+func (s *Selector) Find(node Node) *Element {
+	var found *Element
+	walk(node, nil, func(n Node, ancestors []*Element) bool {
+		if found != nil {
+			return false
+		}
+		if el, ok := n.(*Element); ok && s.match(el, ancestors) {
+			found = el
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// Matches reports whether el matches the selector on its own, ignoring
+// combinators that need ancestors.
+func (s *Selector) Matches(el *Element) bool {
+	return s.match(el, nil)
+}
+
+func (s *Selector) match(el *Element, ancestors []*Element) bool {
+	for _, group := range s.groups {
+		if matchSteps(group, len(group)-1, el, ancestors) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSteps matches steps[:i+1] right to left, el matching steps[i].
+func matchSteps(steps []selectorStep, i int, el *Element, ancestors []*Element) bool {
+	if !steps[i].matches(el) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	switch steps[i].combinator {
+	case '>':
+		if len(ancestors) == 0 {
+			return false
+		}
+		parent := ancestors[len(ancestors)-1]
+		return matchSteps(steps, i-1, parent, ancestors[:len(ancestors)-1])
+	default:
+		for j := len(ancestors) - 1; j >= 0; j-- {
+			if matchSteps(steps, i-1, ancestors[j], ancestors[:j]) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (st *selectorStep) matches(el *Element) bool {
+	if st.tag != "" && st.tag != "*" && !strings.EqualFold(st.tag, el.Tag) {
+		return false
+	}
+	if st.id != "" {
+		if id, _ := el.Attributes.Get("id"); id != st.id {
+			return false
+		}
+	}
+	if len(st.classes) > 0 {
+		class, _ := el.Attributes.Get("class")
+		fields := strings.Fields(class)
+		for _, want := range st.classes {
+			if !containsString(fields, want) {
+				return false
+			}
+		}
+	}
+	for _, a := range st.attrs {
+		value, ok := el.Attributes.Get(a.name)
+		if !ok || !a.matches(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a attrMatcher) matches(value string) bool {
+	switch a.op {
+	case "=":
+		return value == a.value
+	case "~=":
+		return containsString(strings.Fields(value), a.value)
+	case "^=":
+		return a.value != "" && strings.HasPrefix(value, a.value)
+	case "$=":
+		return a.value != "" && strings.HasSuffix(value, a.value)
+	case "*=":
+		return a.value != "" && strings.Contains(value, a.value)
+	}
+	return true
+}
+
+// selectorParser is a small recursive-descent parser for the subset.
+type selectorParser struct {
+	src string
+	pos int
+}
+
+func (p *selectorParser) errorf(format string, args ...interface{}) error {
+	return &SelectorError{Selector: p.src, Offset: p.pos, Reason: fmt.Sprintf(format, args...)}
+}
+
+func (p *selectorParser) skipSpace() bool {
+	start := p.pos
+	for p.pos < len(p.src) && isSelectorSpace(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *selectorParser) group() ([]selectorStep, error) {
+	var steps []selectorStep
+	p.skipSpace()
+	combinator := byte(' ')
+	for {
+		step, err := p.compound()
+		if err != nil {
+			return nil, err
+		}
+		step.combinator = combinator
+		steps = append(steps, step)
+
+		spaced := p.skipSpace()
+		if p.pos == len(p.src) || p.src[p.pos] == ',' {
+			return steps, nil
+		}
+		if p.src[p.pos] == '>' {
+			p.pos++
+			p.skipSpace()
+			combinator = '>'
+		} else if spaced {
+			combinator = ' '
+		} else {
+			return nil, p.errorf("unexpected %q", p.src[p.pos])
+		}
+	}
+}
+
+func (p *selectorParser) compound() (selectorStep, error) {
+	var st selectorStep
+	start := p.pos
+	if p.pos < len(p.src) && p.src[p.pos] == '*' {
+		st.tag = "*"
+		p.pos++
+	} else {
+		st.tag = p.ident()
+	}
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '#':
+			p.pos++
+			if st.id = p.ident(); st.id == "" {
+				return st, p.errorf("expected id after #")
+			}
+		case '.':
+			p.pos++
+			class := p.ident()
+			if class == "" {
+				return st, p.errorf("expected class name after .")
+			}
+			st.classes = append(st.classes, class)
+		case '[':
+			p.pos++
+			a, err := p.attribute()
+			if err != nil {
+				return st, err
+			}
+			st.attrs = append(st.attrs, a)
+		case ':':
+			return st, p.errorf("pseudo-classes are not supported")
+		default:
+			if p.pos == start {
+				return st, p.errorf("expected selector")
+			}
+			return st, nil
+		}
+	}
+	if p.pos == start {
+		return st, p.errorf("expected selector")
+	}
+	return st, nil
+}
+
+func (p *selectorParser) attribute() (attrMatcher, error) {
+	var a attrMatcher
+	p.skipSpace()
+	// Attribute names may contain colons (xlink:href, hx-on:click)
+	start := p.pos
+	for p.pos < len(p.src) && (isSelectorIdent(p.src[p.pos]) || p.src[p.pos] == ':') {
+		p.pos++
+	}
+	if a.name = p.src[start:p.pos]; a.name == "" {
+		return a, p.errorf("expected attribute name")
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == ']' {
+		p.pos++
+		return a, nil
+	}
+	for _, op := range []string{"=", "~=", "^=", "$=", "*="} {
+		if strings.HasPrefix(p.src[p.pos:], op) {
+			a.op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if a.op == "" {
+		return a, p.errorf("unsupported attribute operator")
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) && (p.src[p.pos] == '"' || p.src[p.pos] == '\'') {
+		quote := p.src[p.pos]
+		end := strings.IndexByte(p.src[p.pos+1:], quote)
+		if end < 0 {
+			return a, p.errorf("unterminated string")
+		}
+		a.value = p.src[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+	} else {
+		a.value = p.ident()
+	}
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != ']' {
+		return a, p.errorf("expected ]")
+	}
+	p.pos++
+	return a, nil
+}
+
+func (p *selectorParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.src) && isSelectorIdent(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func isSelectorIdent(c byte) bool {
+	return c == '-' || c == '_' ||
+		c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+func isSelectorSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,127 @@
+package minty
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func queryFixture() Node {
+	b := B
+	return b.Div(ID("page"),
+		b.Nav(Class("menu main"),
+			b.A(Href("/"), Class("active"), "Home"),
+			b.A(Href("https://example.com"), Target("_blank"), "Out"),
+		),
+		b.Main(
+			b.Section(Class("card"),
+				b.H2("Title"),
+				b.Img(Src("/a.png"), Alt("A")),
+				b.P("Body ", b.Strong("bold")),
+			),
+			b.Img(Src("/b.png"), Alt("B")),
+			NewFragment(b.Img(Src("/c.png"), Alt("C"))),
+		),
+	)
+}
+
+func tags(els []*Element) string {
+	var out []string
+	for _, el := range els {
+		s := el.Tag
+		if alt, ok := el.GetAttribute("alt"); ok {
+			s += "[" + alt + "]"
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestFindAllSelectors(t *testing.T) {
+	root := queryFixture()
+	cases := []struct {
+		selector string
+		want     string
+	}{
+		{"img", "img[A] img[B] img[C]"},
+		{"#page", "div"},
+		{".card img", "img[A]"},
+		{"main > img", "img[B] img[C]"},
+		{"nav.main.menu > a.active", "a"},
+		{"a[target]", "a"},
+		{`a[href^="https:"]`, "a"},
+		{"[alt=B], [alt='C']", "img[B] img[C]"},
+		{"[class~=menu]", "nav"},
+		{"div p strong", "strong"},
+		{"section > strong", ""},
+		{"*", "div nav a a main section h2 img[A] p strong img[B] img[C]"},
+	}
+	for _, c := range cases {
+		if got := tags(FindAll(root, c.selector)); got != c.want {
+			t.Errorf("FindAll(%q) = %q, want %q", c.selector, got, c.want)
+		}
+	}
+
+	if el := FindFirst(root, "section h2"); el == nil || TextContent(el) != "Title" {
+		t.Errorf("FindFirst returned %v", el)
+	}
+	if TextContent(FindFirst(root, "p")) != "Body bold" {
+		t.Error("TextContent should include nested text")
+	}
+}
+
+func TestParseSelectorErrors(t *testing.T) {
+	for _, sel := range []string{"", "a:hover", "div >", "[href", "a[href!=x]", ".", "a,"} {
+		_, err := ParseSelector(sel)
+		var se *SelectorError
+		if !errors.As(err, &se) {
+			t.Errorf("ParseSelector(%q) error = %v, want *SelectorError", sel, err)
+		}
+	}
+}
+
+func TestWalkSkipsChildren(t *testing.T) {
+	var visited []string
+	Walk(queryFixture(), func(n Node) bool {
+		if el, ok := n.(*Element); ok {
+			visited = append(visited, el.Tag)
+			return el.Tag != "main"
+		}
+		return true
+	})
+	if got := strings.Join(visited, " "); got != "div nav a a main" {
+		t.Errorf("visited %q", got)
+	}
+}
+
+func TestTransformAndModify(t *testing.T) {
+	page := Transformed(func(b *Builder) Node { return queryFixture() },
+		func(n Node) Node {
+			return Modify(n, "img", func(img *Element) {
+				img.SetAttribute("loading", "lazy")
+			})
+		},
+		func(n Node) Node {
+			return Transform(n, func(n Node) Node {
+				if el, ok := n.(*Element); ok && el.Tag == "nav" {
+					return nil
+				}
+				if el, ok := n.(*Element); ok && el.Tag == "strong" {
+					return B.Em(NewFragment(el.Children...))
+				}
+				return n
+			})
+		},
+	)
+	html := RenderToString(page)
+
+	if strings.Count(html, `loading="lazy"`) != 3 {
+		t.Errorf("expected every img to be lazy: %s", html)
+	}
+	if strings.Contains(html, "<nav") {
+		t.Errorf("nav should be removed: %s", html)
+	}
+	if !strings.Contains(html, "<p>Body <em>bold</em></p>") {
+		t.Errorf("strong should become em: %s", html)
+	}
+}
@@ -0,0 +1,228 @@
+package minty
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Render pipeline
+//
+// Nodes write through a pooled, buffered writer so that rendering a page is
+// a sequence of WriteString/WriteByte calls into one buffer rather than one
+// fmt call and one allocation per tag and attribute. Writers that already
+// buffer in memory (bytes.Buffer, strings.Builder, bufio.Writer) are used
+// directly; anything else, such as an http.ResponseWriter, is wrapped.
+
+// stringWriter is the write surface used internally by the render path.
+type stringWriter interface {
+	io.Writer
+	io.StringWriter
+	io.ByteWriter
+}
+
+// renderWriter buffers output for an underlying writer and optionally
+// flushes it, including to an http.Flusher, at chosen points.
+type renderWriter struct {
+	*bufio.Writer
+	dst        io.Writer
+	flushAfter []string
+
+	// ctx, when set, is checked periodically while elements are rendered.
+	ctx      context.Context
+	elements int
+
+	// csp, when set, collects inline hashes and drops handlers (csp.go).
+	csp *CSPCollector
+
+	// compile, when set, records the dynamic parts of a template being
+	// compiled (compile.go).
+	compile *compiler
+}
+
+const renderBufferSize = 8 << 10
+
+var renderWriterPool = sync.Pool{
+	New: func() interface{} {
+		return &renderWriter{Writer: bufio.NewWriterSize(nil, renderBufferSize)}
+	},
+}
+
+func acquireRenderWriter(dst io.Writer) *renderWriter {
+	rw := renderWriterPool.Get().(*renderWriter)
+	rw.Writer.Reset(dst)
+	rw.dst = dst
+	return rw
+}
+
+func releaseRenderWriter(rw *renderWriter) {
+	rw.Writer.Reset(nil)
+	rw.dst = nil
+	rw.flushAfter = rw.flushAfter[:0]
+	rw.ctx = nil
+	rw.elements = 0
+	rw.csp = nil
+	rw.compile = nil
+	renderWriterPool.Put(rw)
+}
+
+// flush writes buffered output to the destination and, if the destination
+// is an http.Flusher, pushes it to the client.
+func (rw *renderWriter) flush() error {
+	if err := rw.Writer.Flush(); err != nil {
+		return err
+	}
+	if f, ok := rw.dst.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// closedTag is called after an element's closing tag has been written.
+func (rw *renderWriter) closedTag(tag string) error {
+	for _, t := range rw.flushAfter {
+		if t == tag {
+			return rw.flush()
+		}
+	}
+	return nil
+}
+
+// bufferedWriter returns w as a stringWriter if it already buffers output.
+func bufferedWriter(w io.Writer) (stringWriter, bool) {
+	switch v := w.(type) {
+	case *renderWriter:
+		return v, true
+	case *bytes.Buffer:
+		return v, true
+	case *strings.Builder:
+		return v, true
+	case *bufio.Writer:
+		return v, true
+	}
+	return nil, false
+}
+
+// renderBuffered renders node into a pooled writer wrapping w.
+func renderBuffered(node Node, w io.Writer) error {
+	rw := acquireRenderWriter(w)
+	defer releaseRenderWriter(rw)
+	if err := node.Render(rw); err != nil {
+		return err
+	}
+	return rw.Writer.Flush()
+}
+
+// StreamOption configures RenderStream.
+type StreamOption func(*renderWriter)
+
+// FlushAfter flushes the output, and the http.Flusher behind it if any,
+// after the closing tag of each named element has been written.
+func FlushAfter(tags ...string) StreamOption {
+	return func(rw *renderWriter) {
+		rw.flushAfter = append(rw.flushAfter, tags...)
+	}
+}
+
+// RenderStream renders a template through a buffered writer that flushes
+// early at chosen points, so the browser can start fetching stylesheets and
+// scripts while the rest of the page is rendered. Without options it
+// flushes after </head>. Use FlushPoint to flush at arbitrary positions.
+func RenderStream(template H, w io.Writer, opts ...StreamOption) error {
+	rw := acquireRenderWriter(w)
+	defer releaseRenderWriter(rw)
+	if len(opts) == 0 {
+		opts = []StreamOption{FlushAfter("head")}
+	}
+	for _, opt := range opts {
+		opt(rw)
+	}
+	node := template(NewBuilder(context.Background()))
+	if err := checkStrict(context.Background(), node); err != nil {
+		return err
+	}
+	if err := node.Render(rw); err != nil {
+		return err
+	}
+	return rw.flush()
+}
+
+// flushNode flushes a streaming render when it is reached.
+type flushNode struct{}
+
+// Render flushes the writer if it supports streaming and writes nothing.
+func (flushNode) Render(w io.Writer) error {
+	if c := compilerFor(w); c != nil {
+		return c.live(func(*Builder) Node { return flushNode{} })
+	}
+	if rw, ok := w.(*renderWriter); ok {
+		return rw.flush()
+	}
+	return nil
+}
+
+// FlushPoint returns a node that flushes buffered output to the client when
+// rendered with RenderStream. It renders nothing in other modes.
+func FlushPoint() Node {
+	return flushNode{}
+}
+
+// String buffer pool for RenderToString
+
+const maxPooledBufferSize = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+func acquireBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func releaseBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(buf)
+	}
+}
+
+// HTML escaping without intermediate strings
+
+// writeEscaped writes s with the same escaping as html.EscapeString,
+// copying unescaped runs straight to the writer.
+func writeEscaped(w stringWriter, s string) error {
+	last := 0
+	for i := 0; i < len(s); i++ {
+		var esc string
+		switch s[i] {
+		case '<':
+			esc = "&lt;"
+		case '>':
+			esc = "&gt;"
+		case '&':
+			esc = "&amp;"
+		case '\'':
+			esc = "&#39;"
+		case '"':
+			esc = "&#34;"
+		default:
+			continue
+		}
+		if _, err := w.WriteString(s[last:i]); err != nil {
+			return err
+		}
+		if _, err := w.WriteString(esc); err != nil {
+			return err
+		}
+		last = i + 1
+	}
+	_, err := w.WriteString(s[last:])
+	return err
+}
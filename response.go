@@ -0,0 +1,119 @@
+package minty
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Out-of-band responses
+//
+// One htmx request often changes several parts of a page: adding to the
+// cart updates the list, the cart badge and a notification. htmx swaps the
+// response into the request's target and swaps elements marked with
+// hx-swap-oob into their own places. Response builds such a response:
+//
+//	mi.NewResponse(CartItems(cart)).
+//	    OOB("", "", CartBadge(cart)).                   // outerHTML of #cart-badge
+//	    OOB("#notifications", "beforeend", Notice(msg)). // children appended to #notifications
+//	    ServeHTTP(w, r)
+//
+// The fragments are built with one builder, so ids from b.UID are unique
+// across them, and the response is written in one piece.
+
+// Response is an htmx response made of a primary fragment and out-of-band
+// fragments.
+type Response struct {
+	primary H
+	oob     []oobFragment
+}
+
+// oobFragment is a fragment swapped outside the request's target.
+type oobFragment struct {
+	target  string
+	swap    string
+	content H
+}
+
+// NewResponse starts a response whose primary fragment is swapped into the
+// request's target. primary may be nil for responses made only of
+// out-of-band fragments.
+func NewResponse(primary H) *Response {
+	return &Response{primary: primary}
+}
+
+// OOB adds a fragment swapped out of band. With an empty target the
+// fragment's root element must have an id, and the element with that id
+// on the page is swapped. Otherwise target is a CSS selector such as
+// "#list", and the children of the root element are swapped into every
+// match. swap is an htmx swap strategy and defaults to outerHTML. With
+// both empty, an hx-swap-oob attribute already on the root is kept.
+func (r *Response) OOB(target, swap string, content H) *Response {
+	r.oob = append(r.oob, oobFragment{target: target, swap: swap, content: content})
+	return r
+}
+
+// Template returns the response as a single template. Building it fails
+// the render if an out-of-band fragment has no root element, or needs an
+// id and has none.
+func (r *Response) Template() H {
+	return func(b *Builder) Node {
+		nodes := make([]Node, 0, len(r.oob)+1)
+		if r.primary != nil {
+			nodes = append(nodes, r.primary(b))
+		}
+		for i, f := range r.oob {
+			node := f.content(b)
+			root := rootElement(node)
+			if root == nil {
+				return errorNode{fmt.Errorf("minty: out-of-band fragment %d has no root element", i+1)}
+			}
+			value := f.swapOOB()
+			if existing, _ := root.Attributes.Get("hx-swap-oob"); existing != "" && f.target == "" && f.swap == "" {
+				value = existing // set by the fragment itself, e.g. HtmxSwapOOB("beforeend:#list")
+			}
+			// like htmx, a colon separates the strategy from a target selector
+			if id, _ := root.Attributes.Get("id"); strings.Index(value, ":") <= 0 && strings.TrimSpace(id) == "" {
+				return errorNode{fmt.Errorf("minty: out-of-band fragment %d: <%s> has no id; give it one or name a target", i+1, root.Tag)}
+			}
+			root.Attributes.Set("hx-swap-oob", value)
+			nodes = append(nodes, node)
+		}
+		return NewFragment(nodes...)
+	}
+}
+
+// swapOOB returns the hx-swap-oob value for the fragment.
+func (f oobFragment) swapOOB() string {
+	swap := f.swap
+	if swap == "" {
+		swap = HTMXSwapStrategies.OuterHTML
+	}
+	switch {
+	case f.target != "":
+		return swap + ":" + f.target
+	case swap == HTMXSwapStrategies.OuterHTML:
+		return "true"
+	}
+	return swap
+}
+
+// Render writes the response to w with a builder bound to ctx. Nothing is
+// written if building or rendering fails.
+func (r *Response) Render(ctx context.Context, w http.ResponseWriter) error {
+	var buf bytes.Buffer
+	if err := RenderWithContext(ctx, r.Template(), &buf); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ServeHTTP renders the response for req, so a Response can be returned
+// from a handler or used as one. Errors are answered as by Handle.
+func (r *Response) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	serve(w, req, r.Template(), nil, handlerOptions(nil))
+}
@@ -0,0 +1,57 @@
+package minty
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseRendersOOBFragments(t *testing.T) {
+	badge := func(b *Builder) Node { return b.Span(ID("cart-badge"), "3") }
+	notice := func(b *Builder) Node { return b.Div(b.P("Added")) }
+	row := func(b *Builder) Node { return b.Tr(HtmxSwapOOB("beforeend:#items"), b.Td("Tea")) }
+	count := func(b *Builder) Node { return b.Span(ID("count"), "3") }
+	field := func(b *Builder) Node { return b.Input(ID(b.UID("qty"))) }
+
+	res := NewResponse(func(b *Builder) Node { return b.Ul(b.Li("Tea"), field(b)) }).
+		OOB("", "", badge).
+		OOB("#notifications", "beforeend", notice).
+		OOB("", "", row).
+		OOB("", "innerHTML", count).
+		OOB("", "", field)
+
+	rec := httptest.NewRecorder()
+	res.ServeHTTP(rec, httptest.NewRequest("POST", "/cart", nil))
+
+	want := `<ul><li>Tea</li><input id="qty" /></ul>` +
+		`<span id="cart-badge" hx-swap-oob="true">3</span>` +
+		`<div hx-swap-oob="beforeend:#notifications"><p>Added</p></div>` +
+		`<tr hx-swap-oob="beforeend:#items"><td>Tea</td></tr>` +
+		`<span id="count" hx-swap-oob="innerHTML">3</span>` +
+		`<input id="qty-2" hx-swap-oob="true" />`
+	if rec.Body.String() != want {
+		t.Errorf("body:\n%s\nwant:\n%s", rec.Body.String(), want)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestResponseRequiresOOBIDs(t *testing.T) {
+	res := NewResponse(nil).OOB("", "outerHTML", func(b *Builder) Node { return b.Div("no id") })
+
+	rec := httptest.NewRecorder()
+	err := res.Render(context.Background(), rec)
+	if err == nil || !strings.Contains(err.Error(), "<div> has no id") {
+		t.Fatalf("expected missing id error, got %v", err)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("nothing should be written on error, got %q", rec.Body.String())
+	}
+
+	res = NewResponse(nil).OOB("#x", "", func(b *Builder) Node { return b.Text("loose text") })
+	if err := res.Render(context.Background(), httptest.NewRecorder()); err == nil {
+		t.Error("fragments without a root element should fail")
+	}
+}
@@ -0,0 +1,179 @@
+package minty
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Strict mode
+//
+// The builders are forgiving: an argument that is not an attribute, node,
+// string, int or fmt.Stringer is written as text with fmt's %v, and content
+// passed to a self-closing element such as the core SVG shapes (Path,
+// Circle, Rect...) is dropped. That keeps templates short but lets
+// mistakes through; passing a struct, or an H instead of the Node it
+// returns, renders as garbage text such as "{42 Widget}" or "0x4a2f60".
+//
+// The builders record these problems on the element. In strict mode,
+// rendering stops before anything is written and returns a *StrictError
+// listing each problem with the path of the element it occurred in:
+//
+//	func TestMain(m *testing.M) {
+//	    mi.SetStrict(true)
+//	    os.Exit(m.Run())
+//	}
+//
+// Render, RenderToStringE, RenderStream, RenderWithContext and the HTTP
+// handlers all check; RenderToString still returns "" on error. Use
+// WithStrict to enable strict mode for a single request, and StrictIssues
+// to inspect a tree directly.
+
+var strictMode atomic.Bool
+
+// SetStrict turns strict mode on or off for all renders that do not set
+// it through WithStrict. Enable it in tests and development builds.
+func SetStrict(enabled bool) {
+	strictMode.Store(enabled)
+}
+
+var strictKey = NewContextKey[bool]("strict")
+
+// WithStrict returns a copy of ctx that turns strict mode on or off for
+// renders using it, overriding SetStrict.
+func WithStrict(ctx context.Context, enabled bool) context.Context {
+	return strictKey.With(ctx, enabled)
+}
+
+// strictEnabled reports whether renders with ctx run in strict mode.
+func strictEnabled(ctx context.Context) bool {
+	if enabled, ok := strictKey.From(ctx); ok {
+		return enabled
+	}
+	return strictMode.Load()
+}
+
+// Issue is a template mistake found in strict mode.
+type Issue struct {
+	Path    string // element path, e.g. "body > form#signup > input"
+	Message string
+}
+
+// String returns the issue as "path: message".
+func (i Issue) String() string {
+	return i.Path + ": " + i.Message
+}
+
+// StrictError is returned by renders in strict mode when the tree has
+// issues.
+type StrictError struct {
+	Issues []Issue
+}
+
+// Error implements the error interface.
+func (e *StrictError) Error() string {
+	if len(e.Issues) == 1 {
+		return "minty: strict mode: " + e.Issues[0].String()
+	}
+	var sb strings.Builder
+	sb.WriteString("minty: strict mode: ")
+	sb.WriteString(strconv.Itoa(len(e.Issues)))
+	sb.WriteString(" issues")
+	for _, issue := range e.Issues {
+		sb.WriteString("\n\t")
+		sb.WriteString(issue.String())
+	}
+	return sb.String()
+}
+
+// StrictIssues returns the issues recorded by the builders for the
+// elements under node, in document order.
+func StrictIssues(node Node) []Issue {
+	var issues []Issue
+	walk(node, nil, func(n Node, ancestors []*Element) bool {
+		el, ok := n.(*Element)
+		if !ok || len(el.issues) == 0 {
+			return true
+		}
+		path := elementPath(append(ancestors[:len(ancestors):len(ancestors)], el))
+		for _, msg := range el.issues {
+			issues = append(issues, Issue{Path: path, Message: msg})
+		}
+		return true
+	})
+	return issues
+}
+
+// checkStrict returns a *StrictError for node if ctx enables strict mode
+// and the tree has issues.
+func checkStrict(ctx context.Context, node Node) error {
+	if !strictEnabled(ctx) {
+		return nil
+	}
+	if issues := StrictIssues(node); len(issues) > 0 {
+		return &StrictError{Issues: issues}
+	}
+	return nil
+}
+
+// elementPath describes a chain of elements as "tag#id > tag.class > tag".
+func elementPath(chain []*Element) string {
+	parts := make([]string, len(chain))
+	for i, el := range chain {
+		part := el.Tag
+		if id, ok := el.Attributes.Get("id"); ok && id != "" {
+			part += "#" + id
+		} else if class, ok := el.Attributes.Get("class"); ok {
+			if fields := strings.Fields(class); len(fields) > 0 {
+				part += "." + fields[0]
+			}
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, " > ")
+}
+
+// dropped records content passed to a self-closing element.
+func (e *Element) dropped(v interface{}) {
+	e.issues = append(e.issues, fmt.Sprintf("%s passed to self-closing <%s> was dropped", describeArg(v), e.Tag))
+}
+
+// stringified records an argument that was written with %v.
+func (e *Element) stringified(v interface{}, text string) {
+	msg := fmt.Sprintf("argument of type %T was rendered as text %q", v, truncate(text, 40))
+	switch v.(type) {
+	case H, func(*Builder) Node:
+		msg += "; call the template with the builder to get a Node"
+	}
+	e.issues = append(e.issues, msg)
+}
+
+// describeArg names an argument in an issue message.
+func describeArg(v interface{}) string {
+	switch a := v.(type) {
+	case *Element:
+		return "<" + a.Tag + "> element"
+	case string:
+		return "text " + strconv.Quote(truncate(a, 40))
+	case Node:
+		return fmt.Sprintf("%T node", v)
+	}
+	return fmt.Sprintf("%T value", v)
+}
+
+// isScalar reports whether v is a number, bool or string type, whose %v
+// form is meaningful text.
+func isScalar(v interface{}) bool {
+	kind := reflect.TypeOf(v).Kind()
+	return kind <= reflect.Complex128 || kind == reflect.String
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return s
+}
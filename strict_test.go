@@ -0,0 +1,96 @@
+package minty
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type product struct {
+	ID   int
+	Name string
+}
+
+func TestStrictModeReportsMisuse(t *testing.T) {
+	card := func(b *Builder) Node { return b.P("card") }
+	template := func(b *Builder) Node {
+		return b.Body(
+			b.Form(ID("signup"),
+				b.Circle(R("4"), "Logo"),
+				b.Div(Class("summary row"), product{42, "Widget"}),
+				b.Div(H(card)),
+				b.Span(3.5, true, statusText("ok")),
+			),
+		)
+	}
+
+	// Default mode keeps the forgiving behaviour
+	out, err := RenderToStringE(template)
+	if err != nil {
+		t.Fatalf("non-strict render failed: %v", err)
+	}
+	if !strings.Contains(out, "{42 Widget}") || !strings.Contains(out, "<span>3.5trueok</span>") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	SetStrict(true)
+	defer SetStrict(false)
+
+	_, err = RenderToStringE(template)
+	var strictErr *StrictError
+	if !errors.As(err, &strictErr) {
+		t.Fatalf("expected *StrictError, got %v", err)
+	}
+	if len(strictErr.Issues) != 3 {
+		t.Fatalf("got %d issues: %v", len(strictErr.Issues), err)
+	}
+	want := []struct{ path, message string }{
+		{"body > form#signup > circle", `text "Logo" passed to self-closing <circle> was dropped`},
+		{"body > form#signup > div.summary", "argument of type minty.product was rendered as text"},
+		{"body > form#signup > div", "call the template with the builder"},
+	}
+	for i, w := range want {
+		issue := strictErr.Issues[i]
+		if issue.Path != w.path || !strings.Contains(issue.Message, w.message) {
+			t.Errorf("issue %d = %s; want %s: ...%s...", i, issue, w.path, w.message)
+		}
+	}
+	if !strings.Contains(err.Error(), "3 issues\n\tbody > form#signup > circle:") {
+		t.Errorf("error text = %s", err)
+	}
+
+	if RenderToString(template) != "" {
+		t.Error("RenderToString should return an empty string on error")
+	}
+}
+
+// statusText is a string type, which strict mode accepts like a string.
+type statusText string
+
+func TestWithStrictOverridesDefault(t *testing.T) {
+	template := func(b *Builder) Node { return b.Path(D("M0 0"), "text") }
+
+	var buf strings.Builder
+	if err := RenderWithContext(WithStrict(context.Background(), true), template, &buf); err == nil {
+		t.Error("expected strict error from context")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("nothing should be written on a strict error, got %q", buf.String())
+	}
+
+	SetStrict(true)
+	defer SetStrict(false)
+	if err := RenderWithContext(WithStrict(context.Background(), false), template, &buf); err != nil {
+		t.Errorf("WithStrict(false) should override SetStrict: %v", err)
+	}
+}
+
+func TestStrictIssuesIgnoresDetachedElements(t *testing.T) {
+	B.Rect("unused")
+	root := B.Svg(B.Rect(B.Title("tooltip")))
+	issues := StrictIssues(root)
+	if len(issues) != 1 || issues[0].String() != "svg > rect: <title> element passed to self-closing <rect> was dropped" {
+		t.Errorf("issues = %v", issues)
+	}
+}
@@ -0,0 +1,306 @@
+package minty
+
+import (
+	"sort"
+	"strings"
+)
+
+// Tailwind class merging
+//
+// Plain class merging keeps every token, so "px-4" from a theme and
+// "px-2" from a wrapper both end up on the element and the stylesheet
+// order decides. TailwindClass resolves such conflicts the way the caller
+// means them: the later utility wins.
+//
+//	tw.Button("Save", "primary", mi.TailwindClass("px-2 bg-emerald-600"))
+//
+// Utilities conflict when they set the same property under the same
+// variants, so "hover:bg-red-500" leaves "bg-blue-500" alone. A utility
+// also replaces the narrower ones it covers: "p-4" removes an earlier
+// "px-2", while "px-2" after "p-4" refines it, and "text-sm/6" sets the
+// line height too, so it removes an earlier "leading-7". The resolver
+// knows the common utility groups; classes it does not recognise are kept
+// as is.
+
+// TailwindClass adds classes to the class attribute, removing earlier
+// Tailwind utilities they conflict with.
+func TailwindClass(value string) Attribute {
+	return tailwindClass(value)
+}
+
+type tailwindClass string
+
+// Apply merges the classes into the element's class attribute.
+func (c tailwindClass) Apply(element *Element) {
+	existing, _ := element.Attributes.Get("class")
+	element.Attributes.Set("class", TailwindMerge(existing, string(c)))
+}
+
+// TailwindMerge joins class lists, dropping duplicates and any utility
+// overridden by a later conflicting one.
+func TailwindMerge(classes ...string) string {
+	type entry struct {
+		token, variants, group string // group is "" when unrecognised
+	}
+	var entries []entry
+	for _, list := range classes {
+		for _, token := range strings.Fields(list) {
+			variants, group := tailwindGroup(token)
+			kept := entries[:0]
+			for _, prev := range entries {
+				conflicts := group != "" && prev.group != "" && prev.variants == variants &&
+					(tailwindBase(prev.group) == tailwindBase(group) || tailwindCovers(group, prev.group))
+				if prev.token != token && !conflicts {
+					kept = append(kept, prev)
+				}
+			}
+			entries = append(kept, entry{token, variants, group})
+		}
+	}
+	tokens := make([]string, len(entries))
+	for i, e := range entries {
+		tokens[i] = e.token
+	}
+	return strings.Join(tokens, " ")
+}
+
+// tailwindCovers reports whether utilities in group set every property of
+// the narrower group.
+func tailwindCovers(group, narrower string) bool {
+	for _, g := range tailwindSubgroups[group] {
+		if g == narrower || tailwindCovers(g, narrower) {
+			return true
+		}
+	}
+	return false
+}
+
+var tailwindSubgroups = map[string][]string{
+	"p":         {"px", "py", "ps", "pe"},
+	"px":        {"pl", "pr"},
+	"py":        {"pt", "pb"},
+	"m":         {"mx", "my", "ms", "me"},
+	"mx":        {"ml", "mr"},
+	"my":        {"mt", "mb"},
+	"inset":     {"inset-x", "inset-y"},
+	"inset-x":   {"left", "right"},
+	"inset-y":   {"top", "bottom"},
+	"size":      {"w", "h"},
+	"gap":       {"gap-x", "gap-y"},
+	"overflow":  {"overflow-x", "overflow-y"},
+	"scale":     {"scale-x", "scale-y"},
+	"rounded":   {"rounded-t", "rounded-r", "rounded-b", "rounded-l"},
+	"rounded-t": {"rounded-tl", "rounded-tr"},
+	"rounded-r": {"rounded-tr", "rounded-br"},
+	"rounded-b": {"rounded-bl", "rounded-br"},
+	"rounded-l": {"rounded-tl", "rounded-bl"},
+	// text-sm/6
+	"text-size-leading": {"leading"},
+}
+
+// tailwindBase returns the group that a group setting extra properties
+// otherwise belongs to, so that text-lg and text-sm/6 replace each other.
+func tailwindBase(group string) string {
+	if group == "text-size-leading" {
+		return "text-size"
+	}
+	return group
+}
+
+// tailwindKeywords are utilities without a value, by group.
+var tailwindKeywords = map[string]string{}
+
+func init() {
+	for group, words := range map[string]string{
+		"display":         "block inline-block inline flex inline-flex grid inline-grid hidden table table-row table-cell contents flow-root list-item",
+		"position":        "static fixed absolute relative sticky",
+		"visibility":      "visible invisible collapse",
+		"font-style":      "italic not-italic",
+		"text-decoration": "underline overline line-through no-underline",
+		"text-transform":  "uppercase lowercase capitalize normal-case",
+		"truncate":        "truncate text-ellipsis text-clip",
+	} {
+		for _, w := range strings.Fields(words) {
+			tailwindKeywords[w] = group
+		}
+	}
+	sort.Slice(tailwindPrefixes, func(i, j int) bool {
+		return len(tailwindPrefixes[i]) > len(tailwindPrefixes[j])
+	})
+}
+
+// tailwindPrefixes are utilities whose prefix names the group. Longer
+// prefixes are matched first.
+var tailwindPrefixes = strings.Fields(`
+	p px py pt pr pb pl ps pe m mx my mt mr mb ml ms me
+	w h min-w min-h max-w max-h size basis grow shrink order
+	gap gap-x gap-y space-x space-y inset inset-x inset-y top right bottom left z
+	rounded rounded-t rounded-r rounded-b rounded-l rounded-tl rounded-tr rounded-bl rounded-br
+	grid-cols grid-rows col-span row-span col-start col-end row-start row-end columns
+	justify justify-items justify-self items self content place-content place-items place-self
+	overflow overflow-x overflow-y overscroll cursor select whitespace line-clamp aspect
+	opacity leading tracking indent align list decoration underline-offset
+	duration ease delay transition origin rotate scale scale-x scale-y
+	translate-x translate-y skew-x skew-y
+	text font border bg flex ring outline outline-offset shadow object
+`)
+
+// tailwindGroup splits a class into its variant prefix and utility group.
+// The group is empty for classes the resolver does not recognise.
+func tailwindGroup(token string) (variants, group string) {
+	utility := token
+	depth := 0
+	for i := 0; i < len(token); i++ {
+		switch token[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ':':
+			if depth == 0 {
+				variants, utility = token[:i+1], token[i+1:]
+			}
+		}
+	}
+	if strings.HasPrefix(utility, "!") {
+		variants += "!"
+		utility = utility[1:]
+	}
+	utility = strings.TrimPrefix(utility, "-")
+
+	if g, ok := tailwindKeywords[utility]; ok {
+		return variants, g
+	}
+	for _, prefix := range tailwindPrefixes {
+		if utility != prefix && !strings.HasPrefix(utility, prefix+"-") {
+			continue
+		}
+		value := strings.TrimPrefix(utility[len(prefix):], "-")
+		modifier := false
+		if i := strings.IndexByte(value, '/'); i >= 0 && !strings.HasPrefix(value, "[") {
+			value, modifier = value[:i], true // text-sm/6, bg-black/50
+		}
+		group = tailwindValueGroup(prefix, value)
+		if group == "text-size" && modifier {
+			group = "text-size-leading"
+		}
+		return variants, group
+	}
+	return variants, ""
+}
+
+// tailwindValueGroup splits prefixes shared by several properties, such
+// as text-lg (font size) and text-red-500 (colour).
+func tailwindValueGroup(prefix, value string) string {
+	switch prefix {
+	case "text":
+		switch {
+		case tailwindIn(value, "xs sm base lg xl 2xl 3xl 4xl 5xl 6xl 7xl 8xl 9xl") || tailwindArbitraryLength(value):
+			return "text-size"
+		case tailwindIn(value, "left center right justify start end"):
+			return "text-align"
+		case tailwindIn(value, "wrap nowrap balance pretty"):
+			return "text-wrap"
+		}
+		return "text-color"
+	case "font":
+		if tailwindIn(value, "thin extralight light normal medium semibold bold extrabold black") {
+			return "font-weight"
+		}
+		return "font-family"
+	case "border":
+		side := ""
+		if parts := strings.SplitN(value, "-", 2); tailwindIn(parts[0], "x y t r b l s e") {
+			side, value = "-"+parts[0], ""
+			if len(parts) == 2 {
+				value = parts[1]
+			}
+		}
+		switch {
+		case value == "" || tailwindNumber(value) || tailwindArbitraryLength(value):
+			return "border" + side + "-width"
+		case side == "" && tailwindIn(value, "solid dashed dotted double hidden none"):
+			return "border-style"
+		case side == "" && tailwindIn(value, "collapse separate"):
+			return "border-collapse"
+		}
+		return "border" + side + "-color"
+	case "bg":
+		switch {
+		case tailwindIn(value, "fixed local scroll"):
+			return "bg-attachment"
+		case tailwindIn(value, "auto cover contain"):
+			return "bg-size"
+		case strings.HasPrefix(value, "repeat") || value == "no-repeat":
+			return "bg-repeat"
+		case tailwindIn(value, "bottom center left left-bottom left-top right right-bottom right-top top"):
+			return "bg-position"
+		case value == "none" || strings.HasPrefix(value, "gradient"):
+			return "bg-image"
+		}
+		return "bg-color"
+	case "flex":
+		switch {
+		case tailwindIn(value, "row row-reverse col col-reverse"):
+			return "flex-direction"
+		case tailwindIn(value, "wrap wrap-reverse nowrap"):
+			return "flex-wrap"
+		}
+		return "flex"
+	case "ring":
+		switch {
+		case value == "" || tailwindNumber(value) || tailwindArbitraryLength(value):
+			return "ring-width"
+		case value == "inset":
+			return "ring-inset"
+		}
+		return "ring-color"
+	case "outline":
+		switch {
+		case value == "" || tailwindNumber(value) || tailwindArbitraryLength(value):
+			return "outline-width"
+		case tailwindIn(value, "none solid dashed dotted double hidden"):
+			return "outline-style"
+		}
+		return "outline-color"
+	case "shadow":
+		if value == "" || tailwindIn(value, "sm md lg xl 2xl inner none") {
+			return "shadow"
+		}
+		return "shadow-color"
+	case "object":
+		if tailwindIn(value, "contain cover fill none scale-down") {
+			return "object-fit"
+		}
+		return "object-position"
+	}
+	return prefix
+}
+
+func tailwindIn(value, words string) bool {
+	return containsToken(strings.Fields(words), value)
+}
+
+func tailwindNumber(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
+
+// tailwindArbitraryLength reports whether value is an arbitrary value
+// such as [14px] rather than a colour such as [#1e293b].
+func tailwindArbitraryLength(value string) bool {
+	if !strings.HasPrefix(value, "[") {
+		return false
+	}
+	inner := strings.TrimPrefix(value, "[")
+	for _, colour := range []string{"#", "rgb", "hsl", "color:", "oklch"} {
+		if strings.HasPrefix(inner, colour) {
+			return false
+		}
+	}
+	return true
+}
@@ -86,7 +86,7 @@ func (t *BootstrapTheme) Badge(text, variant string) mi.H {
 // FormInput creates a Bootstrap form input with label
 func (t *BootstrapTheme) FormInput(label, name, inputType string, attrs ...mi.Attribute) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		id := "input_" + name
+		id := b.UID("input_" + name)
 		inputAttrs := append([]mi.Attribute{
 			mi.Class("form-control"),
 			mi.ID(id),
@@ -104,7 +104,7 @@ func (t *BootstrapTheme) FormInput(label, name, inputType string, attrs ...mi.At
 // FormSelect creates a Bootstrap select dropdown with label
 func (t *BootstrapTheme) FormSelect(label, name string, options []mui.SelectOption) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		id := "select_" + name
+		id := b.UID("select_" + name)
 		
 		optionNodes := make([]mi.Node, len(options))
 		for i, option := range options {
@@ -131,7 +131,7 @@ func (t *BootstrapTheme) FormSelect(label, name string, options []mui.SelectOpti
 // FormTextarea creates a Bootstrap textarea with label
 func (t *BootstrapTheme) FormTextarea(label, name string, attrs ...mi.Attribute) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		id := "textarea_" + name
+		id := b.UID("textarea_" + name)
 		textareaAttrs := append([]mi.Attribute{
 			mi.Class("form-control"),
 			mi.ID(id),
@@ -154,7 +154,7 @@ func (t *BootstrapTheme) FormTextarea(label, name string, attrs ...mi.Attribute)
 // FormLabel creates a Bootstrap form label
 func (t *BootstrapTheme) FormLabel(text, forField string) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		return b.Label(mi.Class("form-label"), mi.DataAttr("for", forField), text)
+		return b.Label(mi.Class("form-label"), mi.For(forField), text)
 	}
 }
 
@@ -463,39 +463,45 @@ func minInt(a, b int) int {
 // CSS AND SCRIPTS
 // =====================================================
 
-// CDNLinks returns Bootstrap 5 CDN links for inclusion in HTML head
+// CDNLinks returns Bootstrap 5 CDN links for inclusion in HTML head.
+// It is registered as a page asset, so it is emitted once per page.
 func CDNLinks() mi.H {
 	return func(b *mi.Builder) mi.Node {
-		return mi.NewFragment(
-			// Bootstrap CSS
-			b.Link(
-				mi.Href("https://cdn.jsdelivr.net/npm/bootstrap@5.3.0/dist/css/bootstrap.min.css"),
-				mi.Rel("stylesheet"),
-				mi.DataAttr("integrity", "sha384-9ndCyUa+IgAaWhp066j+EugYkAuULlhkTAP0O7D4C/ZIyIbOrN4ySHXKdZJh3jP6"),
-				mi.DataAttr("crossorigin", "anonymous"),
-			),
-			// Bootstrap Icons
-			b.Link(
-				mi.Href("https://cdn.jsdelivr.net/npm/bootstrap-icons@1.10.0/font/bootstrap-icons.css"),
-				mi.Rel("stylesheet"),
-			),
-		)
+		return b.Require(mi.HeadAsset("bootstrap-css", func(b *mi.Builder) mi.Node {
+			return mi.NewFragment(
+				// Bootstrap CSS
+				b.Link(
+					mi.Href("https://cdn.jsdelivr.net/npm/bootstrap@5.3.0/dist/css/bootstrap.min.css"),
+					mi.Rel("stylesheet"),
+					mi.DataAttr("integrity", "sha384-9ndCyUa+IgAaWhp066j+EugYkAuULlhkTAP0O7D4C/ZIyIbOrN4ySHXKdZJh3jP6"),
+					mi.DataAttr("crossorigin", "anonymous"),
+				),
+				// Bootstrap Icons
+				b.Link(
+					mi.Href("https://cdn.jsdelivr.net/npm/bootstrap-icons@1.10.0/font/bootstrap-icons.css"),
+					mi.Rel("stylesheet"),
+				),
+			)
+		}))
 	}
 }
 
-// CDNScripts returns Bootstrap 5 CDN scripts for inclusion before closing body tag
+// CDNScripts returns Bootstrap 5 CDN scripts for inclusion before closing body tag.
+// It is registered as a page asset, so it is emitted once per page.
 func CDNScripts() mi.H {
 	return func(b *mi.Builder) mi.Node {
-		return b.Script(
-			mi.Src("https://cdn.jsdelivr.net/npm/bootstrap@5.3.0/dist/js/bootstrap.bundle.min.js"),
-			mi.DataAttr("integrity", "sha384-geWF76RCwLtnZ8qwWowPQNguL3RmwHVBC9FhGdlKrxdiJJigb/j/68SIy3Te4Bkz"),
-			mi.DataAttr("crossorigin", "anonymous"),
-		)
+		return b.Require(mi.BodyAsset("bootstrap-js", func(b *mi.Builder) mi.Node {
+			return b.Script(
+				mi.Src("https://cdn.jsdelivr.net/npm/bootstrap@5.3.0/dist/js/bootstrap.bundle.min.js"),
+				mi.DataAttr("integrity", "sha384-geWF76RCwLtnZ8qwWowPQNguL3RmwHVBC9FhGdlKrxdiJJigb/j/68SIy3Te4Bkz"),
+				mi.DataAttr("crossorigin", "anonymous"),
+			)
+		}))
 	}
 }
 
 // BootstrapDocument creates a complete HTML document with Bootstrap styling
-func BootstrapDocument(title string, content mi.H) mi.H {
+func BootstrapDocument(title string, content mi.H, opts ...mi.DocumentOption) mi.H {
 	return func(b *mi.Builder) mi.Node {
 		return mi.Document(title,
 			[]mi.Node{
@@ -507,6 +513,7 @@ func BootstrapDocument(title string, content mi.H) mi.H {
 				),
 				CDNScripts()(b),
 			),
+			opts...,
 		)(b)
 	}
 }
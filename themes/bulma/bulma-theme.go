@@ -88,7 +88,7 @@ func (t *BulmaTheme) Badge(text, variant string) mi.H {
 // FormInput creates a Bulma form input with label
 func (t *BulmaTheme) FormInput(label, name, inputType string, attrs ...mi.Attribute) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		id := "input_" + name
+		id := b.UID("input_" + name)
 		inputAttrs := append([]mi.Attribute{
 			mi.Class("input"),
 			mi.ID(id),
@@ -108,7 +108,7 @@ func (t *BulmaTheme) FormInput(label, name, inputType string, attrs ...mi.Attrib
 // FormSelect creates a Bulma select dropdown with label
 func (t *BulmaTheme) FormSelect(label, name string, options []mui.SelectOption) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		id := "select_" + name
+		id := b.UID("select_" + name)
 		
 		optionNodes := make([]mi.Node, len(options))
 		for i, option := range options {
@@ -139,7 +139,7 @@ func (t *BulmaTheme) FormSelect(label, name string, options []mui.SelectOption)
 // FormTextarea creates a Bulma textarea with label
 func (t *BulmaTheme) FormTextarea(label, name string, attrs ...mi.Attribute) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		id := "textarea_" + name
+		id := b.UID("textarea_" + name)
 		textareaAttrs := append([]mi.Attribute{
 			mi.Class("textarea"),
 			mi.ID(id),
@@ -164,7 +164,7 @@ func (t *BulmaTheme) FormTextarea(label, name string, attrs ...mi.Attribute) mi.
 // FormLabel creates a Bulma form label
 func (t *BulmaTheme) FormLabel(text, forField string) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		return b.Label(mi.Class("label"), mi.DataAttr("for", forField), text)
+		return b.Label(mi.Class("label"), mi.For(forField), text)
 	}
 }
 
@@ -479,26 +479,29 @@ func minInt(a, b int) int {
 // CSS AND SCRIPTS
 // =====================================================
 
-// CDNLinks returns Bulma CSS CDN links for inclusion in HTML head
+// CDNLinks returns Bulma CSS CDN links for inclusion in HTML head.
+// It is registered as a page asset, so it is emitted once per page.
 func CDNLinks() mi.H {
 	return func(b *mi.Builder) mi.Node {
-		return mi.NewFragment(
-			// Bulma CSS
-			b.Link(
-				mi.Href("https://cdn.jsdelivr.net/npm/bulma@0.9.4/css/bulma.min.css"),
-				mi.Rel("stylesheet"),
-			),
-			// Font Awesome for icons
-			b.Link(
-				mi.Href("https://cdnjs.cloudflare.com/ajax/libs/font-awesome/6.4.0/css/all.min.css"),
-				mi.Rel("stylesheet"),
-			),
-		)
+		return b.Require(mi.HeadAsset("bulma-css", func(b *mi.Builder) mi.Node {
+			return mi.NewFragment(
+				// Bulma CSS
+				b.Link(
+					mi.Href("https://cdn.jsdelivr.net/npm/bulma@0.9.4/css/bulma.min.css"),
+					mi.Rel("stylesheet"),
+				),
+				// Font Awesome for icons
+				b.Link(
+					mi.Href("https://cdnjs.cloudflare.com/ajax/libs/font-awesome/6.4.0/css/all.min.css"),
+					mi.Rel("stylesheet"),
+				),
+			)
+		}))
 	}
 }
 
 // BulmaDocument creates a complete HTML document with Bulma styling
-func BulmaDocument(title string, content mi.H) mi.H {
+func BulmaDocument(title string, content mi.H, opts ...mi.DocumentOption) mi.H {
 	return func(b *mi.Builder) mi.Node {
 		return mi.Document(title,
 			[]mi.Node{
@@ -511,6 +514,7 @@ func BulmaDocument(title string, content mi.H) mi.H {
 					),
 				),
 			),
+			opts...,
 		)(b)
 	}
 }
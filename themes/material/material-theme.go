@@ -100,17 +100,19 @@ func (t *MaterialTheme) Badge(text, variant string) mi.H {
 // FormInput creates a Material Design text field with label
 func (t *MaterialTheme) FormInput(label, name, inputType string, attrs ...mi.Attribute) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		id := "input_" + name
+		id := b.UID("input_" + name)
+		labelID := b.UID(id + "_label")
 		inputAttrs := append([]mi.Attribute{
 			mi.Class("mdc-text-field__input"),
 			mi.ID(id),
 			mi.Name(name),
 			mi.Type(inputType),
+			mi.AriaLabelledby(labelID),
 		}, attrs...)
 		
 		return b.Div(mi.Class("mdc-text-field mdc-text-field--filled"),
 			b.Span(mi.Class("mdc-text-field__ripple")),
-			b.Span(mi.Class("mdc-floating-label"), mi.DataAttr("for", id), label),
+			b.Span(mi.Class("mdc-floating-label"), mi.ID(labelID), label),
 			b.Input(inputAttrs...),
 			b.Span(mi.Class("mdc-line-ripple")),
 		)
@@ -179,12 +181,14 @@ func (t *MaterialTheme) FormSelect(label, name string, options []mui.SelectOptio
 // FormTextarea creates a Material Design textarea with label
 func (t *MaterialTheme) FormTextarea(label, name string, attrs ...mi.Attribute) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		id := "textarea_" + name
+		id := b.UID("textarea_" + name)
+		labelID := b.UID(id + "_label")
 		textareaAttrs := append([]mi.Attribute{
 			mi.Class("mdc-text-field__input"),
 			mi.ID(id),
 			mi.Name(name),
 			mi.Rows(4),
+			mi.AriaLabelledby(labelID),
 		}, attrs...)
 		
 		// Convert to []interface{} for Textarea
@@ -198,7 +202,7 @@ func (t *MaterialTheme) FormTextarea(label, name string, attrs ...mi.Attribute)
 			b.Span(mi.Class("mdc-text-field__resizer"),
 				b.Textarea(args...),
 			),
-			b.Span(mi.Class("mdc-floating-label"), mi.DataAttr("for", id), label),
+			b.Span(mi.Class("mdc-floating-label"), mi.ID(labelID), label),
 			b.Span(mi.Class("mdc-line-ripple")),
 		)
 	}
@@ -207,7 +211,7 @@ func (t *MaterialTheme) FormTextarea(label, name string, attrs ...mi.Attribute)
 // FormLabel creates a Material Design form label
 func (t *MaterialTheme) FormLabel(text, forField string) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		return b.Label(mi.Class("mdc-floating-label"), mi.DataAttr("for", forField), text)
+		return b.Label(mi.Class("mdc-floating-label"), mi.For(forField), text)
 	}
 }
 
@@ -531,55 +535,61 @@ func minInt(a, b int) int {
 // CSS AND SCRIPTS
 // =====================================================
 
-// CDNLinks returns Material Design Components CDN links for inclusion in HTML head
+// CDNLinks returns Material Design Components CDN links for inclusion in HTML head.
+// It is registered as a page asset, so it is emitted once per page.
 func CDNLinks() mi.H {
 	return func(b *mi.Builder) mi.Node {
-		return mi.NewFragment(
-			// Material Design Components CSS
-			b.Link(
-				mi.Href("https://unpkg.com/material-components-web@latest/dist/material-components-web.min.css"),
-				mi.Rel("stylesheet"),
-			),
-			// Material Icons
-			b.Link(
-				mi.Href("https://fonts.googleapis.com/icon?family=Material+Icons"),
-				mi.Rel("stylesheet"),
-			),
-			// Roboto Font (Material Design typography)
-			b.Link(
-				mi.Href("https://fonts.googleapis.com/css2?family=Roboto:wght@300;400;500;700&display=swap"),
-				mi.Rel("stylesheet"),
-			),
-		)
+		return b.Require(mi.HeadAsset("material-css", func(b *mi.Builder) mi.Node {
+			return mi.NewFragment(
+				// Material Design Components CSS
+				b.Link(
+					mi.Href("https://unpkg.com/material-components-web@latest/dist/material-components-web.min.css"),
+					mi.Rel("stylesheet"),
+				),
+				// Material Icons
+				b.Link(
+					mi.Href("https://fonts.googleapis.com/icon?family=Material+Icons"),
+					mi.Rel("stylesheet"),
+				),
+				// Roboto Font (Material Design typography)
+				b.Link(
+					mi.Href("https://fonts.googleapis.com/css2?family=Roboto:wght@300;400;500;700&display=swap"),
+					mi.Rel("stylesheet"),
+				),
+			)
+		}))
 	}
 }
 
-// CDNScripts returns Material Design Components CDN scripts for inclusion before closing body tag
+// CDNScripts returns Material Design Components CDN scripts for inclusion before closing body tag.
+// It is registered as a page asset, so it is emitted once per page.
 func CDNScripts() mi.H {
 	return func(b *mi.Builder) mi.Node {
-		return mi.NewFragment(
-			b.Script(
-				mi.Src("https://unpkg.com/material-components-web@latest/dist/material-components-web.min.js"),
-			),
-			// Initialize Material Components
-			b.Script(`
-				// Auto-initialize all MDC components
-				window.mdc.autoInit();
+		return b.Require(mi.BodyAsset("material-js", func(b *mi.Builder) mi.Node {
+			return mi.NewFragment(
+				b.Script(
+					mi.Src("https://unpkg.com/material-components-web@latest/dist/material-components-web.min.js"),
+				),
+				// Initialize Material Components
+				b.Script(`
+					// Auto-initialize all MDC components
+					window.mdc.autoInit();
 				
-				// Initialize ripples for buttons
-				const buttons = document.querySelectorAll('.mdc-button');
-				buttons.forEach((button) => {
-					if (button.querySelector('.mdc-button__ripple')) {
-						window.mdc.ripple.MDCRipple.attachTo(button);
-					}
-				});
-			`),
-		)
+					// Initialize ripples for buttons
+					const buttons = document.querySelectorAll('.mdc-button');
+					buttons.forEach((button) => {
+						if (button.querySelector('.mdc-button__ripple')) {
+							window.mdc.ripple.MDCRipple.attachTo(button);
+						}
+					});
+				`),
+			)
+		}))
 	}
 }
 
 // MaterialDocument creates a complete HTML document with Material Design styling
-func MaterialDocument(title string, content mi.H) mi.H {
+func MaterialDocument(title string, content mi.H, opts ...mi.DocumentOption) mi.H {
 	return func(b *mi.Builder) mi.Node {
 		return mi.Document(title,
 			[]mi.Node{
@@ -605,6 +615,7 @@ func MaterialDocument(title string, content mi.H) mi.H {
 				),
 				CDNScripts()(b),
 			),
+			opts...,
 		)(b)
 	}
 }
@@ -86,7 +86,7 @@ func (t *TailwindTheme) Badge(text, variant string) mi.H {
 // FormInput creates a Tailwind form input with label
 func (t *TailwindTheme) FormInput(label, name, inputType string, attrs ...mi.Attribute) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		id := "input_" + name
+		id := b.UID("input_" + name)
 		inputAttrs := append([]mi.Attribute{
 			mi.Class("mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 sm:text-sm"),
 			mi.ID(id),
@@ -104,7 +104,7 @@ func (t *TailwindTheme) FormInput(label, name, inputType string, attrs ...mi.Att
 // FormSelect creates a Tailwind select dropdown with label
 func (t *TailwindTheme) FormSelect(label, name string, options []mui.SelectOption) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		id := "select_" + name
+		id := b.UID("select_" + name)
 		
 		optionNodes := make([]mi.Node, len(options))
 		for i, option := range options {
@@ -133,7 +133,7 @@ func (t *TailwindTheme) FormSelect(label, name string, options []mui.SelectOptio
 // FormTextarea creates a Tailwind textarea with label
 func (t *TailwindTheme) FormTextarea(label, name string, attrs ...mi.Attribute) mi.H {
 	return func(b *mi.Builder) mi.Node {
-		id := "textarea_" + name
+		id := b.UID("textarea_" + name)
 		textareaAttrs := append([]mi.Attribute{
 			mi.Class("mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 sm:text-sm"),
 			mi.ID(id),
@@ -159,7 +159,7 @@ func (t *TailwindTheme) FormLabel(text, forField string) mi.H {
 	return func(b *mi.Builder) mi.Node {
 		return b.Label(
 			mi.Class("block text-sm font-medium text-gray-700"),
-			mi.DataAttr("for", forField),
+			mi.For(forField),
 			text,
 		)
 	}
@@ -492,38 +492,41 @@ func minInt(a, b int) int {
 // CSS AND SCRIPTS
 // =====================================================
 
-// CDNLinks returns Tailwind CSS CDN links for inclusion in HTML head
+// CDNLinks returns Tailwind CSS CDN links for inclusion in HTML head.
+// It is registered as a page asset, so it is emitted once per page.
 func CDNLinks() mi.H {
 	return func(b *mi.Builder) mi.Node {
-		return mi.NewFragment(
-			// Tailwind CSS
-			b.Link(
-				mi.Href("https://cdn.tailwindcss.com"),
-				mi.Rel("stylesheet"),
-			),
-			// Optional: Add custom Tailwind config
-			b.Script(`
-				tailwind.config = {
-					theme: {
-						extend: {
-							colors: {
-								primary: {
-									50: '#eff6ff',
-									500: '#3b82f6',
-									600: '#2563eb',
-									700: '#1d4ed8',
+		return b.Require(mi.HeadAsset("tailwind-cdn", func(b *mi.Builder) mi.Node {
+			return mi.NewFragment(
+				// Tailwind CSS
+				b.Link(
+					mi.Href("https://cdn.tailwindcss.com"),
+					mi.Rel("stylesheet"),
+				),
+				// Optional: Add custom Tailwind config
+				b.Script(`
+					tailwind.config = {
+						theme: {
+							extend: {
+								colors: {
+									primary: {
+										50: '#eff6ff',
+										500: '#3b82f6',
+										600: '#2563eb',
+										700: '#1d4ed8',
+									}
 								}
 							}
 						}
 					}
-				}
-			`),
-		)
+				`),
+			)
+		}))
 	}
 }
 
 // TailwindDocument creates a complete HTML document with Tailwind styling
-func TailwindDocument(title string, content mi.H) mi.H {
+func TailwindDocument(title string, content mi.H, opts ...mi.DocumentOption) mi.H {
 	return func(b *mi.Builder) mi.Node {
 		return mi.Document(title,
 			[]mi.Node{
@@ -534,6 +537,7 @@ func TailwindDocument(title string, content mi.H) mi.H {
 					content(b),
 				),
 			),
+			opts...,
 		)(b)
 	}
 }
@@ -0,0 +1,129 @@
+package minty
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Render-scoped IDs
+//
+// Labels, descriptions and error messages refer to their fields by id, so
+// the ids must be unique on the page. Deriving them from field names
+// breaks as soon as a form is rendered twice, as in a list of inline
+// editors. b.UID hands out ids that are unique within one render and
+// stable across renders of the same page:
+//
+//	id := b.UID("email")  // "email", then "email-2", "email-3"...
+//	b.Label(mi.For(id), "Email")
+//	b.Input(mi.ID(id), mi.Name("email"))
+//
+// The builders created by Render, RenderWithContext and RenderStream each
+// carry a generator. Ids set explicitly with mi.ID are not tracked, so
+// avoid bases that clash with them.
+
+// idGenerator issues unique ids for one render.
+type idGenerator struct {
+	mu     sync.Mutex
+	prefix string
+	issued map[string]bool
+}
+
+// next returns base, or base with the lowest free numeric suffix.
+func (g *idGenerator) next(base string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.issued == nil {
+		g.issued = make(map[string]bool)
+	}
+	base = g.prefix + idBase(base)
+	id := base
+	for n := 2; g.issued[id]; n++ {
+		id = base + "-" + strconv.Itoa(n)
+	}
+	g.issued[id] = true
+	return id
+}
+
+// reserve marks ids as taken.
+func (g *idGenerator) reserve(ids []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.issued == nil {
+		g.issued = make(map[string]bool)
+	}
+	for _, id := range ids {
+		g.issued[id] = true
+	}
+}
+
+// claim reserves ids and returns the first one that was already taken,
+// or "" if none was.
+func (g *idGenerator) claim(ids []string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.issued == nil {
+		g.issued = make(map[string]bool)
+	}
+	taken := ""
+	for _, id := range ids {
+		if g.issued[id] && taken == "" {
+			taken = id
+		}
+		g.issued[id] = true
+	}
+	return taken
+}
+
+// list returns the issued ids.
+func (g *idGenerator) list() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ids := make([]string, 0, len(g.issued))
+	for id := range g.issued {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// unscopedIDs numbers ids built with a builder outside a render, such as
+// the global B. They are unique in the process but not stable.
+var unscopedIDs atomic.Uint64
+
+// UID returns an id derived from base that is unique within the render.
+// The first id for a base is the base itself, so ids stay readable;
+// characters not allowed in a plain id are replaced by hyphens.
+//
+// Builders without a render scope, such as the global B, append a
+// process-wide counter instead.
+func (b *Builder) UID(base string) string {
+	if b == nil || b.ids == nil {
+		return idBase(base) + "-u" + strconv.FormatUint(unscopedIDs.Add(1), 10)
+	}
+	return b.ids.next(base)
+}
+
+// idBase turns base into a readable id: letters, digits, '-' and '_',
+// with other runs collapsed into single hyphens.
+func idBase(base string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range base {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			sb.WriteRune(r)
+			hyphen = false
+		default:
+			if !hyphen && sb.Len() > 0 {
+				sb.WriteByte('-')
+				hyphen = true
+			}
+		}
+	}
+	id := strings.TrimRight(sb.String(), "-")
+	if id == "" {
+		return "id"
+	}
+	return id
+}
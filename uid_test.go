@@ -0,0 +1,79 @@
+package minty
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUIDIsScopedToRender(t *testing.T) {
+	var ids []string
+	template := func(b *Builder) Node {
+		ids = append(ids, b.UID("email"), b.UID("email"), b.UID("email-2"), b.UID("user[0].name"), b.UID("!!"))
+		return NewFragment()
+	}
+	RenderToString(template)
+	first := strings.Join(ids, " ")
+	if first != "email email-2 email-2-2 user-0-name id" {
+		t.Errorf("ids = %s", first)
+	}
+
+	ids = nil
+	RenderToString(template)
+	if again := strings.Join(ids, " "); again != first {
+		t.Errorf("ids should be stable across renders: %s then %s", first, again)
+	}
+
+	if a, b := B.UID("x"), B.UID("x"); a == b || !strings.HasPrefix(a, "x-u") {
+		t.Errorf("unscoped ids %q and %q should be unique", a, b)
+	}
+}
+
+func TestFormFieldsUseUniqueIDs(t *testing.T) {
+	editor := func(b *Builder) Node {
+		return b.Form(
+			FormField("Email", "email", "email", "", "Email is required", true)(b),
+			SelectField("Role", "role", "", "", false, []SelectOption{{Value: "admin", Text: "Admin"}})(b),
+		)
+	}
+	html := RenderToString(func(b *Builder) Node {
+		return b.Div(editor(b), editor(b), FormField("Email", "email", "email", "", "", false, ID("custom"))(b))
+	})
+
+	for _, want := range []string{
+		`<label for="email">Email</label><input id="email" type="email" name="email" required="required" aria-invalid="true" aria-describedby="email-error" />`,
+		`margin: 0.5rem 0;" id="email-error"><p>Email is required</p>`,
+		`<label for="email-2">Email</label><input id="email-2"`,
+		`aria-describedby="email-error-2"`,
+		`<label for="role-2">Role</label><select id="role-2" name="role">`,
+		`<label for="custom">Email</label><input id="custom"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %s in\n%s", want, html)
+		}
+	}
+}
+
+func TestUIDInCachedAndCompiledTemplates(t *testing.T) {
+	cache := NewLRUCache(8)
+	field := func(b *Builder) Node { return b.Input(ID(b.UID("q"))) }
+	html := RenderToString(func(b *Builder) Node {
+		return b.Div(field(b), Cached("search box", 0, field, InCache(cache))(b))
+	})
+	if html != `<div><input id="q" /><input id="search-box-q" /></div>` {
+		t.Errorf("cached ids should be prefixed with the key: %s", html)
+	}
+
+	_, err := RenderToStringE(func(b *Builder) Node {
+		search := Cached("search box", 0, field, InCache(cache))
+		return b.Div(search(b), search(b))
+	})
+	if err == nil || !strings.Contains(err.Error(), `repeats id "search-box-q"`) {
+		t.Errorf("a cached fragment used twice on a page should fail, got %v", err)
+	}
+
+	body := NewHole[H]("body")
+	page := MustCompile(func(b *Builder) Node { return b.Div(field(b), body) })
+	if html := RenderToString(page.With(body.Fill(field))); html != `<div><input id="q" /><input id="q-2" /></div>` {
+		t.Errorf("hole content should not reuse ids from the static parts: %s", html)
+	}
+}
@@ -0,0 +1,351 @@
+package minty
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tree validation
+//
+// The builders accept any nesting, so b.P(b.Div(...)), b.Ul(b.Span(...))
+// or a form inside a form render without complaint; browsers then repair
+// the markup in ways that break styling, htmx swaps and scripts.
+// ValidateTree checks an evaluated tree against the HTML content model and
+// reports duplicate ids and references to ids that do not exist:
+//
+//	if issues := mi.ValidateTree(page(mi.NewBuilder(ctx))); len(issues) > 0 {
+//	    log.Print(issues)
+//	}
+//
+// Reference checks cover hx-target, hx-include and hx-indicator values
+// that are #id selectors, and the targetId of mintydyn dependency rules.
+// They run when the tree is a whole document; a fragment such as an htmx
+// response refers to the page around it, whose ids can be passed with
+// KnownIDs.
+//
+// The content model rules are a practical subset of HTML5: phrasing
+// content, list, table and select children, and nested forms, labels,
+// interactive elements, headers and footers. SVG and MathML subtrees are
+// not checked.
+
+// ValidateOptions configures ValidateTree.
+type ValidateOptions struct {
+	// KnownIDs exist on the page outside the tree. Giving them enables
+	// reference checks for fragments.
+	KnownIDs []string
+
+	// SkipReferences turns off the reference checks.
+	SkipReferences bool
+}
+
+// ValidateOption configures ValidateOptions.
+type ValidateOption func(*ValidateOptions)
+
+// KnownIDs declares ids that exist outside the tree being validated.
+func KnownIDs(ids ...string) ValidateOption {
+	return func(o *ValidateOptions) {
+		o.KnownIDs = append(o.KnownIDs, ids...)
+	}
+}
+
+// SkipReferences turns off the checks of htmx and mintydyn references.
+func SkipReferences() ValidateOption {
+	return func(o *ValidateOptions) {
+		o.SkipReferences = true
+	}
+}
+
+// ValidateTree returns the content model, id and reference problems in
+// the tree under node, in document order.
+func ValidateTree(node Node, opts ...ValidateOption) []Issue {
+	o := ValidateOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	v := &validator{ids: make(map[string]string)}
+	complete := false
+	walk(node, nil, func(n Node, ancestors []*Element) bool {
+		el, ok := n.(*Element)
+		if !ok {
+			return true
+		}
+		if el.Tag == "html" {
+			complete = true
+		}
+		v.pos++
+		path := elementPath(append(ancestors[:len(ancestors):len(ancestors)], el))
+		v.checkID(el, path)
+		v.collectReferences(el, path)
+		if el.Tag == "svg" || el.Tag == "math" || el.Tag == "template" {
+			return false
+		}
+		v.checkContent(el, ancestors, path)
+		return true
+	})
+
+	if !o.SkipReferences && (complete || len(o.KnownIDs) > 0) {
+		for _, id := range o.KnownIDs {
+			if _, ok := v.ids[id]; !ok {
+				v.ids[id] = ""
+			}
+		}
+		for _, ref := range v.refs {
+			if _, ok := v.ids[ref.id]; !ok {
+				v.pos = ref.pos
+				v.add(ref.path, "%s refers to missing id %q", ref.source, ref.id)
+			}
+		}
+	}
+
+	// reference issues are found after the walk; put them in document order
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		return v.issues[i].pos < v.issues[j].pos
+	})
+	issues := make([]Issue, len(v.issues))
+	for i, issue := range v.issues {
+		issues[i] = issue.Issue
+	}
+	return issues
+}
+
+type validator struct {
+	ids    map[string]string // id to the path of its first element
+	refs   []reference
+	issues []positionedIssue
+	pos    int // document position of the current element
+}
+
+type positionedIssue struct {
+	Issue
+	pos int
+}
+
+// reference is an id named by an attribute or a mintydyn rule.
+type reference struct {
+	path, source, id string
+	pos              int
+}
+
+func (v *validator) add(path, format string, args ...interface{}) {
+	v.issues = append(v.issues, positionedIssue{Issue{Path: path, Message: fmt.Sprintf(format, args...)}, v.pos})
+}
+
+func (v *validator) checkID(el *Element, path string) {
+	id, ok := el.Attributes.Get("id")
+	if !ok {
+		return
+	}
+	switch first, seen := v.ids[id]; {
+	case strings.TrimSpace(id) == "":
+		v.add(path, "empty id attribute")
+	case seen:
+		v.add(path, "duplicate id %q, first used by %s", id, first)
+	default:
+		v.ids[id] = path
+	}
+}
+
+// htmxReferenceAttributes hold CSS selectors naming other elements.
+var htmxReferenceAttributes = []string{"hx-target", "hx-include", "hx-indicator"}
+
+func (v *validator) collectReferences(el *Element, path string) {
+	for _, name := range htmxReferenceAttributes {
+		value, ok := el.Attributes.Get(name)
+		if !ok {
+			continue
+		}
+		for _, selector := range strings.Split(value, ",") {
+			if id, ok := idSelector(selector); ok {
+				v.refs = append(v.refs, reference{path, name, id, v.pos})
+			}
+		}
+	}
+
+	// mintydyn writes its rules into a JSON config script and into
+	// data-dependent-rules attributes
+	var rules []struct {
+		ID      string `json:"id"`
+		Actions []struct {
+			TargetID string `json:"targetId"`
+		} `json:"actions"`
+	}
+	if value, ok := el.Attributes.Get("data-dependent-rules"); ok {
+		json.Unmarshal([]byte(value), &rules)
+	}
+	if typ, _ := el.Attributes.Get("type"); el.Tag == "script" && typ == "application/json" {
+		var config struct {
+			Rules json.RawMessage `json:"rules"`
+		}
+		if json.Unmarshal([]byte(TextContent(el)+rawContent(el)), &config) == nil && config.Rules != nil {
+			json.Unmarshal(config.Rules, &rules)
+		}
+	}
+	for _, rule := range rules {
+		for _, action := range rule.Actions {
+			if action.TargetID != "" {
+				v.refs = append(v.refs, reference{path, fmt.Sprintf("mintydyn rule %q", rule.ID), action.TargetID, v.pos})
+			}
+		}
+	}
+}
+
+// idSelector returns the id of a selector of the form #id.
+func idSelector(selector string) (string, bool) {
+	selector = strings.TrimSpace(selector)
+	if !strings.HasPrefix(selector, "#") || len(selector) == 1 {
+		return "", false
+	}
+	id := selector[1:]
+	if strings.ContainsAny(id, " .#[:>~+") {
+		return "", false
+	}
+	return id, true
+}
+
+// rawContent returns the raw text children of an element.
+func rawContent(el *Element) string {
+	var sb strings.Builder
+	for _, child := range el.Children {
+		if raw, ok := child.(*RawNode); ok {
+			sb.WriteString(raw.Content)
+		}
+	}
+	return sb.String()
+}
+
+func (v *validator) checkContent(el *Element, ancestors []*Element, path string) {
+	parent := contentParent(ancestors)
+
+	if parent != nil {
+		if phrasingOnly[parent.Tag] && !phrasing[el.Tag] {
+			v.add(path, "<%s> is not allowed in <%s>, which only holds phrasing content", el.Tag, parent.Tag)
+		}
+		if allowed, ok := allowedChildren[parent.Tag]; ok && !containsToken(allowed, el.Tag) {
+			v.add(path, "<%s> is not allowed as a child of <%s>", el.Tag, parent.Tag)
+		}
+	}
+	if parents, ok := requiredParents[el.Tag]; ok && len(ancestors) > 0 {
+		placed := containsToken(parents, ancestors[len(ancestors)-1].Tag)
+		if el.Tag == "dt" || el.Tag == "dd" {
+			placed = inDefinitionList(ancestors)
+		}
+		if !placed {
+			v.add(path, "<%s> must be a child of <%s>", el.Tag, strings.Join(parents, ">, <"))
+		}
+	}
+
+	for _, a := range ancestors {
+		if msg := forbiddenIn(el, a); msg != "" {
+			v.add(path, "%s", msg)
+			break
+		}
+	}
+}
+
+// forbiddenIn describes why el may not appear anywhere inside ancestor,
+// or returns "".
+func forbiddenIn(el, ancestor *Element) string {
+	switch {
+	case el.Tag == "form" && ancestor.Tag == "form":
+		return "<form> cannot be nested inside another <form>"
+	case el.Tag == "label" && ancestor.Tag == "label":
+		return "<label> cannot be nested inside another <label>"
+	case (ancestor.Tag == "a" || ancestor.Tag == "button") && interactive(el):
+		return fmt.Sprintf("interactive <%s> cannot be inside <%s>", el.Tag, ancestor.Tag)
+	case (el.Tag == "header" || el.Tag == "footer") && (ancestor.Tag == "header" || ancestor.Tag == "footer"):
+		return fmt.Sprintf("<%s> cannot be inside <%s>", el.Tag, ancestor.Tag)
+	}
+	return ""
+}
+
+// contentParent returns the nearest ancestor whose content model applies,
+// looking through transparent elements such as <a>.
+func contentParent(ancestors []*Element) *Element {
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if !transparent[ancestors[i].Tag] {
+			return ancestors[i]
+		}
+	}
+	return nil
+}
+
+// inDefinitionList reports whether dt or dd sits in a dl, directly or in
+// a div grouping inside one.
+func inDefinitionList(ancestors []*Element) bool {
+	n := len(ancestors)
+	switch {
+	case ancestors[n-1].Tag == "dl":
+		return true
+	case ancestors[n-1].Tag == "div" && n > 1:
+		return ancestors[n-2].Tag == "dl"
+	}
+	return false
+}
+
+// interactive reports whether el is interactive content.
+func interactive(el *Element) bool {
+	switch el.Tag {
+	case "a", "button", "select", "textarea", "label", "details", "embed", "iframe":
+		return true
+	case "input":
+		typ, _ := el.Attributes.Get("type")
+		return typ != "hidden"
+	case "audio", "video":
+		return el.Attributes.Has("controls")
+	}
+	return false
+}
+
+func tagSet(tags string) map[string]bool {
+	set := make(map[string]bool)
+	for _, tag := range strings.Fields(tags) {
+		set[tag] = true
+	}
+	return set
+}
+
+// phrasing is the phrasing content of HTML5.
+var phrasing = tagSet(`a abbr area audio b bdi bdo br button canvas cite code data datalist
+	del dfn em embed i iframe img input ins kbd label link map mark math meta meter noscript
+	object output picture progress q ruby s samp script select slot small span strong sub sup
+	svg template textarea time u var video wbr`)
+
+// phrasingOnly elements accept only phrasing content.
+var phrasingOnly = tagSet(`p h1 h2 h3 h4 h5 h6 span em strong small b i u s code abbr cite q
+	sub sup mark time var samp kbd dfn bdi bdo data label button output pre`)
+
+// transparent elements take the content model of their parent.
+var transparent = tagSet("a ins del map object noscript canvas audio video slot")
+
+// allowedChildren restricts the children of list, table and select
+// elements.
+var allowedChildren = map[string][]string{
+	"ul":       {"li", "script", "template"},
+	"ol":       {"li", "script", "template"},
+	"menu":     {"li", "script", "template"},
+	"dl":       {"dt", "dd", "div", "script", "template"},
+	"table":    {"caption", "colgroup", "thead", "tbody", "tfoot", "tr", "script", "template"},
+	"thead":    {"tr", "script", "template"},
+	"tbody":    {"tr", "script", "template"},
+	"tfoot":    {"tr", "script", "template"},
+	"tr":       {"td", "th", "script", "template"},
+	"select":   {"option", "optgroup", "hr", "script", "template"},
+	"optgroup": {"option", "script", "template"},
+	"colgroup": {"col", "template"},
+	"html":     {"head", "body"},
+	"head":     {"meta", "title", "link", "style", "script", "base", "noscript", "template"},
+}
+
+// requiredParents lists the parents an element may have.
+var requiredParents = map[string][]string{
+	"li":       {"ul", "ol", "menu"},
+	"dt":       {"dl"},
+	"dd":       {"dl"},
+	"tr":       {"table", "thead", "tbody", "tfoot"},
+	"td":       {"tr"},
+	"th":       {"tr"},
+	"option":   {"select", "datalist", "optgroup"},
+	"optgroup": {"select"},
+}
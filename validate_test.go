@@ -0,0 +1,95 @@
+package minty
+
+import (
+	"strings"
+	"testing"
+)
+
+func issueLines(issues []Issue) string {
+	lines := make([]string, len(issues))
+	for i, issue := range issues {
+		lines[i] = issue.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestValidateTreeContentModel(t *testing.T) {
+	b := B
+	tree := b.Main(
+		b.P(b.Div("block in a paragraph")),
+		b.Ul(b.Span("not an item"), b.Li("ok")),
+		b.Li("stray item"),
+		b.Form(ID("outer"), b.Form(ID("inner"))),
+		b.Button(b.A(Href("/"), "link")),
+		b.A(Href("/"), b.Div("block links are fine")),
+		b.P(b.A(Href("/"), b.Div("but not in a paragraph"))),
+		b.Table(b.Tr(b.Td("cell")), b.Div("row")),
+		b.Dl(b.Div(b.Dt("term"), b.Dd("definition"))),
+		b.Header(b.Footer()),
+		b.Svg(b.G(b.Rect())),
+	)
+
+	want := strings.Join([]string{
+		"main > p > div: <div> is not allowed in <p>, which only holds phrasing content",
+		"main > ul > span: <span> is not allowed as a child of <ul>",
+		"main > li: <li> must be a child of <ul>, <ol>, <menu>",
+		"main > form#outer > form#inner: <form> cannot be nested inside another <form>",
+		"main > button > a: interactive <a> cannot be inside <button>",
+		"main > p > a > div: <div> is not allowed in <p>, which only holds phrasing content",
+		"main > table > div: <div> is not allowed as a child of <table>",
+		"main > header > footer: <footer> cannot be inside <header>",
+	}, "\n")
+	if got := issueLines(ValidateTree(tree)); got != want {
+		t.Errorf("issues:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestValidateTreeIDs(t *testing.T) {
+	b := B
+	page := Document("Search", nil, b.Body(
+		b.Input(ID("q"), Name("q"), HtmxGet("/search"), HtmxTarget("#results"), HtmxInclude("#filters, [name='sort']")),
+		b.Div(ID("results")),
+		b.Div(ID("results")),
+		b.Span(ID("")),
+		b.Script(Type("application/json"), ID("form-config"),
+			Raw(`{"rules":[{"id":"show-spouse","actions":[{"targetId":"spouse-section","action":"show"}]}]}`)),
+	))(b)
+
+	want := strings.Join([]string{
+		`html > body > input#q: hx-include refers to missing id "filters"`,
+		`html > body > div#results: duplicate id "results", first used by html > body > div#results`,
+		`html > body > span: empty id attribute`,
+		`html > body > script#form-config: mintydyn rule "show-spouse" refers to missing id "spouse-section"`,
+	}, "\n")
+	if got := issueLines(ValidateTree(page)); got != want {
+		t.Errorf("issues:\n%s\nwant:\n%s", got, want)
+	}
+	if got := ValidateTree(page, SkipReferences()); len(got) != 2 {
+		t.Errorf("SkipReferences should leave the id issues, got:\n%s", issueLines(got))
+	}
+}
+
+func TestValidateTreeFragments(t *testing.T) {
+	b := B
+	row := b.Tr(b.Td(b.Button(HtmxDelete("/items/1"), HtmxTarget("#items"), "Delete")))
+
+	if got := ValidateTree(row); len(got) != 0 {
+		t.Errorf("fragments should not be checked for references or a parent, got:\n%s", issueLines(got))
+	}
+	got := ValidateTree(row, KnownIDs("list"))
+	if len(got) != 1 || got[0].Message != `hx-target refers to missing id "items"` {
+		t.Errorf("got:\n%s", issueLines(got))
+	}
+	if got := ValidateTree(row, KnownIDs("items")); len(got) != 0 {
+		t.Errorf("known ids should satisfy references, got:\n%s", issueLines(got))
+	}
+
+	parsed, err := ParseHTML(`<ul><li><a href="/"><button>x</button></a></li></ul>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = ValidateTree(parsed)
+	if len(got) != 1 || got[0].Path != "ul > li > a > button" {
+		t.Errorf("got:\n%s", issueLines(got))
+	}
+}
@@ -94,11 +94,15 @@ func ValidateMaxLength(value, fieldName string, maxLen int) *ValidationError {
 }
 
 // Form field helpers with validation support
+//
+// The helpers take ids for the field and its error message from b.UID, so
+// the same form can appear several times on a page. An error message is
+// linked to its field with aria-describedby.
 
 // FormField creates a form field with label, input, and error display.
 func FormField(label, name, fieldType, value, errorMsg string, required bool, attributes ...Attribute) H {
 	return func(b *Builder) Node {
-		attrs := []Attribute{Type(fieldType), Name(name)}
+		attrs := []Attribute{ID(b.UID(name)), Type(fieldType), Name(name)}
 		if value != "" {
 			attrs = append(attrs, Value(value))
 		}
@@ -107,14 +111,12 @@ func FormField(label, name, fieldType, value, errorMsg string, required bool, at
 		}
 		attrs = append(attrs, attributes...)
 		
-		var errorElement Node = NewFragment()
-		if errorMsg != "" {
-			errorElement = ErrorMessage(errorMsg)(b)
-		}
+		errorElement, describedBy := fieldError(b, name, errorMsg)
+		input := b.Input(append(attrs, describedBy...)...)
 		
 		return b.Div(Class("form-field"),
-			b.Label(For(name), label),
-			b.Input(attrs...),
+			b.Label(For(fieldID(input)), label),
+			input,
 			errorElement,
 		)
 	}
@@ -123,7 +125,8 @@ func FormField(label, name, fieldType, value, errorMsg string, required bool, at
 // TextareaField creates a textarea field with label and error display.
 func TextareaField(label, name, value, errorMsg string, required bool, rows, cols int) H {
 	return func(b *Builder) Node {
-		attrs := []interface{}{Name(name)}
+		id := b.UID(name)
+		attrs := []interface{}{ID(id), Name(name)}
 		if required {
 			attrs = append(attrs, Required())
 		}
@@ -133,15 +136,15 @@ func TextareaField(label, name, value, errorMsg string, required bool, rows, col
 		if cols > 0 {
 			attrs = append(attrs, Cols(cols))
 		}
-		attrs = append(attrs, value) // Text content
 		
-		var errorElement Node = NewFragment()
-		if errorMsg != "" {
-			errorElement = ErrorMessage(errorMsg)(b)
+		errorElement, describedBy := fieldError(b, name, errorMsg)
+		for _, attr := range describedBy {
+			attrs = append(attrs, attr)
 		}
+		attrs = append(attrs, value) // Text content
 		
 		return b.Div(Class("form-field"),
-			b.Label(For(name), label),
+			b.Label(For(id), label),
 			b.Textarea(attrs...),
 			errorElement,
 		)
@@ -151,11 +154,17 @@ func TextareaField(label, name, value, errorMsg string, required bool, rows, col
 // SelectField creates a select field with options.
 func SelectField(label, name, value, errorMsg string, required bool, options []SelectOption) H {
 	return func(b *Builder) Node {
-		selectAttrs := []interface{}{Name(name)}
+		id := b.UID(name)
+		selectAttrs := []interface{}{ID(id), Name(name)}
 		if required {
 			selectAttrs = append(selectAttrs, Required())
 		}
 		
+		errorElement, describedBy := fieldError(b, name, errorMsg)
+		for _, attr := range describedBy {
+			selectAttrs = append(selectAttrs, attr)
+		}
+		
 		for _, opt := range options {
 			optAttrs := []interface{}{Value(opt.Value), opt.Text}
 			if opt.Value == value {
@@ -164,19 +173,35 @@ func SelectField(label, name, value, errorMsg string, required bool, options []S
 			selectAttrs = append(selectAttrs, b.Option(optAttrs...))
 		}
 		
-		var errorElement Node = NewFragment()
-		if errorMsg != "" {
-			errorElement = ErrorMessage(errorMsg)(b)
-		}
-		
 		return b.Div(Class("form-field"),
-			b.Label(For(name), label),
+			b.Label(For(id), label),
 			b.Select(selectAttrs...),
 			errorElement,
 		)
 	}
 }
 
+// fieldError builds the error message for a field, if any, and the
+// attributes linking the field to it.
+func fieldError(b *Builder, name, errorMsg string) (Node, []Attribute) {
+	if errorMsg == "" {
+		return NewFragment(), nil
+	}
+	id := b.UID(name + "-error")
+	message := Decorate(ErrorMessage(errorMsg), ID(id))(b)
+	return message, []Attribute{Attr("aria-invalid", "true"), AriaDescribedby(id)}
+}
+
+// fieldID returns the id of a field element, which attributes passed by
+// the caller may have replaced.
+func fieldID(field Node) string {
+	if el, ok := field.(*Element); ok {
+		id, _ := el.GetAttribute("id")
+		return id
+	}
+	return ""
+}
+
 // SelectOption represents an option in a select field.
 type SelectOption struct {
 	Value string